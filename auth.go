@@ -10,18 +10,28 @@ import (
 	"github.com/gin-gonic/gin/internal/bytesconv"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // 用户auth名称
 const AuthUserKey = "user"
 
-// 授权登录的use/pwd键值对
+// 授权登录的use/pwd键值对，password可以是bcrypt（$2a$、$2b$、$2y$开头）或者argon2（$argon2id$、$argon2i$开头）的哈希，
+// 配置中不需要保存明文密码。
+//
+// 包含哈希时，每个携带Basic认证的请求（包括用户名不存在的请求）都会计算一次哈希，
+// argon2按照参数中的m分配内存，并发较高时会消耗大量CPU和内存，
+// 需要选择合适的参数，并在认证之前使用限流或者限制并发的中间件
 type Accounts map[string]string
 
 // 授权对
 type authPair struct {
 	value string
 	user  string
+	// password为哈希时校验密码，此时不使用value
+	verify passwordVerifier
 }
 
 type authPairs []authPair
@@ -30,31 +40,108 @@ func (a authPairs) searchCredential(authValue string) (string, bool) {
 	if authValue == "" {
 		return "", false
 	}
+	var user, password string
+	parsed, ok := false, false
+	// 密码为哈希时匹配的用户，以及用户不存在时用于校验的哈希
+	var matched *authPair
+	var dummy passwordVerifier
 	// 检索authValue是否存在
-	for _, pair := range a {
-		if subtle.ConstantTimeCompare(bytesconv.StringToBytes(pair.value), bytesconv.StringToBytes(authValue)) == 1 {
-			return pair.user, true
+	for i, pair := range a {
+		if pair.verify == nil {
+			if subtle.ConstantTimeCompare(bytesconv.StringToBytes(pair.value), bytesconv.StringToBytes(authValue)) == 1 {
+				return pair.user, true
+			}
+			continue
+		}
+		if dummy == nil {
+			dummy = pair.verify
+		}
+		// 密码为哈希时解码header，比较全部的用户名之后再校验密码
+		if !parsed {
+			user, password, ok = parseBasicAuth(authValue)
+			parsed = true
 		}
+		if ok && subtle.ConstantTimeCompare(bytesconv.StringToBytes(pair.user), bytesconv.StringToBytes(user)) == 1 {
+			matched = &a[i]
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	// 用户不存在时同样校验一次哈希，避免通过响应时间判断用户名是否存在
+	if matched == nil {
+		dummy(password)
+		return "", false
+	}
+	if matched.verify(password) {
+		return matched.user, true
 	}
 	return "", false
 }
 
+// 解码Basic认证的header，返回user和password
+func parseBasicAuth(authValue string) (user, password string, ok bool) {
+	const prefix = "Basic "
+	if len(authValue) < len(prefix) || !strings.EqualFold(authValue[:len(prefix)], prefix) {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(authValue[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+	user, password, ok = strings.Cut(string(decoded), ":")
+	return
+}
+
+// BasicAuthOptions BasicAuthWithOptions的配置
+type BasicAuthOptions struct {
+	// Basic realm的值，默认为Authorization Required
+	Realm string
+
+	// 为true时按照RFC 7617在challenge中添加charset="UTF-8"，
+	// 配置的以及请求中的用户名和密码使用Unicode NFC规范化之后比较，非ASCII的用户名和密码可以正常使用
+	UTF8 bool
+
+	// 认证失败时写入响应，调用之前已经设置了WWW-Authenticate header和401，默认只返回401
+	Unauthorized func(c *Context)
+}
+
 // 基础的HTTP Authorization中间件，accounts是一个key为user，value为password的map,realm为Basic realm的值
 func BasicAuthForRealm(accounts Accounts, realm string) HandlerFunc {
+	return BasicAuthWithOptions(accounts, BasicAuthOptions{Realm: realm})
+}
+
+// 通过配置返回HTTP Basic Authorization中间件
+func BasicAuthWithOptions(accounts Accounts, opts BasicAuthOptions) HandlerFunc {
+	realm := opts.Realm
 	// 默认为Authorization Required
 	if realm == "" {
 		realm = "Authorization Required"
 	}
-	realm = "Basic realm=" + strconv.Quote(realm)
+	challenge := "Basic realm=" + strconv.Quote(realm)
+	if opts.UTF8 {
+		challenge += `, charset="UTF-8"`
+		accounts = normalizeAccounts(accounts)
+	}
 	// 处理为authPairs类型
 	pairs := processAccounts(accounts)
 	return func(c *Context) {
+		authValue := c.requestHeader("Authorization")
+		if opts.UTF8 {
+			authValue = normalizeAuthorization(authValue)
+		}
 		// 查找request中的Authorization header
-		user, found := pairs.searchCredential(c.requestHeader("Authorization"))
+		user, found := pairs.searchCredential(authValue)
 		if !found {
 			// 未找到Authorization header，返回401，并且中断请求
-			c.Header("WWW-Authenticate", realm)
-			c.AbortWithStatus(http.StatusUnauthorized)
+			c.Header("WWW-Authenticate", challenge)
+			if opts.Unauthorized == nil {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			c.Abort()
+			c.Status(http.StatusUnauthorized)
+			opts.Unauthorized(c)
 			return
 		}
 
@@ -68,6 +155,35 @@ func BasicAuth(accounts Accounts) HandlerFunc {
 	return BasicAuthForRealm(accounts, "")
 }
 
+// 返回使用HTTP Basic Authorization的RouterGroup，base path与group相同，
+// 不同的路由组可以使用不同的realm和accounts
+//
+//	admin := router.Group("/admin").WithBasicAuth(adminAccounts, gin.BasicAuthOptions{Realm: "admin"})
+func (group *RouterGroup) WithBasicAuth(accounts Accounts, opts BasicAuthOptions) *RouterGroup {
+	return group.Group("", BasicAuthWithOptions(accounts, opts))
+}
+
+// 使用NFC规范化用户名和密码，密码为哈希时不修改
+func normalizeAccounts(accounts Accounts) Accounts {
+	normalized := make(Accounts, len(accounts))
+	for user, password := range accounts {
+		if verify, err := parsePasswordHash(password); verify == nil && err == nil {
+			password = norm.NFC.String(password)
+		}
+		normalized[norm.NFC.String(user)] = password
+	}
+	return normalized
+}
+
+// 使用NFC规范化Authorization header中的用户名和密码，无法解码时返回原值
+func normalizeAuthorization(authValue string) string {
+	user, password, ok := parseBasicAuth(authValue)
+	if !ok {
+		return authValue
+	}
+	return authorizationHeader(norm.NFC.String(user), norm.NFC.String(password))
+}
+
 // 将Accounts中的map转换为authPairs类型
 func processAccounts(accounts Accounts) authPairs {
 	// 校验是否为空
@@ -77,6 +193,14 @@ func processAccounts(accounts Accounts) authPairs {
 	// 转换Accounts
 	for user, password := range accounts {
 		assert1(user != "", "User can not be empty")
+		verify, err := parsePasswordHash(password)
+		if err != nil {
+			panic("invalid password hash for user " + strconv.Quote(user) + ": " + err.Error())
+		}
+		if verify != nil {
+			pairs = append(pairs, authPair{user: user, verify: verify})
+			continue
+		}
 		// 使用authorizationHeader生成user和password的value
 		value := authorizationHeader(user, password)
 		pairs = append(pairs, authPair{
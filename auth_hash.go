@@ -0,0 +1,77 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 校验密码是否与哈希匹配
+type passwordVerifier func(password string) bool
+
+// 根据前缀识别密码哈希：bcrypt（$2a$、$2b$、$2y$）以及PHC格式的argon2id和argon2i，
+// 不是哈希时返回nil，哈希格式不正确时返回错误
+func parsePasswordHash(s string) (passwordVerifier, error) {
+	switch {
+	case strings.HasPrefix(s, "$2a$"), strings.HasPrefix(s, "$2b$"), strings.HasPrefix(s, "$2y$"):
+		hash := []byte(s)
+		if _, err := bcrypt.Cost(hash); err != nil {
+			return nil, err
+		}
+		return func(password string) bool {
+			return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+		}, nil
+	case strings.HasPrefix(s, "$argon2id$"), strings.HasPrefix(s, "$argon2i$"):
+		return parseArgon2Hash(s)
+	}
+	return nil, nil
+}
+
+// 解析$argon2id$v=19$m=65536,t=3,p=4$salt$hash格式的哈希，salt和hash为不带填充的base64
+func parseArgon2Hash(s string) (passwordVerifier, error) {
+	parts := strings.Split(s, "$")
+	if len(parts) != 6 {
+		return nil, errors.New("invalid argon2 hash format")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, fmt.Errorf("invalid argon2 version: %w", err)
+	}
+	if version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid argon2 hash: %w", err)
+	}
+	if len(hash) == 0 || time == 0 || threads == 0 {
+		return nil, errors.New("invalid argon2 parameters")
+	}
+
+	key := argon2.IDKey
+	if parts[1] == "argon2i" {
+		key = argon2.Key
+	}
+	return func(password string) bool {
+		computed := key([]byte(password), salt, time, memory, threads, uint32(len(hash)))
+		return subtle.ConstantTimeCompare(computed, hash) == 1
+	}, nil
+}
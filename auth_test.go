@@ -11,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestBasicAuth(t *testing.T) {
@@ -137,3 +139,150 @@ func TestBasicAuth401WithCustomRealm(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Equal(t, "Basic realm=\"My Custom \\\"Realm\\\"\"", w.Header().Get("WWW-Authenticate"))
 }
+
+func TestBasicAuthHashedPassword(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("bcrypt-secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	salt := []byte("0123456789abcdef")
+	argon2Hash := "$argon2id$v=19$m=64,t=1,p=1$" + base64.RawStdEncoding.EncodeToString(salt) + "$" +
+		base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte("argon2-secret"), salt, 1, 64, 1, 32))
+	argon2iHash := "$argon2i$v=19$m=64,t=1,p=1$" + base64.RawStdEncoding.EncodeToString(salt) + "$" +
+		base64.RawStdEncoding.EncodeToString(argon2.Key([]byte("argon2i-secret"), salt, 1, 64, 1, 32))
+
+	pairs := processAccounts(Accounts{
+		"bcrypt":  string(bcryptHash),
+		"argon2":  argon2Hash,
+		"argon2i": argon2iHash,
+		"plain":   "plain-secret",
+	})
+
+	for user, password := range map[string]string{
+		"bcrypt":  "bcrypt-secret",
+		"argon2":  "argon2-secret",
+		"argon2i": "argon2i-secret",
+		"plain":   "plain-secret",
+	} {
+		found, ok := pairs.searchCredential(authorizationHeader(user, password))
+		assert.True(t, ok, user)
+		assert.Equal(t, user, found)
+
+		_, ok = pairs.searchCredential(authorizationHeader(user, password+"x"))
+		assert.False(t, ok, user)
+	}
+
+	// 哈希不能作为密码使用
+	_, ok := pairs.searchCredential(authorizationHeader("bcrypt", string(bcryptHash)))
+	assert.False(t, ok)
+	_, ok = pairs.searchCredential(authorizationHeader("argon2", "bcrypt-secret"))
+	assert.False(t, ok)
+	_, ok = pairs.searchCredential("Basic !!!")
+	assert.False(t, ok)
+	_, ok = pairs.searchCredential("Bearer token")
+	assert.False(t, ok)
+}
+
+func TestBasicAuthHashedPasswordUnknownUser(t *testing.T) {
+	calls := map[string]int{}
+	verifier := func(user string) passwordVerifier {
+		return func(password string) bool {
+			calls[user]++
+			return password == user+"-secret"
+		}
+	}
+	pairs := authPairs{
+		{user: "plain", value: authorizationHeader("plain", "plain-secret")},
+		{user: "alice", verify: verifier("alice")},
+		{user: "bob", verify: verifier("bob")},
+	}
+
+	user, ok := pairs.searchCredential(authorizationHeader("bob", "bob-secret"))
+	assert.True(t, ok)
+	assert.Equal(t, "bob", user)
+	assert.Equal(t, map[string]int{"bob": 1}, calls)
+
+	// 用户不存在时同样校验一次哈希
+	_, ok = pairs.searchCredential(authorizationHeader("mallory", "bob-secret"))
+	assert.False(t, ok)
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, calls)
+
+	_, ok = pairs.searchCredential(authorizationHeader("alice", "bob-secret"))
+	assert.False(t, ok)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, calls)
+
+	// 明文密码和无法解码的header不计算哈希
+	_, ok = pairs.searchCredential(authorizationHeader("plain", "plain-secret"))
+	assert.True(t, ok)
+	_, ok = pairs.searchCredential("Bearer token")
+	assert.False(t, ok)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, calls)
+}
+
+func TestBasicAuthInvalidHash(t *testing.T) {
+	for _, hash := range []string{
+		"$2a$10$short",
+		"$argon2id$v=19$m=64,t=1,p=1$salt",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaA",
+	} {
+		assert.Panics(t, func() {
+			processAccounts(Accounts{"user": hash})
+		}, hash)
+	}
+}
+
+func TestBasicAuthWithOptions(t *testing.T) {
+	router := New()
+	router.Use(BasicAuthWithOptions(Accounts{"José": "paßword"}, BasicAuthOptions{
+		Realm: "My Custom \"Realm\"",
+		UTF8:  true,
+		Unauthorized: func(c *Context) {
+			c.JSON(c.Writer.Status(), H{"error": "unauthorized"})
+		},
+	}))
+	router.GET("/login", func(c *Context) {
+		c.String(http.StatusOK, c.MustGet(AuthUserKey).(string))
+	})
+
+	// 请求中使用分解形式的é，NFC规范化之后匹配
+	w := PerformRequest(router, http.MethodGet, "/login",
+		header{Key: "Authorization", Value: authorizationHeader("Jose\u0301", "paßword")})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "José", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/login",
+		header{Key: "Authorization", Value: authorizationHeader("José", "password")})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="My Custom \"Realm\"", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, `{"error":"unauthorized"}`, w.Body.String())
+}
+
+func TestBasicAuthWithOptionsDefault(t *testing.T) {
+	router := New()
+	router.Use(BasicAuthWithOptions(Accounts{"admin": "password"}, BasicAuthOptions{}))
+	router.GET("/login", func(c *Context) {})
+
+	w := PerformRequest(router, http.MethodGet, "/login")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="Authorization Required"`, w.Header().Get("WWW-Authenticate"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestRouterGroupWithBasicAuth(t *testing.T) {
+	router := New()
+	admin := router.Group("/admin").WithBasicAuth(Accounts{"admin": "secret"}, BasicAuthOptions{Realm: "admin"})
+	admin.GET("/users", func(c *Context) {})
+	api := router.Group("/api").WithBasicAuth(Accounts{"bot": "token"}, BasicAuthOptions{Realm: "api"})
+	api.GET("/users", func(c *Context) {})
+
+	assert.Equal(t, "/admin", admin.BasePath())
+	w := PerformRequest(router, http.MethodGet, "/admin/users")
+	assert.Equal(t, `Basic realm="admin"`, w.Header().Get("WWW-Authenticate"))
+	w = PerformRequest(router, http.MethodGet, "/api/users",
+		header{Key: "Authorization", Value: authorizationHeader("admin", "secret")})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="api"`, w.Header().Get("WWW-Authenticate"))
+	w = PerformRequest(router, http.MethodGet, "/api/users",
+		header{Key: "Authorization", Value: authorizationHeader("bot", "token")})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
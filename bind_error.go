@@ -0,0 +1,84 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// BindErrorConfig Bind、BindJSON等方法（MustBindWith）绑定失败时的处理，零值只设置400并记录错误
+type BindErrorConfig struct {
+	// 请求无法解码时的status code，例如JSON语法错误或者类型不匹配，默认为400
+	DecodeStatus int
+
+	// 请求可以解码但是校验失败时的status code，默认为400，可以设置为422（http.StatusUnprocessableEntity）
+	ValidationStatus int
+
+	// 渲染错误时内容协商提供的格式，格式与ErrorRenderer相同：{"errors":[{"error":"..."}]}；
+	// 为空时不写入body，可以由ErrorRenderer等middleware处理
+	Offered []string
+
+	// 设置后替代默认的渲染，Offered为空时同样调用
+	Render func(c *Context, code int, err error)
+}
+
+// 返回绑定错误对应的status code：校验失败时为ValidationStatus，其他为DecodeStatus
+func (c *Context) bindErrorStatus(err error) int {
+	var conf BindErrorConfig
+	if c.engine != nil {
+		conf = c.engine.BindErrors
+	}
+	code := conf.DecodeStatus
+	if errors.Is(err, binding.ErrValidation) {
+		code = conf.ValidationStatus
+	}
+	if code == 0 {
+		code = http.StatusBadRequest
+	}
+	return code
+}
+
+// 返回HandlerOf和WrapResult中绑定错误的status code：不支持的Content-Type返回415，body太大返回413，
+// 其他错误与bindErrorStatus相同
+func (c *Context) handlerBindErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, binding.ErrUnknownContentType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, binding.ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return c.bindErrorStatus(err)
+}
+
+// 绑定失败时记录错误并调用Abort，根据Engine.BindErrors设置status code和渲染错误
+func (c *Context) abortWithBindError(err error) {
+	code := c.bindErrorStatus(err)
+	c.AbortWithError(code, err).SetType(ErrorTypeBind) //nolint: errcheck
+	c.renderBindError(code, err, nil)
+}
+
+// 根据Engine.BindErrors渲染绑定错误，没有配置时使用fallback提供的格式，fallback为空时不渲染
+func (c *Context) renderBindError(code int, err error, fallback []string) {
+	var conf BindErrorConfig
+	if c.engine != nil {
+		conf = c.engine.BindErrors
+	}
+	if conf.Render != nil {
+		conf.Render(c, code, err)
+		return
+	}
+	offered := conf.Offered
+	if len(offered) == 0 {
+		offered = fallback
+	}
+	if len(offered) == 0 {
+		return
+	}
+	e := &Error{Err: err, Type: ErrorTypeBind}
+	c.Negotiate(code, Negotiate{Offered: offered, Data: H{"errors": []any{e.JSON()}}})
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindErrorRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// 使用JSON body请求router
+func performBindError(router *Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", MIMEJSON)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBindErrorsDefault(t *testing.T) {
+	router := New()
+	router.POST("/", func(c *Context) {
+		var req bindErrorRequest
+		if c.BindJSON(&req) == nil {
+			c.String(http.StatusOK, req.Name)
+		}
+	})
+
+	w := performBindError(router, `{"name":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = performBindError(router, `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestBindErrorsStatus(t *testing.T) {
+	router := New()
+	router.BindErrors = BindErrorConfig{
+		ValidationStatus: http.StatusUnprocessableEntity,
+		Offered:          []string{binding.MIMEJSON},
+	}
+	var errs []*Error
+	router.POST("/", func(c *Context) {
+		var req bindErrorRequest
+		if c.BindJSON(&req) != nil {
+			errs = c.Errors
+			return
+		}
+		c.String(http.StatusOK, req.Name)
+	})
+
+	w := performBindError(router, `{"name":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"unexpected EOF"}]}`, w.Body.String())
+	assert.True(t, errs[0].IsType(ErrorTypeBind))
+
+	w = performBindError(router, `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `{"errors":[{"error":"Key: 'bindErrorRequest.Name'`)
+
+	w = performBindError(router, `{"name":"gin"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 自定义渲染
+	router.BindErrors = BindErrorConfig{
+		DecodeStatus: http.StatusNotAcceptable,
+		Render: func(c *Context, code int, err error) {
+			c.String(code, "bad request: %v", err)
+		},
+	}
+	w = performBindError(router, `{"name":`)
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, "bad request: unexpected EOF", w.Body.String())
+}
+
+func TestBindErrorsUri(t *testing.T) {
+	router := New()
+	router.BindErrors.ValidationStatus = http.StatusUnprocessableEntity
+	router.GET("/:id", func(c *Context) {
+		var req struct {
+			ID int `uri:"id" binding:"min=10"`
+		}
+		_ = c.BindUri(&req)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = PerformRequest(router, http.MethodGet, "/1")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestBindErrorsHandlerOf(t *testing.T) {
+	router := New()
+	router.BindErrors.ValidationStatus = http.StatusUnprocessableEntity
+	router.POST("/", HandlerOf(func(c *Context, req bindErrorRequest) {
+		c.String(http.StatusOK, req.Name)
+	}))
+
+	w := performBindError(router, `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `{"errors":[{"error":"Key: 'bindErrorRequest.Name'`)
+	w = performBindError(router, `{"name":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -6,7 +6,10 @@
 
 package binding
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // 常见的Content-Type类型
 const (
@@ -44,6 +47,18 @@ type BindingUri interface {
 	BindUri(map[string][]string, any) error
 }
 
+// BindingBodyCtx 是可选的接口，与BindBody相同，同时将ctx传递给validator
+type BindingBodyCtx interface {
+	BindingBody
+	BindBodyCtx(ctx context.Context, body []byte, obj any) error
+}
+
+// BindingUriCtx 是可选的接口，与BindUri相同，同时将ctx传递给validator
+type BindingUriCtx interface {
+	BindingUri
+	BindUriCtx(ctx context.Context, m map[string][]string, obj any) error
+}
+
 // StructValidator提供最小的函数接口，用来实现validator，确保请求的正确性
 type StructValidator interface {
 	// ValidateStruct接受any类型，但是只会处理结构体、指针和指向指针的类型（Slice和Array）
@@ -53,6 +68,12 @@ type StructValidator interface {
 	Engine() any
 }
 
+// ContextValidator是可选的接口，Validator实现该接口时，绑定请求会携带request的context进行校验，
+// 可以用于唯一性检查、租户规则等需要按请求查询并支持取消的校验
+type ContextValidator interface {
+	ValidateStructCtx(ctx context.Context, obj any) error
+}
+
 // defaultValidator是默认的validator，实现了StructValidator接口
 var Validator StructValidator = &defaultValidator{}
 
@@ -107,3 +128,33 @@ func validate(obj any) error {
 
 	return Validator.ValidateStruct(obj)
 }
+
+// context中保存validator的key
+type validatorCtxKey struct{}
+
+// ContextWithValidator返回携带v的context，通过该context绑定请求时使用v代替全局的Validator进行校验，
+// v为nil时不进行校验
+func ContextWithValidator(ctx context.Context, v StructValidator) context.Context {
+	return context.WithValue(ctx, validatorCtxKey{}, &v)
+}
+
+// Validate使用ctx中通过ContextWithValidator设置的validator或者全局的Validator校验obj，
+// 用于通过多个binding绑定同一个obj之后统一校验，校验失败的错误为ErrValidation
+func Validate(ctx context.Context, obj any) error {
+	return validateCtx(ctx, obj)
+}
+
+// 携带context校验，Validator没有实现ContextValidator时等同于validate
+func validateCtx(ctx context.Context, obj any) error {
+	v := Validator
+	if override, ok := ctx.Value(validatorCtxKey{}).(*StructValidator); ok {
+		v = *override
+	}
+	if v == nil {
+		return nil
+	}
+	if cv, ok := v.(ContextValidator); ok {
+		return validationError(cv.ValidateStructCtx(ctx, obj))
+	}
+	return validationError(v.ValidateStruct(obj))
+}
@@ -791,6 +791,50 @@ func TestHeaderBinding(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestHeaderBindingSlice(t *testing.T) {
+	var obj struct {
+		IDs    []int    `header:"X-Ids" collection_format:"csv"`
+		Accept []string `header:"Accept" collection_format:"csv"`
+		Pipes  []int    `header:"X-Pipes" collection_format:"pipes"`
+		Values []string `header:"X-Values"`
+	}
+	req := requestWithBody("GET", "/", "")
+	req.Header.Add("X-Ids", "1")
+	req.Header.Add("X-Ids", "2, 3")
+	req.Header.Add("Accept", "text/html, application/json")
+	req.Header.Add("X-Pipes", "1|2")
+	req.Header.Add("X-Values", "a, b")
+	req.Header.Add("X-Values", "c")
+	assert.NoError(t, Header.Bind(req, &obj))
+	assert.Equal(t, []int{1, 2, 3}, obj.IDs)
+	assert.Equal(t, []string{"text/html", "application/json"}, obj.Accept)
+	assert.Equal(t, []int{1, 2}, obj.Pipes)
+	// 未指定collection_format时不拆分
+	assert.Equal(t, []string{"a, b", "c"}, obj.Values)
+
+	req = requestWithBody("GET", "/", "")
+	req.Header.Add("X-Ids", "1, wrong")
+	assert.Error(t, Header.Bind(req, &obj))
+}
+
+func TestHeaderBindingCaseInsensitive(t *testing.T) {
+	var obj struct {
+		RequestID string `header:"x_request_id"`
+	}
+	req := requestWithBody("GET", "/", "")
+	req.Header["X_Request_ID"] = []string{"abc"}
+
+	assert.NoError(t, Header.Bind(req, &obj))
+	assert.Equal(t, "", obj.RequestID)
+
+	EnableHeaderCaseInsensitive = true
+	defer func() {
+		EnableHeaderCaseInsensitive = false
+	}()
+	assert.NoError(t, Header.Bind(req, &obj))
+	assert.Equal(t, "abc", obj.RequestID)
+}
+
 func TestUriBinding(t *testing.T) {
 	b := Uri
 	assert.Equal(t, "uri", b.Name())
@@ -1242,6 +1286,9 @@ func testBodyBindingStringMap(t *testing.T, b Binding, path, badPath, body, badB
 
 	objInt := make(map[string]int)
 	req = requestWithBody("POST", path, body)
+	if b.Name() == "form" {
+		req.Header.Add("Content-Type", MIMEPOSTForm)
+	}
 	err = b.Bind(req, &objInt)
 	assert.Error(t, err)
 }
@@ -5,6 +5,7 @@
 package binding
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -48,10 +49,18 @@ func (err SliceValidationError) Error() string {
 }
 
 // 接口实现校验
-var _ StructValidator = (*defaultValidator)(nil)
+var (
+	_ StructValidator  = (*defaultValidator)(nil)
+	_ ContextValidator = (*defaultValidator)(nil)
+)
 
 // ValidateStruct接受any类型，但是只会处理结构体、指针和指向指针的类型（Slice和Array）
 func (v *defaultValidator) ValidateStruct(obj any) error {
+	return v.ValidateStructCtx(context.Background(), obj)
+}
+
+// ValidateStructCtx与ValidateStruct相同，ctx会传递给validator中注册的context校验函数
+func (v *defaultValidator) ValidateStructCtx(ctx context.Context, obj any) error {
 	if obj == nil {
 		return nil
 	}
@@ -62,16 +71,16 @@ func (v *defaultValidator) ValidateStruct(obj any) error {
 	switch value.Kind() {
 	case reflect.Ptr:
 		// 递归校验Ptr的值
-		return v.ValidateStruct(value.Elem().Interface())
+		return v.ValidateStructCtx(ctx, value.Elem().Interface())
 	case reflect.Struct:
-		return v.validateStruct(obj)
+		return v.validateStruct(ctx, obj)
 	case reflect.Slice, reflect.Array:
 		count := value.Len()
 		// 类型为Slice和Array，创建等长的SliceValidationError记录校验错误
 		validateRet := make(SliceValidationError, 0)
 		for i := 0; i < count; i++ {
 			// 递归校验对应index的值
-			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
+			if err := v.ValidateStructCtx(ctx, value.Index(i).Interface()); err != nil {
 				validateRet = append(validateRet, err)
 			}
 		}
@@ -85,11 +94,15 @@ func (v *defaultValidator) ValidateStruct(obj any) error {
 }
 
 // validateStruct校验struct类型
-func (v *defaultValidator) validateStruct(obj any) error {
+func (v *defaultValidator) validateStruct(ctx context.Context, obj any) error {
+	// context已经取消时不再校验
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	// 获取v.validate单例
 	v.lazyinit()
 	// 使用validate校验struct类型
-	return v.validate.Struct(obj)
+	return v.validate.StructCtx(ctx, obj)
 }
 
 // 返货默认的validator engine
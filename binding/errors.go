@@ -0,0 +1,105 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 绑定失败的错误类型，可以通过errors.Is判断绑定在哪个阶段失败，例如映射为400/415/413/422
+var (
+	// JSON body有语法错误或者不完整
+	ErrJSONSyntax = errors.New("binding: invalid json")
+
+	// body、form、query、header、uri等无法解码或者映射到obj，例如JSON字段类型不匹配、未知字段
+	ErrDecode = errors.New("binding: can not decode request")
+
+	// 不支持的Content-Type
+	ErrUnknownContentType = errors.New("binding: unknown content type")
+
+	// obj校验失败
+	ErrValidation = errors.New("binding: validation failed")
+
+	// body超过了允许的大小
+	ErrBodyTooLarge = errors.New("binding: request body too large")
+
+	// JSON body超过了JSONDecoderMaxDepth或者JSONDecoderMaxElements的限制
+	ErrJSONLimit = errors.New("binding: json limit exceeded")
+
+	// XML body超过了XMLDecoderMaxDepth、XMLDecoderMaxTokenSize的限制或者包含了不允许的指令
+	ErrXMLLimit = errors.New("binding: xml limit exceeded")
+)
+
+// Error 绑定失败时返回的错误，Binding为失败的binding名称，Kind为上面的错误类型之一，Err为原始错误
+// Error()返回原始错误的内容，errors.Is/errors.As同时匹配Kind和Err，
+// 例如errors.As(err, &validator.ValidationErrors{})仍然可用
+type Error struct {
+	Binding string
+	Kind    error
+	Err     error
+}
+
+// 接口实现校验
+var _ error = (*Error)(nil)
+
+// 返回原始错误的内容
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// 支持errors.Is(err, ErrValidation)以及匹配原始错误
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// 将绑定过程中的错误包装为*Error，已经包装过的错误只补充binding名称，
+// 读取body超过http.MaxBytesReader限制的错误为ErrBodyTooLarge，其他错误为decodeKind
+func wrapError(name string, decodeKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	var bindErr *Error
+	if errors.As(err, &bindErr) {
+		if bindErr.Binding == "" {
+			bindErr.Binding = name
+		}
+		return err
+	}
+	kind := decodeKind
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		kind = ErrBodyTooLarge
+	case errors.Is(err, ErrJSONLimit):
+		kind = ErrJSONLimit
+	case errors.Is(err, ErrXMLLimit):
+		kind = ErrXMLLimit
+	}
+	return &Error{Binding: name, Kind: kind, Err: err}
+}
+
+// 包装校验失败的错误
+func validationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: ErrValidation, Err: err}
+}
+
+// 不支持的Content-Type
+func unknownContentTypeError(contentType string) error {
+	return &Error{Kind: ErrUnknownContentType, Err: fmt.Errorf("unknown content type %q", contentType)}
+}
+
+// Lookup 与Default相同，但是Content-Type不受支持时返回ErrUnknownContentType，而不是使用Form
+func Lookup(method, contentType string) (Binding, error) {
+	b := Default(method, contentType)
+	if b == Form && method != http.MethodGet && contentType != MIMEPOSTForm && contentType != "" {
+		return nil, unknownContentTypeError(contentType)
+	}
+	return b, nil
+}
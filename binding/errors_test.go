@@ -0,0 +1,107 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindingErrorKinds(t *testing.T) {
+	type obj struct {
+		Foo int `json:"foo" form:"foo" binding:"required"`
+	}
+
+	// json syntax
+	err := JSON.Bind(requestWithBody("POST", "/", `{"foo":`), &obj{})
+	var bindErr *Error
+	assert.ErrorAs(t, err, &bindErr)
+	assert.Equal(t, "json", bindErr.Binding)
+	assert.ErrorIs(t, err, ErrJSONSyntax)
+	assert.Equal(t, "unexpected EOF", err.Error())
+
+	err = JSON.Bind(requestWithBody("POST", "/", `{"foo":x}`), &obj{})
+	assert.ErrorIs(t, err, ErrJSONSyntax)
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+
+	// json type mismatch
+	err = JSON.Bind(requestWithBody("POST", "/", `{"foo":"bar"}`), &obj{})
+	assert.ErrorIs(t, err, ErrDecode)
+	assert.False(t, errors.Is(err, ErrJSONSyntax))
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+
+	// validation keeps the original validator errors
+	err = JSON.Bind(requestWithBody("POST", "/", `{}`), &obj{})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.ErrorAs(t, err, &bindErr)
+	assert.Equal(t, "json", bindErr.Binding)
+	assert.False(t, errors.Is(err, ErrJSONSyntax))
+	var validationErrs validator.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs)
+
+	// form mapping
+	err = Query.Bind(requestWithBody("GET", "/?foo=wrong", ""), &obj{})
+	assert.ErrorIs(t, err, ErrDecode)
+	assert.ErrorAs(t, err, &bindErr)
+	assert.Equal(t, "query", bindErr.Binding)
+	var numErr *strconv.NumError
+	assert.ErrorAs(t, err, &numErr)
+
+	err = Uri.BindUri(map[string][]string{}, &obj{})
+	assert.ErrorIs(t, err, ErrValidation)
+
+	// body too large
+	req := requestWithBody("POST", "/", `{"foo": 1234567890}`)
+	req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 4)
+	err = JSON.Bind(req, &obj{})
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.False(t, errors.Is(err, ErrJSONSyntax))
+}
+
+func TestWrapError(t *testing.T) {
+	assert.NoError(t, wrapError("json", ErrDecode, nil))
+
+	err := wrapError("yaml", ErrDecode, io.ErrUnexpectedEOF)
+	assert.ErrorIs(t, err, ErrDecode)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// binding name is only filled once
+	err = wrapError("json", ErrDecode, validationError(io.EOF))
+	err = wrapError("xml", ErrDecode, err)
+	var bindErr *Error
+	assert.ErrorAs(t, err, &bindErr)
+	assert.Equal(t, "json", bindErr.Binding)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestLookup(t *testing.T) {
+	b, err := Lookup("POST", MIMEJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, JSON, b)
+
+	b, err = Lookup("POST", "")
+	assert.NoError(t, err)
+	assert.Equal(t, Form, b)
+
+	b, err = Lookup("GET", "text/csv")
+	assert.NoError(t, err)
+	assert.Equal(t, Form, b)
+
+	b, err = Lookup("POST", "text/csv")
+	assert.Nil(t, b)
+	assert.ErrorIs(t, err, ErrUnknownContentType)
+	assert.True(t, strings.Contains(err.Error(), "text/csv"))
+}
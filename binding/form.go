@@ -23,18 +23,18 @@ func (formBinding) Name() string {
 func (formBinding) Bind(req *http.Request, obj any) error {
 	// 解析form表单
 	if err := req.ParseForm(); err != nil {
-		return err
+		return wrapError("form", ErrDecode, err)
 	}
 	// 解析multipart form表单
 	if err := req.ParseMultipartForm(defaultMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
-		return err
+		return wrapError("form", ErrDecode, err)
 	}
 	// 绑定form值
 	if err := mapForm(obj, req.Form); err != nil {
-		return err
+		return wrapError("form", ErrDecode, err)
 	}
 	// 校验obj
-	return validate(obj)
+	return wrapError("form", ErrDecode, validateCtx(req.Context(), obj))
 }
 
 func (formPostBinding) Name() string {
@@ -44,14 +44,14 @@ func (formPostBinding) Name() string {
 func (formPostBinding) Bind(req *http.Request, obj any) error {
 	// 解析form表单
 	if err := req.ParseForm(); err != nil {
-		return err
+		return wrapError("form-urlencoded", ErrDecode, err)
 	}
 	// 绑定form值
 	if err := mapForm(obj, req.PostForm); err != nil {
-		return err
+		return wrapError("form-urlencoded", ErrDecode, err)
 	}
 	// 校验obj
-	return validate(obj)
+	return wrapError("form-urlencoded", ErrDecode, validateCtx(req.Context(), obj))
 }
 
 func (formMultipartBinding) Name() string {
@@ -61,12 +61,12 @@ func (formMultipartBinding) Name() string {
 func (formMultipartBinding) Bind(req *http.Request, obj any) error {
 	// 解析multipart form表单
 	if err := req.ParseMultipartForm(defaultMemory); err != nil {
-		return err
+		return wrapError("multipart/form-data", ErrDecode, err)
 	}
 	// 通过ptr绑定值
 	if err := mappingByPtr(obj, (*multipartRequest)(req), "form"); err != nil {
-		return err
+		return wrapError("multipart/form-data", ErrDecode, err)
 	}
 	// 校验obj
-	return validate(obj)
+	return wrapError("multipart/form-data", ErrDecode, validateCtx(req.Context(), obj))
 }
@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin/internal/bytesconv"
@@ -27,6 +29,50 @@ var (
 	ErrConvertToMapString = errors.New("can not convert to map of strings")
 )
 
+// CustomTypeDecoder 将请求中同名key的所有值解码为目标类型的值
+type CustomTypeDecoder func(values []string) (reflect.Value, error)
+
+var (
+	customTypeMu       sync.RWMutex
+	customTypeDecoders = map[reflect.Type]CustomTypeDecoder{}
+)
+
+// RegisterCustomType 为typ注册解码函数，form/query/uri等绑定遇到该类型时优先使用decoder，
+// 例如uuid.UUID、decimal.Decimal或者自定义枚举类型
+func RegisterCustomType(typ reflect.Type, decoder CustomTypeDecoder) {
+	customTypeMu.Lock()
+	defer customTypeMu.Unlock()
+	if decoder == nil {
+		delete(customTypeDecoders, typ)
+		return
+	}
+	customTypeDecoders[typ] = decoder
+}
+
+// 获取typ对应的解码函数
+func lookupCustomType(typ reflect.Type) (CustomTypeDecoder, bool) {
+	customTypeMu.RLock()
+	defer customTypeMu.RUnlock()
+	decoder, ok := customTypeDecoders[typ]
+	return decoder, ok
+}
+
+// 通过注册的解码函数设置值，解码结果的类型必须可以赋值给value
+func setCustomType(decoder CustomTypeDecoder, vals []string, value reflect.Value) error {
+	v, err := decoder(vals)
+	if err != nil {
+		return err
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if !v.Type().AssignableTo(value.Type()) {
+		return fmt.Errorf("custom decoder returned %s, can not assign to %s", v.Type(), value.Type())
+	}
+	value.Set(v)
+	return nil
+}
+
 // 映射uri的值
 func mapURI(ptr any, m map[string][]string) error {
 	return mapFormByTag(ptr, m, "uri")
@@ -80,9 +126,110 @@ var _ setter = (nil)
 
 // 尝试用request's form给formSource设置值
 func (form formSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (isSet bool, err error) {
+	// 不存在同名key时，尝试items[0].name形式的下标key以及m[key]形式的map key
+	if _, ok := form[tagValue]; !ok {
+		if kind := value.Kind(); kind == reflect.Slice || kind == reflect.Array {
+			if isSet, err = setFormIndexed(value, field, form, tagValue, opt.tag); isSet || err != nil {
+				return isSet, err
+			}
+		} else if _, custom := lookupCustomType(value.Type()); !custom && isStringKeyMap(value.Type()) {
+			if isSet, err = setMapFromForm(value, field, form, tagValue); isSet || err != nil {
+				return isSet, err
+			}
+		}
+	}
 	return setByForm(value, field, form, tagValue, opt)
 }
 
+// 解析items[0].name、items[0][name]、items[0]形式的key，返回下标和剩余的子key
+func parseIndexedKey(key, prefix string) (index int, subKey string, ok bool) {
+	if !strings.HasPrefix(key, prefix+"[") {
+		return 0, "", false
+	}
+	rest := key[len(prefix)+1:]
+	end := strings.IndexByte(rest, ']')
+	if end <= 0 {
+		return 0, "", false
+	}
+	index, err := strconv.Atoi(rest[:end])
+	if err != nil || index < 0 {
+		return 0, "", false
+	}
+	rest = rest[end+1:]
+	switch {
+	case rest == "":
+	case rest[0] == '.':
+		rest = rest[1:]
+	case rest[0] == '[':
+		// items[0][name]转换为name，items[0][name][x]转换为name[x]
+		name, tail := head(rest[1:], "]")
+		rest = name + tail
+	default:
+		return 0, "", false
+	}
+	return index, rest, true
+}
+
+// 通过下标key设置Slice/Array的值，Slice按照下标排序依次填充，Array按照下标直接设置
+func setFormIndexed(value reflect.Value, field reflect.StructField, form map[string][]string, tagValue, tag string) (bool, error) {
+	elems := make(map[int]formSource)
+	for key, vs := range form {
+		index, subKey, ok := parseIndexedKey(key, tagValue)
+		if !ok {
+			continue
+		}
+		if elems[index] == nil {
+			elems[index] = formSource{}
+		}
+		elems[index][subKey] = vs
+	}
+	if len(elems) == 0 {
+		return false, nil
+	}
+
+	indexes := make([]int, 0, len(elems))
+	for index := range elems {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	if value.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(value.Type(), len(indexes), len(indexes))
+		for i, index := range indexes {
+			if err := setIndexedElem(slice.Index(i), field, elems[index], tag); err != nil {
+				return false, err
+			}
+		}
+		value.Set(slice)
+		return true, nil
+	}
+
+	for _, index := range indexes {
+		if index >= value.Len() {
+			return false, fmt.Errorf("index %d out of range for %s", index, value.Type().String())
+		}
+		if err := setIndexedElem(value.Index(index), field, elems[index], tag); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// 设置单个元素，结构体元素递归绑定子key，其他类型取items[0]的值
+func setIndexedElem(elem reflect.Value, field reflect.StructField, sub formSource, tag string) error {
+	if _, custom := lookupCustomType(elem.Type()); elem.Kind() == reflect.Struct && !custom {
+		if _, isTime := elem.Interface().(time.Time); !isTime {
+			_, err := mapping(elem, emptyField, sub, tag)
+			return err
+		}
+	}
+	vs := sub[""]
+	if len(vs) == 0 {
+		return nil
+	}
+	return setWithProperType(vs[0], elem, field)
+}
+
 // 通过ptr绑定值
 func mappingByPtr(ptr any, setter setter, tag string) error {
 	_, err := mapping(reflect.ValueOf(ptr), emptyField, setter, tag)
@@ -128,6 +275,10 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			return false, err
 		}
 		if ok {
+			// 整体解码的结构体以及Slice/Array元素，为其中未设置的字段补充默认值
+			if err = setNestedDefaults(value, tag); err != nil {
+				return false, err
+			}
 			return true, nil
 		}
 	}
@@ -158,16 +309,51 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 	return false, nil
 }
 
+// 只为零值字段设置默认值的setter
+type defaultSource struct{}
+
+// 接口实现校验
+var _ setter = defaultSource{}
+
+// 字段存在default且当前为零值时，设置默认值
+func (defaultSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (bool, error) {
+	if !opt.isDefaultExists || !value.IsZero() {
+		return false, nil
+	}
+	return setByForm(value, field, nil, tagValue, opt)
+}
+
+// 为结构体或者结构体Slice/Array的元素中的零值字段设置默认值
+func setNestedDefaults(value reflect.Value, tag string) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		_, err := mapping(value, emptyField, defaultSource{}, tag)
+		return err
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if _, err := mapping(value.Index(i), emptyField, defaultSource{}, tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // TODO
 type setOptions struct {
 	isDefaultExists bool
 	defaultValue    string
+	// 当前使用的tag，用于递归绑定嵌套元素
+	tag string
 }
 
 // 尝试设置值，非强制，一般不会报错
 func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
 	var tagValue string
-	var setOpt setOptions
+	setOpt := setOptions{tag: tag}
 
 	tagValue = field.Tag.Get(tag)
 	tagValue, opts := head(tagValue, ",")
@@ -206,6 +392,26 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 		return false, nil
 	}
 
+	// Slice/Array按照collection_format拆分单个值
+	if kind := value.Kind(); ok && (kind == reflect.Slice || kind == reflect.Array) {
+		if vs, err = splitCollection(vs, field); err != nil {
+			return false, err
+		}
+	}
+
+	// 存在但值为空时同样使用默认值，Slice/Array中的空元素逐个替换为默认值
+	if ok && opt.isDefaultExists {
+		vs = fillEmptyWithDefault(vs, opt.defaultValue)
+	}
+
+	// 注册过的自定义类型优先使用解码函数，整个值列表交给解码函数处理
+	if decoder, exists := lookupCustomType(value.Type()); exists {
+		if !ok {
+			vs = []string{opt.defaultValue}
+		}
+		return true, setCustomType(decoder, vs, value)
+	}
+
 	switch value.Kind() {
 	case reflect.Slice:
 		// 获取不到tagValue
@@ -238,8 +444,64 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 	}
 }
 
+// collection_format对应的分隔符，与OpenAPI的collectionFormat一致，multi表示不拆分
+var collectionSeparators = map[string]string{
+	"csv":   ",",
+	"ssv":   " ",
+	"tsv":   "\t",
+	"pipes": "|",
+	"multi": "",
+}
+
+// 按照field的collection_format标签拆分vs中的每个值
+func splitCollection(vs []string, field reflect.StructField) ([]string, error) {
+	format := field.Tag.Get("collection_format")
+	if format == "" {
+		return vs, nil
+	}
+	sep, ok := collectionSeparators[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown collection_format %q", format)
+	}
+	if sep == "" {
+		return vs, nil
+	}
+	split := make([]string, 0, len(vs))
+	for _, v := range vs {
+		split = append(split, strings.Split(v, sep)...)
+	}
+	return split, nil
+}
+
+// 将vs中的空字符串替换为defaultValue，vs为空时返回只包含defaultValue的切片，不修改原切片
+func fillEmptyWithDefault(vs []string, defaultValue string) []string {
+	if len(vs) == 0 {
+		return []string{defaultValue}
+	}
+	var filled []string
+	for i, v := range vs {
+		if v != "" {
+			continue
+		}
+		if filled == nil {
+			filled = make([]string, len(vs))
+			copy(filled, vs)
+		}
+		filled[i] = defaultValue
+	}
+	if filled == nil {
+		return vs
+	}
+	return filled
+}
+
 // 通过value的不同反射类型设置值，内部原理一样，若有值则设置，没值设置默认值
 func setWithProperType(val string, value reflect.Value, field reflect.StructField) error {
+	// Slice和Array的元素同样支持自定义类型
+	if decoder, ok := lookupCustomType(value.Type()); ok {
+		return setCustomType(decoder, []string{val}, value)
+	}
+
 	switch value.Kind() {
 	case reflect.Int:
 		return setIntField(val, 0, value)
@@ -442,7 +704,8 @@ func setFormMap(ptr any, form map[string][]string) error {
 		// 确保ptr的类型为map[string][]string，为后面循环赋值做前置准备
 		ptrMap, ok := ptr.(map[string][]string)
 		if !ok {
-			return ErrConvertMapStringSlice
+			// 其他类型的Slice，按照元素类型转换
+			return setFormMapByType(reflect.ValueOf(ptr), form, ErrConvertMapStringSlice)
 		}
 		// 遍历赋值
 		for k, v := range form {
@@ -455,7 +718,8 @@ func setFormMap(ptr any, form map[string][]string) error {
 	// 判断el的类型，这个分支为map[string]string
 	ptrMap, ok := ptr.(map[string]string)
 	if !ok {
-		return ErrConvertToMapString
+		// 其他类型的map，例如map[string]int或者嵌套的map，按照元素类型转换
+		return setFormMapByType(reflect.ValueOf(ptr), form, ErrConvertToMapString)
 	}
 	// 确保ptr的类型为map[string]string，为后面循环赋值做前置准备
 	for k, v := range form {
@@ -465,3 +729,102 @@ func setFormMap(ptr any, form map[string][]string) error {
 
 	return nil
 }
+
+// 按照map的元素类型转换form的值，nil map无法赋值时返回nilErr
+func setFormMapByType(value reflect.Value, form map[string][]string, nilErr error) error {
+	if value.Kind() != reflect.Map || value.IsNil() {
+		return nilErr
+	}
+	_, err := setMapFromForm(value, emptyField, form, "")
+	return err
+}
+
+// MapFormMap 将form中key[name]形式的值绑定到ptr指向的map中，map的key需要为string类型，
+// 元素按照map的元素类型进行转换，支持map[string]int、map[string][]string以及a[b][c]=1形式的嵌套map
+func MapFormMap(ptr any, form map[string][]string, key string) (bool, error) {
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return false, ErrConvertToMapString
+	}
+	value = value.Elem()
+	if !isStringKeyMap(value.Type()) {
+		return false, ErrConvertToMapString
+	}
+	return setMapFromForm(value, emptyField, form, key)
+}
+
+// 判断类型是否为key是string的map
+func isStringKeyMap(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String
+}
+
+// 将form中的key按照map的key分组
+// prefix不为空时只处理prefix[name]形式的key，prefix为空时处理[name]形式的key以及普通key，
+// split为true时普通key中的name[x]会拆分为name和[x]，用于嵌套的map
+func groupMapKeys(form map[string][]string, prefix string, split bool) map[string]map[string][]string {
+	groups := make(map[string]map[string][]string)
+	for k, vs := range form {
+		var name, tail string
+		switch {
+		case strings.HasPrefix(k, prefix+"["):
+			rest := k[len(prefix)+1:]
+			end := strings.IndexByte(rest, ']')
+			if end <= 0 || (end+1 < len(rest) && rest[end+1] != '[') {
+				continue
+			}
+			name, tail = rest[:end], rest[end+1:]
+		case prefix == "" && k != "":
+			name = k
+			if i := strings.IndexByte(k, '['); split && i > 0 {
+				name, tail = k[:i], k[i:]
+			}
+		default:
+			continue
+		}
+		if groups[name] == nil {
+			groups[name] = make(map[string][]string)
+		}
+		groups[name][tail] = vs
+	}
+	return groups
+}
+
+// 通过form设置map的值，value为nil map时会新建map
+func setMapFromForm(value reflect.Value, field reflect.StructField, form map[string][]string, prefix string) (bool, error) {
+	typ := value.Type()
+	elemType := typ.Elem()
+	groups := groupMapKeys(form, prefix, isStringKeyMap(elemType))
+	if len(groups) == 0 {
+		return false, nil
+	}
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(typ))
+	}
+	for name, sub := range groups {
+		elem := reflect.New(elemType).Elem()
+		if existing := value.MapIndex(reflect.ValueOf(name).Convert(typ.Key())); existing.IsValid() {
+			elem.Set(existing)
+		}
+		isSet, err := setMapElem(elem, field, sub)
+		if err != nil {
+			return false, err
+		}
+		if isSet {
+			value.SetMapIndex(reflect.ValueOf(name).Convert(typ.Key()), elem)
+		}
+	}
+	return true, nil
+}
+
+// 设置map的单个元素，嵌套的map继续通过[name]形式的key设置
+func setMapElem(elem reflect.Value, field reflect.StructField, sub map[string][]string) (bool, error) {
+	if _, custom := lookupCustomType(elem.Type()); !custom && isStringKeyMap(elem.Type()) {
+		if isSet, err := setMapFromForm(elem, field, sub, ""); isSet || err != nil {
+			return isSet, err
+		}
+	}
+	if _, ok := sub[""]; !ok {
+		return false, nil
+	}
+	return setByForm(elem, field, sub, "", setOptions{})
+}
@@ -5,6 +5,7 @@
 package binding
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -288,3 +289,247 @@ func TestMappingIgnoredCircularRef(t *testing.T) {
 	err := mappingByPtr(&s, formSource{}, "form")
 	assert.NoError(t, err)
 }
+
+type customEnum int
+
+func TestMappingCustomType(t *testing.T) {
+	enumType := reflect.TypeOf(customEnum(0))
+	RegisterCustomType(enumType, func(vals []string) (reflect.Value, error) {
+		switch vals[0] {
+		case "one":
+			return reflect.ValueOf(customEnum(1)), nil
+		case "two":
+			return reflect.ValueOf(customEnum(2)), nil
+		}
+		return reflect.Value{}, errors.New("invalid enum")
+	})
+	defer RegisterCustomType(enumType, nil)
+
+	var s struct {
+		E     customEnum   `form:"e"`
+		P     *customEnum  `form:"p"`
+		D     customEnum   `form:"d,default=two"`
+		Slice []customEnum `form:"slice"`
+	}
+	err := mappingByPtr(&s, formSource{"e": {"one"}, "p": {"two"}, "slice": {"one", "two"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, customEnum(1), s.E)
+	assert.Equal(t, customEnum(2), *s.P)
+	assert.Equal(t, customEnum(2), s.D)
+	assert.Equal(t, []customEnum{1, 2}, s.Slice)
+
+	// decoder error
+	err = mappingByPtr(&s, formSource{"e": {"three"}}, "form")
+	assert.Error(t, err)
+
+	// decoder returns wrong type
+	RegisterCustomType(enumType, func(vals []string) (reflect.Value, error) {
+		return reflect.ValueOf("one"), nil
+	})
+	err = mappingByPtr(&s, formSource{"e": {"one"}}, "form")
+	assert.Error(t, err)
+}
+
+func TestMappingDefaultPresentButEmpty(t *testing.T) {
+	var s struct {
+		Int   int    `form:"int,default=9"`
+		Str   string `form:"str,default=hello"`
+		Slice []int  `form:"slice,default=7"`
+		Array [3]int `form:"array,default=7"`
+		Empty []int  `form:"empty,default=7"`
+	}
+	err := mappingByPtr(&s, formSource{
+		"int":   {""},
+		"str":   {""},
+		"slice": {"1", "", "3"},
+		"array": {"", "2", ""},
+		"empty": {},
+	}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 9, s.Int)
+	assert.Equal(t, "hello", s.Str)
+	assert.Equal(t, []int{1, 7, 3}, s.Slice)
+	assert.Equal(t, [3]int{7, 2, 7}, s.Array)
+	assert.Equal(t, []int{7}, s.Empty)
+}
+
+func TestMappingNestedDefault(t *testing.T) {
+	type inner struct {
+		Name string `form:"name,default=guest"`
+		Qty  int    `form:"qty,default=1"`
+	}
+	var s struct {
+		Nested inner   `form:"nested"`
+		Ptr    *inner  `form:"ptr"`
+		Flat   inner   `form:"flat"`
+		Items  []inner `form:"items"`
+	}
+	err := mappingByPtr(&s, formSource{
+		"nested": {`{"name": "gin"}`},
+		"ptr":    {`{"qty": 3}`},
+		"items":  {`{"name": "a"}`, `{"qty": 2}`},
+	}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, inner{"gin", 1}, s.Nested)
+	assert.Equal(t, &inner{"guest", 3}, s.Ptr)
+	assert.Equal(t, inner{"guest", 1}, s.Flat)
+	assert.Equal(t, []inner{{"a", 1}, {"guest", 2}}, s.Items)
+}
+
+func TestMappingIndexedSlice(t *testing.T) {
+	type item struct {
+		Name string `form:"name"`
+		Qty  int    `form:"qty,default=1"`
+	}
+	var s struct {
+		Items []item    `form:"items"`
+		Tags  []string  `form:"tags"`
+		Pairs [2]item   `form:"pairs"`
+		Times []float64 `form:"times"`
+	}
+	err := mappingByPtr(&s, formSource{
+		"items[1].name":  {"b"},
+		"items[0].name":  {"a"},
+		"items[0].qty":   {"3"},
+		"items[10][qty]": {"5"},
+		"tags[1]":        {"y"},
+		"tags[0]":        {"x"},
+		"pairs[1].name":  {"p"},
+	}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []item{{"a", 3}, {"b", 1}, {"", 5}}, s.Items)
+	assert.Equal(t, []string{"x", "y"}, s.Tags)
+	assert.Equal(t, [2]item{{"", 1}, {"p", 1}}, s.Pairs)
+	assert.Nil(t, s.Times)
+
+	// plain key takes precedence
+	var plain struct {
+		Tags []string `form:"tags"`
+	}
+	err = mappingByPtr(&plain, formSource{"tags": {"a"}, "tags[0]": {"b"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, plain.Tags)
+
+	// array index out of range
+	err = mappingByPtr(&s, formSource{"pairs[2].name": {"p"}}, "form")
+	assert.Error(t, err)
+
+	// wrong value
+	err = mappingByPtr(&s, formSource{"items[0].qty": {"wrong"}}, "form")
+	assert.Error(t, err)
+}
+
+func TestParseIndexedKey(t *testing.T) {
+	for _, tt := range []struct {
+		key    string
+		index  int
+		subKey string
+		ok     bool
+	}{
+		{"items[0]", 0, "", true},
+		{"items[2].name", 2, "name", true},
+		{"items[3][name]", 3, "name", true},
+		{"items[4][name][x]", 4, "name[x]", true},
+		{"items", 0, "", false},
+		{"items[]", 0, "", false},
+		{"items[a]", 0, "", false},
+		{"items[-1]", 0, "", false},
+		{"items[0]name", 0, "", false},
+		{"other[0]", 0, "", false},
+	} {
+		index, subKey, ok := parseIndexedKey(tt.key, "items")
+		assert.Equal(t, tt.ok, ok, tt.key)
+		assert.Equal(t, tt.index, index, tt.key)
+		assert.Equal(t, tt.subKey, subKey, tt.key)
+	}
+}
+
+func TestMappingMapBrackets(t *testing.T) {
+	var s struct {
+		Qty    map[string]int            `form:"qty"`
+		Tags   map[string][]string       `form:"tags"`
+		Nested map[string]map[string]int `form:"nested"`
+		JSON   map[string]int            `form:"json"`
+	}
+	err := mappingByPtr(&s, formSource{
+		"qty[a]":         {"1"},
+		"qty[b]":         {"2"},
+		"tags[x]":        {"1", "2"},
+		"nested[a][b]":   {"3"},
+		"nested[a][c]":   {"4"},
+		"nested[d][e]":   {"5"},
+		"json":           {`{"one": 1}`},
+		"json[ignored]":  {"2"},
+		"qty[]":          {"9"},
+		"unrelated[a]":   {"9"},
+		"nested[a]other": {"9"},
+	}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, s.Qty)
+	assert.Equal(t, map[string][]string{"x": {"1", "2"}}, s.Tags)
+	assert.Equal(t, map[string]map[string]int{"a": {"b": 3, "c": 4}, "d": {"e": 5}}, s.Nested)
+	assert.Equal(t, map[string]int{"one": 1}, s.JSON)
+
+	// wrong value
+	err = mappingByPtr(&s, formSource{"qty[a]": {"wrong"}}, "form")
+	assert.Error(t, err)
+}
+
+func TestMappingFormIntoTypedMap(t *testing.T) {
+	ints := map[string]int{}
+	err := mapForm(&ints, map[string][]string{"a": {"1"}, "b": {"2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, ints)
+
+	floats := map[string][]float64{}
+	err = mapForm(&floats, map[string][]string{"a": {"1.5", "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]float64{"a": {1.5, 2}}, floats)
+
+	nested := map[string]map[string]int{}
+	err = mapForm(&nested, map[string][]string{"a[b]": {"1"}, "a[c]": {"2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int{"a": {"b": 1, "c": 2}}, nested)
+
+	err = mapForm(&ints, map[string][]string{"a": {"wrong"}})
+	assert.Error(t, err)
+}
+
+func TestMappingCollectionFormat(t *testing.T) {
+	var s struct {
+		CSV   []int    `form:"csv" collection_format:"csv"`
+		SSV   []string `form:"ssv" collection_format:"ssv"`
+		TSV   []string `form:"tsv" collection_format:"tsv"`
+		Pipes [3]int   `form:"pipes" collection_format:"pipes"`
+		Multi []string `form:"multi" collection_format:"multi"`
+	}
+	err := mappingByPtr(&s, formSource{
+		"csv":   {"1,2", "3"},
+		"ssv":   {"a b"},
+		"tsv":   {"a\tb"},
+		"pipes": {"1|2|3"},
+		"multi": {"a,b", "c"},
+	}, "form")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2, 3}, s.CSV)
+	assert.Equal(t, []string{"a", "b"}, s.SSV)
+	assert.Equal(t, []string{"a", "b"}, s.TSV)
+	assert.Equal(t, [3]int{1, 2, 3}, s.Pipes)
+	assert.Equal(t, []string{"a,b", "c"}, s.Multi)
+
+	// wrong value
+	err = mappingByPtr(&s, formSource{"csv": {"1,wrong"}}, "form")
+	assert.Error(t, err)
+
+	// unknown format
+	var unknown struct {
+		Slice []int `form:"slice" collection_format:"unknown"`
+	}
+	err = mappingByPtr(&unknown, formSource{"slice": {"1,2"}}, "form")
+	assert.Error(t, err)
+}
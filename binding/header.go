@@ -8,8 +8,13 @@ import (
 	"net/http"
 	"net/textproto"
 	"reflect"
+	"strings"
 )
 
+// EnableHeaderCaseInsensitive 为true时，header名称按照大小写不敏感的方式匹配，
+// tag中的名称以及请求中的header名称都不要求是规范的MIME格式（例如x_request_id）
+var EnableHeaderCaseInsensitive = false
+
 type headerBinding struct{}
 
 func (headerBinding) Name() string {
@@ -19,10 +24,10 @@ func (headerBinding) Name() string {
 // 通过req.Header绑定值
 func (headerBinding) Bind(req *http.Request, obj any) error {
 	if err := mapHeader(obj, req.Header); err != nil {
-		return err
+		return wrapError("header", ErrDecode, err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("header", ErrDecode, validateCtx(req.Context(), obj))
 }
 
 func mapHeader(ptr any, h map[string][]string) error {
@@ -36,5 +41,42 @@ var _ setter = headerSource(nil)
 
 // 通过setByForm设置value的值
 func (hs headerSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (bool, error) {
-	return setByForm(value, field, hs, textproto.CanonicalMIMEHeaderKey(tagValue), opt)
+	key := textproto.CanonicalMIMEHeaderKey(tagValue)
+	vs, ok := hs.lookup(key, tagValue)
+	if !ok {
+		return setByForm(value, field, hs, key, opt)
+	}
+
+	// Slice/Array指定collection_format:"csv"时，按照逗号拆分header列表并去掉空白，例如"Accept: a, b"
+	if kind := value.Kind(); (kind == reflect.Slice || kind == reflect.Array) && field.Tag.Get("collection_format") == "csv" {
+		vs = splitHeaderList(vs)
+	}
+	return setByForm(value, field, map[string][]string{key: vs}, key, opt)
+}
+
+// 查找header的值，开启EnableHeaderCaseInsensitive时忽略大小写
+func (hs headerSource) lookup(key, tagValue string) ([]string, bool) {
+	if vs, ok := hs[key]; ok {
+		return vs, true
+	}
+	if !EnableHeaderCaseInsensitive {
+		return nil, false
+	}
+	for k, vs := range hs {
+		if strings.EqualFold(k, tagValue) {
+			return vs, true
+		}
+	}
+	return nil, false
+}
+
+// 拆分逗号分隔的header值并去掉两侧空白
+func splitHeaderList(vs []string) []string {
+	list := make([]string, 0, len(vs))
+	for _, v := range vs {
+		for _, item := range strings.Split(v, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+	}
+	return list
 }
@@ -6,11 +6,16 @@ package binding
 
 import (
 	"bytes"
+	"context"
+	stdjson "encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin/internal/json"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // EnableDecoderUseNumber is used to call the UseNumber method on the JSON
@@ -24,6 +29,13 @@ var EnableDecoderUseNumber = false
 // keys which do not match any non-ignored, exported fields in the destination.
 var EnableDecoderDisallowUnknownFields = false
 
+// JSONDecoderMaxDepth 大于0时限制JSON body中对象和数组的最大嵌套深度，
+// 超过时返回ErrJSONLimit，用于防止深度嵌套的payload消耗过多资源
+var JSONDecoderMaxDepth = 0
+
+// JSONDecoderMaxElements 大于0时限制JSON body中数组元素和对象成员的总数，超过时返回ErrJSONLimit
+var JSONDecoderMaxElements = 0
+
 type jsonBinding struct{}
 
 func (jsonBinding) Name() string {
@@ -35,16 +47,41 @@ func (jsonBinding) Bind(req *http.Request, obj any) error {
 	if req == nil || req.Body == nil {
 		return errors.New("invalid request")
 	}
-	return decodeJSON(req.Body, obj)
+	return decodeJSON(req.Context(), req.Body, obj)
 }
 
 // 通过body bytes绑定json
 func (jsonBinding) BindBody(body []byte, obj any) error {
-	return decodeJSON(bytes.NewReader(body), obj)
+	return decodeJSON(context.Background(), bytes.NewReader(body), obj)
+}
+
+// 通过body bytes绑定json，ctx传递给validator
+func (jsonBinding) BindBodyCtx(ctx context.Context, body []byte, obj any) error {
+	return decodeJSON(ctx, bytes.NewReader(body), obj)
 }
 
 // 绑定json
-func decodeJSON(r io.Reader, obj any) error {
+func decodeJSON(ctx context.Context, r io.Reader, obj any) error {
+	// 设置了限制时，先读取完整的body进行检查
+	if JSONDecoderMaxDepth > 0 || JSONDecoderMaxElements > 0 {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return wrapError("json", ErrDecode, err)
+		}
+		if err = checkJSONLimits(body, JSONDecoderMaxDepth, JSONDecoderMaxElements); err != nil {
+			return wrapError("json", ErrJSONLimit, err)
+		}
+		r = bytes.NewReader(body)
+	}
+
+	// proto.Message使用protobuf规范的JSON映射解码
+	if msg, ok := obj.(proto.Message); ok {
+		if err := decodeProtoJSON(r, msg); err != nil {
+			return jsonDecodeError(err)
+		}
+		return wrapError("json", ErrValidation, validateCtx(ctx, obj))
+	}
+
 	decoder := json.NewDecoder(r)
 	if EnableDecoderUseNumber {
 		decoder.UseNumber()
@@ -53,8 +90,76 @@ func decodeJSON(r io.Reader, obj any) error {
 		decoder.DisallowUnknownFields()
 	}
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return jsonDecodeError(err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("json", ErrValidation, validateCtx(ctx, obj))
+}
+
+// 包装JSON解码失败的错误：语法错误和不完整的body为ErrJSONSyntax，
+// 类型不匹配、未知字段等其他错误为ErrDecode
+func jsonDecodeError(err error) error {
+	var syntaxErr *stdjson.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return wrapError("json", ErrJSONSyntax, err)
+	}
+	return wrapError("json", ErrDecode, err)
+}
+
+// 检查JSON的嵌套深度和元素数量，maxDepth、maxElements小于等于0时不限制
+func checkJSONLimits(data []byte, maxDepth, maxElements int) error {
+	depth, elements := 0, 0
+	inString, escaped, expectElem := false, false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		// '['、'{'或者','之后第一个非空白字符不是结束符时，记为一个元素
+		if expectElem {
+			expectElem = false
+			if c != ']' && c != '}' {
+				elements++
+				if maxElements > 0 && elements > maxElements {
+					return fmt.Errorf("%w: more than %d elements", ErrJSONLimit, maxElements)
+				}
+			}
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if maxDepth > 0 && depth > maxDepth {
+				return fmt.Errorf("%w: nesting depth exceeds %d", ErrJSONLimit, maxDepth)
+			}
+			expectElem = true
+		case '}', ']':
+			depth--
+		case ',':
+			expectElem = true
+		}
+	}
+	return nil
+}
+
+// 通过protojson解码msg，EnableDecoderDisallowUnknownFields为false时忽略未知字段
+func decodeProtoJSON(r io.Reader, msg proto.Message) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	opts := protojson.UnmarshalOptions{DiscardUnknown: !EnableDecoderDisallowUnknownFields}
+	return opts.Unmarshal(body, msg)
 }
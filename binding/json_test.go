@@ -7,6 +7,7 @@ package binding
 import (
 	"testing"
 
+	"github.com/gin-gonic/gin/testdata/protoexample"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,3 +29,69 @@ func TestJSONBindingBindBodyMap(t *testing.T) {
 	assert.Equal(t, "FOO", s["foo"])
 	assert.Equal(t, "world", s["hello"])
 }
+
+func TestCheckJSONLimits(t *testing.T) {
+	for _, tt := range []struct {
+		json               string
+		maxDepth, maxElems int
+		ok                 bool
+	}{
+		{`{"a": [1, 2, 3]}`, 2, 4, true},
+		{`{"a": [1, 2, 3]}`, 1, 0, false},
+		{`{"a": [1, 2, 3]}`, 0, 3, false},
+		{`[]`, 1, 0, true},
+		{`[[], {}]`, 2, 2, true},
+		{`"[[[[,,,"`, 1, 1, true},
+		{`{"a\"[[": "}}]]"}`, 1, 1, true},
+		{`[[[[1]]]]`, 3, 0, false},
+	} {
+		err := checkJSONLimits([]byte(tt.json), tt.maxDepth, tt.maxElems)
+		if tt.ok {
+			assert.NoError(t, err, tt.json)
+		} else {
+			assert.ErrorIs(t, err, ErrJSONLimit, tt.json)
+		}
+	}
+}
+
+func TestJSONBindingLimits(t *testing.T) {
+	JSONDecoderMaxDepth = 2
+	JSONDecoderMaxElements = 3
+	defer func() {
+		JSONDecoderMaxDepth = 0
+		JSONDecoderMaxElements = 0
+	}()
+
+	var s struct {
+		Foo []int `json:"foo"`
+	}
+	require.NoError(t, jsonBinding{}.BindBody([]byte(`{"foo": [1, 2]}`), &s))
+	assert.Equal(t, []int{1, 2}, s.Foo)
+
+	err := jsonBinding{}.BindBody([]byte(`{"foo": [1, 2, 3]}`), &s)
+	assert.ErrorIs(t, err, ErrJSONLimit)
+	var bindErr *Error
+	require.ErrorAs(t, err, &bindErr)
+	assert.Equal(t, ErrJSONLimit, bindErr.Kind)
+
+	err = jsonBinding{}.Bind(requestWithBody("POST", "/", `{"foo": [[1]]}`), &s)
+	assert.ErrorIs(t, err, ErrJSONLimit)
+}
+
+func TestJSONBindingProtoMessage(t *testing.T) {
+	var msg protoexample.Test
+	err := jsonBinding{}.Bind(requestWithBody("POST", "/", `{"label":"gin","reps":["1",2],"unknown":true}`), &msg)
+	require.NoError(t, err)
+	assert.Equal(t, "gin", msg.GetLabel())
+	assert.Equal(t, []int64{1, 2}, msg.GetReps())
+
+	err = jsonBinding{}.BindBody([]byte(`{"label":1}`), &msg)
+	assert.ErrorIs(t, err, ErrDecode)
+
+	EnableDecoderDisallowUnknownFields = true
+	defer func() {
+		EnableDecoderDisallowUnknownFields = false
+	}()
+	err = jsonBinding{}.BindBody([]byte(`{"label":"gin","unknown":true}`), &msg)
+	assert.ErrorIs(t, err, ErrDecode)
+}
@@ -8,34 +8,53 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 
 	"github.com/ugorji/go/codec"
 )
 
-type msgpackBinding struct{}
+// 没有指定handle时使用的MsgpackHandle，配置完成的handle可以并发使用
+var defaultMsgPackHandle = new(codec.MsgpackHandle)
+
+type msgpackBinding struct {
+	handle *codec.MsgpackHandle
+}
+
+// 返回使用h解码的msgpack binding，可以配置时间格式、RawToString等选项，h为nil时使用默认的handle；
+// h在使用之后不能再修改
+func MsgPackWithHandle(h *codec.MsgpackHandle) BindingBody {
+	return msgpackBinding{handle: h}
+}
 
 func (msgpackBinding) Name() string {
 	return "msgpack"
 }
 
 // 通过req.Body绑定msgpack
-func (msgpackBinding) Bind(req *http.Request, obj any) error {
-	return decodeMsgPack(req.Body, obj)
+func (b msgpackBinding) Bind(req *http.Request, obj any) error {
+	return decodeMsgPack(req.Context(), req.Body, obj, b.handle)
 }
 
 // 通过body bytes绑定msgpack
-func (msgpackBinding) BindBody(body []byte, obj any) error {
-	return decodeMsgPack(bytes.NewReader(body), obj)
+func (b msgpackBinding) BindBody(body []byte, obj any) error {
+	return decodeMsgPack(context.Background(), bytes.NewReader(body), obj, b.handle)
+}
+
+// 通过body bytes绑定msgpack，ctx传递给validator
+func (b msgpackBinding) BindBodyCtx(ctx context.Context, body []byte, obj any) error {
+	return decodeMsgPack(ctx, bytes.NewReader(body), obj, b.handle)
 }
 
 // 绑定msgpack
-func decodeMsgPack(r io.Reader, obj any) error {
-	cdc := new(codec.MsgpackHandle)
-	if err := codec.NewDecoder(r, cdc).Decode(&obj); err != nil {
-		return err
+func decodeMsgPack(ctx context.Context, r io.Reader, obj any, h *codec.MsgpackHandle) error {
+	if h == nil {
+		h = defaultMsgPackHandle
+	}
+	if err := codec.NewDecoder(r, h).Decode(&obj); err != nil {
+		return wrapError("msgpack", ErrDecode, err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("msgpack", ErrDecode, validateCtx(ctx, obj))
 }
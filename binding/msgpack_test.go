@@ -25,6 +25,23 @@ func TestMsgpackBindingBindBody(t *testing.T) {
 	assert.Equal(t, "FOO", s.Foo)
 }
 
+func TestMsgpackBindingWithHandle(t *testing.T) {
+	body := msgpackBody(t, map[string]any{"foo": "bar"})
+
+	var obj map[string]any
+	require.NoError(t, MsgPack.BindBody(body, &obj))
+	assert.Equal(t, []byte("bar"), obj["foo"])
+
+	h := &codec.MsgpackHandle{}
+	h.RawToString = true
+	b := MsgPackWithHandle(h)
+	assert.Equal(t, "msgpack", b.Name())
+
+	obj = nil
+	require.NoError(t, b.BindBody(body, &obj))
+	assert.Equal(t, "bar", obj["foo"])
+}
+
 func msgpackBody(t *testing.T, obj any) []byte {
 	var bs bytes.Buffer
 	h := &codec.MsgpackHandle{}
@@ -22,7 +22,7 @@ func (protobufBinding) Name() string {
 func (b protobufBinding) Bind(req *http.Request, obj any) error {
 	buf, err := io.ReadAll(req.Body)
 	if err != nil {
-		return err
+		return wrapError("protobuf", ErrDecode, err)
 	}
 	// 绑定protobuf值
 	return b.BindBody(buf, obj)
@@ -35,7 +35,7 @@ func (protobufBinding) BindBody(body []byte, obj any) error {
 		return errors.New("obj is not ProtoMessage")
 	}
 	if err := proto.Unmarshal(body, msg); err != nil {
-		return err
+		return wrapError("protobuf", ErrDecode, err)
 	}
 	// proto在Unmarshal时，自动校验过，返回nil和validate(obj)效果一样
 	return nil
@@ -18,8 +18,8 @@ func (queryBinding) Bind(req *http.Request, obj any) error {
 	values := req.URL.Query()
 	// 绑定form值
 	if err := mapForm(obj, values); err != nil {
-		return err
+		return wrapError("query", ErrDecode, err)
 	}
 	// 绑定值之后，通过Validator校验参数
-	return validate(obj)
+	return wrapError("query", ErrDecode, validateCtx(req.Context(), obj))
 }
@@ -6,12 +6,17 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
+// EnableTOMLDisallowUnknownFields 为true时开启TOML的严格模式，
+// body中存在obj无法对应的key时返回错误（*toml.StrictMissingError）
+var EnableTOMLDisallowUnknownFields = false
+
 type tomlBinding struct{}
 
 func (tomlBinding) Name() string {
@@ -20,19 +25,28 @@ func (tomlBinding) Name() string {
 
 // 通过req.Body绑定toml
 func (tomlBinding) Bind(req *http.Request, obj any) error {
-	return decodeToml(req.Body, obj)
+	return decodeToml(req.Context(), req.Body, obj)
 }
 
 // 通过body bytes绑定toml
 func (tomlBinding) BindBody(body []byte, obj any) error {
-	return decodeToml(bytes.NewReader(body), obj)
+	return decodeToml(context.Background(), bytes.NewReader(body), obj)
+}
+
+// 通过body bytes绑定toml，ctx传递给validator
+func (tomlBinding) BindBodyCtx(ctx context.Context, body []byte, obj any) error {
+	return decodeToml(ctx, bytes.NewReader(body), obj)
 }
 
-// 绑定toml
-func decodeToml(r io.Reader, obj any) error {
+// 绑定toml，整个body只解码一次
+func decodeToml(ctx context.Context, r io.Reader, obj any) error {
 	decoder := toml.NewDecoder(r)
+	if EnableTOMLDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return wrapError("toml", ErrDecode, err)
 	}
-	return decoder.Decode(obj)
+	// 绑定值之后校验值
+	return wrapError("toml", ErrDecode, validateCtx(ctx, obj))
 }
@@ -7,6 +7,7 @@ package binding
 import (
 	"testing"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,3 +21,33 @@ func TestTOMLBindingBindBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "FOO", s.Foo)
 }
+
+func TestTOMLBindingStrict(t *testing.T) {
+	var s struct {
+		Foo string `toml:"foo"`
+	}
+	tomlBody := "foo=\"FOO\"\nbar=\"BAR\""
+	require.NoError(t, tomlBinding{}.BindBody([]byte(tomlBody), &s))
+	assert.Equal(t, "FOO", s.Foo)
+
+	EnableTOMLDisallowUnknownFields = true
+	defer func() {
+		EnableTOMLDisallowUnknownFields = false
+	}()
+	err := tomlBinding{}.BindBody([]byte(tomlBody), &s)
+	assert.ErrorIs(t, err, ErrDecode)
+	var strictErr *toml.StrictMissingError
+	assert.ErrorAs(t, err, &strictErr)
+}
+
+func TestTOMLBindingValidation(t *testing.T) {
+	var s struct {
+		Foo string `toml:"foo" binding:"required"`
+	}
+	err := tomlBinding{}.Bind(requestWithBody("POST", "/", `bar="BAR"`), &s)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	err = tomlBinding{}.Bind(requestWithBody("POST", "/", `foo="FOO"`), &s)
+	require.NoError(t, err)
+	assert.Equal(t, "FOO", s.Foo)
+}
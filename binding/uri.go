@@ -4,6 +4,8 @@
 
 package binding
 
+import "context"
+
 type uriBinding struct{}
 
 func (uriBinding) Name() string {
@@ -11,11 +13,16 @@ func (uriBinding) Name() string {
 }
 
 // 绑定URI的值
-func (uriBinding) BindUri(m map[string][]string, obj any) error {
+func (b uriBinding) BindUri(m map[string][]string, obj any) error {
+	return b.BindUriCtx(context.Background(), m, obj)
+}
+
+// 绑定URI的值，ctx传递给validator
+func (uriBinding) BindUriCtx(ctx context.Context, m map[string][]string, obj any) error {
 	// 映射uri的字段值
 	if err := mapURI(obj, m); err != nil {
-		return err
+		return wrapError("uri", ErrDecode, err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("uri", ErrDecode, validateCtx(ctx, obj))
 }
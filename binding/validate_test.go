@@ -6,6 +6,7 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"testing"
 	"time"
 
@@ -226,3 +227,58 @@ func TestValidatorEngine(t *testing.T) {
 	// Check that the error matches expectation
 	assert.Error(t, errs, "", "", "notone")
 }
+
+type ctxKey struct{}
+
+type structCtxValidation struct {
+	Tenant string `binding:"tenant"`
+}
+
+func TestValidatorContext(t *testing.T) {
+	engine, ok := Validator.Engine().(*validator.Validate)
+	assert.True(t, ok)
+
+	err := engine.RegisterValidationCtx("tenant", func(ctx context.Context, fl validator.FieldLevel) bool {
+		tenant, _ := ctx.Value(ctxKey{}).(string)
+		return fl.Field().String() == tenant
+	})
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "gin")
+	assert.NoError(t, validateCtx(ctx, structCtxValidation{Tenant: "gin"}))
+	assert.Error(t, validateCtx(ctx, structCtxValidation{Tenant: "other"}))
+
+	// the request context reaches the validator
+	req := requestWithBody("POST", "/", `{"Tenant": "gin"}`)
+	req = req.WithContext(ctx)
+	assert.NoError(t, JSON.Bind(req, &structCtxValidation{}))
+
+	// canceled context
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, validateCtx(canceled, structCtxValidation{Tenant: "gin"}), context.Canceled)
+}
+
+func TestContextWithValidator(t *testing.T) {
+	obj := struct {
+		Name string `binding:"required"`
+	}{}
+
+	assert.Error(t, validateCtx(context.Background(), obj))
+
+	ctx := ContextWithValidator(context.Background(), nil)
+	assert.NoError(t, validateCtx(ctx, obj))
+
+	ctx = ContextWithValidator(context.Background(), &defaultValidator{})
+	assert.Error(t, validateCtx(ctx, obj))
+}
+
+func TestValidate(t *testing.T) {
+	obj := struct {
+		Name string `binding:"required"`
+	}{}
+
+	err := Validate(context.Background(), obj)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.NoError(t, Validate(ContextWithValidator(context.Background(), nil), obj))
+}
@@ -6,11 +6,23 @@ package binding
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"net/http"
 )
 
+// XMLDecoderMaxDepth 大于0时限制XML元素的最大嵌套深度，超过时返回ErrXMLLimit
+var XMLDecoderMaxDepth = 0
+
+// XMLDecoderMaxTokenSize 大于0时限制单个token（文本、注释、指令以及单个属性值）的最大字节数，超过时返回ErrXMLLimit
+var XMLDecoderMaxTokenSize = 0
+
+// EnableXMLDisallowDirectives 为true时，body中出现<!DOCTYPE ...>、<!ENTITY ...>等指令直接返回ErrXMLLimit，
+// 不再处理自定义实体，用于防止billion laughs之类的payload
+var EnableXMLDisallowDirectives = false
+
 type xmlBinding struct{}
 
 func (xmlBinding) Name() string {
@@ -19,20 +31,92 @@ func (xmlBinding) Name() string {
 
 // 通过req.Body绑定xml
 func (xmlBinding) Bind(req *http.Request, obj any) error {
-	return decodeXML(req.Body, obj)
+	return decodeXML(req.Context(), req.Body, obj)
 }
 
 // 通过body bytes绑定xml
 func (xmlBinding) BindBody(body []byte, obj any) error {
-	return decodeXML(bytes.NewReader(body), obj)
+	return decodeXML(context.Background(), bytes.NewReader(body), obj)
+}
+
+// 通过body bytes绑定xml，ctx传递给validator
+func (xmlBinding) BindBodyCtx(ctx context.Context, body []byte, obj any) error {
+	return decodeXML(ctx, bytes.NewReader(body), obj)
 }
 
 // 绑定xml
-func decodeXML(r io.Reader, obj any) error {
-	decoder := xml.NewDecoder(r)
+func decodeXML(ctx context.Context, r io.Reader, obj any) error {
+	decoder := newXMLDecoder(r)
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return wrapError("xml", ErrDecode, err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("xml", ErrDecode, validateCtx(ctx, obj))
+}
+
+// 根据XMLDecoderMaxDepth、XMLDecoderMaxTokenSize、EnableXMLDisallowDirectives创建decoder
+func newXMLDecoder(r io.Reader) *xml.Decoder {
+	if XMLDecoderMaxDepth <= 0 && XMLDecoderMaxTokenSize <= 0 && !EnableXMLDisallowDirectives {
+		return xml.NewDecoder(r)
+	}
+	return xml.NewTokenDecoder(&limitedXMLTokenReader{
+		decoder:            xml.NewDecoder(r),
+		maxDepth:           XMLDecoderMaxDepth,
+		maxTokenSize:       XMLDecoderMaxTokenSize,
+		disallowDirectives: EnableXMLDisallowDirectives,
+	})
+}
+
+// 检查每个token的xml.TokenReader，使用RawToken读取，命名空间等由外层的decoder处理
+type limitedXMLTokenReader struct {
+	decoder            *xml.Decoder
+	depth              int
+	maxDepth           int
+	maxTokenSize       int
+	disallowDirectives bool
+}
+
+// 读取下一个token并检查限制
+func (r *limitedXMLTokenReader) Token() (xml.Token, error) {
+	tok, err := r.decoder.RawToken()
+	if err != nil {
+		return tok, err
+	}
+	switch t := tok.(type) {
+	case xml.StartElement:
+		r.depth++
+		if r.maxDepth > 0 && r.depth > r.maxDepth {
+			return nil, fmt.Errorf("%w: nesting depth exceeds %d", ErrXMLLimit, r.maxDepth)
+		}
+		for _, attr := range t.Attr {
+			if err = r.checkSize(len(attr.Value)); err != nil {
+				return nil, err
+			}
+		}
+	case xml.EndElement:
+		r.depth--
+	case xml.CharData:
+		err = r.checkSize(len(t))
+	case xml.Comment:
+		err = r.checkSize(len(t))
+	case xml.ProcInst:
+		err = r.checkSize(len(t.Inst))
+	case xml.Directive:
+		if r.disallowDirectives {
+			return nil, fmt.Errorf("%w: directives are not allowed", ErrXMLLimit)
+		}
+		err = r.checkSize(len(t))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// 检查token的大小
+func (r *limitedXMLTokenReader) checkSize(size int) error {
+	if r.maxTokenSize > 0 && size > r.maxTokenSize {
+		return fmt.Errorf("%w: token size exceeds %d bytes", ErrXMLLimit, r.maxTokenSize)
+	}
+	return nil
 }
@@ -5,6 +5,7 @@
 package binding
 
 import (
+	"encoding/xml"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,3 +24,44 @@ func TestXMLBindingBindBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "FOO", s.Foo)
 }
+
+func TestXMLBindingLimits(t *testing.T) {
+	type item struct {
+		Name string `xml:"name,attr"`
+	}
+	var s struct {
+		XMLName xml.Name `xml:"urn:test root"`
+		Foo     string   `xml:"foo"`
+		Items   []item   `xml:"items>item"`
+	}
+	xmlBody := `<root xmlns="urn:test">
+   <foo>FOO</foo>
+   <items><item name="a"/></items>
+</root>`
+
+	XMLDecoderMaxDepth = 3
+	XMLDecoderMaxTokenSize = 16
+	EnableXMLDisallowDirectives = true
+	defer func() {
+		XMLDecoderMaxDepth = 0
+		XMLDecoderMaxTokenSize = 0
+		EnableXMLDisallowDirectives = false
+	}()
+
+	require.NoError(t, xmlBinding{}.BindBody([]byte(xmlBody), &s))
+	assert.Equal(t, "FOO", s.Foo)
+	assert.Equal(t, "a", s.Items[0].Name)
+
+	for _, body := range []string{
+		`<root xmlns="urn:test"><items><item><x/></item></items></root>`,
+		`<root xmlns="urn:test"><foo>0123456789abcdefg</foo></root>`,
+		`<root xmlns="urn:test"><items><item name="0123456789abcdefg"/></items></root>`,
+		`<!DOCTYPE lolz [<!ENTITY lol "lol">]><root xmlns="urn:test"><foo>&lol;</foo></root>`,
+	} {
+		err := xmlBinding{}.BindBody([]byte(body), &s)
+		assert.ErrorIs(t, err, ErrXMLLimit, body)
+		var bindErr *Error
+		require.ErrorAs(t, err, &bindErr)
+		assert.Equal(t, ErrXMLLimit, bindErr.Kind)
+	}
+}
@@ -6,8 +6,11 @@ package binding
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net/http"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,20 +23,70 @@ func (yamlBinding) Name() string {
 
 // 通过req.Body绑定yaml
 func (yamlBinding) Bind(req *http.Request, obj any) error {
-	return decodeYAML(req.Body, obj)
+	return decodeYAML(req.Context(), req.Body, obj)
 }
 
 // 通过body bytes绑定yaml
 func (yamlBinding) BindBody(body []byte, obj any) error {
-	return decodeYAML(bytes.NewReader(body), obj)
+	return decodeYAML(context.Background(), bytes.NewReader(body), obj)
+}
+
+// 通过body bytes绑定yaml，ctx传递给validator
+func (yamlBinding) BindBodyCtx(ctx context.Context, body []byte, obj any) error {
+	return decodeYAML(ctx, bytes.NewReader(body), obj)
 }
 
 // 绑定yaml
-func decodeYAML(r io.Reader, obj any) error {
+func decodeYAML(ctx context.Context, r io.Reader, obj any) error {
 	decoder := yaml.NewDecoder(r)
+	// obj为Slice指针时，支持包含多个document的body
+	if isSlicePtr(obj) {
+		if err := decodeYAMLDocuments(decoder, obj); err != nil {
+			return wrapError("yaml", ErrDecode, err)
+		}
+		return wrapError("yaml", ErrDecode, validateCtx(ctx, obj))
+	}
 	if err := decoder.Decode(obj); err != nil {
-		return err
+		return wrapError("yaml", ErrDecode, err)
 	}
 	// 绑定值之后校验值
-	return validate(obj)
+	return wrapError("yaml", ErrDecode, validateCtx(ctx, obj))
+}
+
+// 判断obj是否为指向Slice的指针
+func isSlicePtr(obj any) bool {
+	typ := reflect.TypeOf(obj)
+	return typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Slice
+}
+
+// 逐个解码body中的document，每个document作为Slice的一个元素；
+// 只有一个document并且该document本身是序列时，与普通解码一致
+func decodeYAMLDocuments(decoder *yaml.Decoder, obj any) error {
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		docs = append(docs, &doc)
+	}
+	if len(docs) == 0 {
+		return io.EOF
+	}
+	if len(docs) == 1 && len(docs[0].Content) == 1 && docs[0].Content[0].Kind == yaml.SequenceNode {
+		return docs[0].Decode(obj)
+	}
+
+	slice := reflect.ValueOf(obj).Elem()
+	elems := reflect.MakeSlice(slice.Type(), len(docs), len(docs))
+	for i, doc := range docs {
+		if err := doc.Decode(elems.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	slice.Set(elems)
+	return nil
 }
@@ -19,3 +19,34 @@ func TestYAMLBindingBindBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "FOO", s.Foo)
 }
+
+func TestYAMLBindingMultipleDocuments(t *testing.T) {
+	type doc struct {
+		Kind string `yaml:"kind" binding:"required"`
+	}
+
+	var docs []doc
+	body := "kind: Service\n---\nkind: Deployment\n"
+	require.NoError(t, yamlBinding{}.BindBody([]byte(body), &docs))
+	assert.Equal(t, []doc{{"Service"}, {"Deployment"}}, docs)
+
+	// a single sequence document keeps the previous behavior
+	docs = nil
+	require.NoError(t, yamlBinding{}.BindBody([]byte("- kind: Service\n- kind: Deployment\n"), &docs))
+	assert.Equal(t, []doc{{"Service"}, {"Deployment"}}, docs)
+
+	// a single mapping document binds as one element
+	docs = nil
+	require.NoError(t, yamlBinding{}.BindBody([]byte("kind: Service\n"), &docs))
+	assert.Equal(t, []doc{{"Service"}}, docs)
+
+	// every document is validated
+	err := yamlBinding{}.BindBody([]byte("kind: Service\n---\nname: x\n"), &docs)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	err = yamlBinding{}.BindBody([]byte("kind: [\n"), &docs)
+	assert.ErrorIs(t, err, ErrDecode)
+
+	err = yamlBinding{}.BindBody([]byte(""), &docs)
+	assert.Error(t, err)
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// BindingOptions 路由或者路由组级别的binding配置，Bind/ShouldBind等方法绑定请求时使用，
+// 例如公开API和管理API在同一个Engine中使用不同的校验策略
+type BindingOptions struct {
+	// 校验使用的validator，为nil时使用binding.Validator
+	Validator binding.StructValidator
+
+	// 为true时不进行校验
+	DisableValidation bool
+
+	// 不为nil时，Bind/ShouldBind使用该binding代替根据Method和Content-Type选择的binding
+	Binding binding.Binding
+}
+
+// 返回将opts保存到Context中的middleware，在注册路由或者路由组时使用
+//
+//	admin := router.Group("/admin", gin.WithBindingOptions(gin.BindingOptions{Validator: strict}))
+func WithBindingOptions(opts BindingOptions) HandlerFunc {
+	return func(c *Context) {
+		c.bindingOpts = &opts
+		c.Next()
+	}
+}
+
+// 根据Context中的BindingOptions返回实际使用的binding
+func (c *Context) defaultBinding() binding.Binding {
+	if c.bindingOpts != nil && c.bindingOpts.Binding != nil {
+		return c.bindingOpts.Binding
+	}
+	return binding.Default(c.Request.Method, c.ContentType())
+}
+
+// 根据Context中的BindingOptions返回绑定使用的request，需要替换validator时返回携带validator的request副本
+func (c *Context) bindingRequest() *http.Request {
+	opts := c.bindingOpts
+	if opts == nil || c.Request == nil || (opts.Validator == nil && !opts.DisableValidation) {
+		return c.Request
+	}
+	v := opts.Validator
+	if opts.DisableValidation {
+		v = nil
+	}
+	return c.Request.WithContext(binding.ContextWithValidator(c.Request.Context(), v))
+}
+
+// msgpack的mime，nomsgpack构建时binding中没有定义对应的常量
+const (
+	mimeMsgPack  = "application/x-msgpack"
+	mimeMsgPack2 = "application/msgpack"
+)
+
+// binding名字对应的mime，用于查找Engine.SetBinding设置的binding
+var bindingNameMIMEs = map[string]string{
+	"json":                binding.MIMEJSON,
+	"xml":                 binding.MIMEXML,
+	"form":                binding.MIMEPOSTForm,
+	"form-urlencoded":     binding.MIMEPOSTForm,
+	"multipart/form-data": binding.MIMEMultipartPOSTForm,
+	"protobuf":            binding.MIMEPROTOBUF,
+	"msgpack":             mimeMsgPack,
+	"yaml":                binding.MIMEYAML,
+	"toml":                binding.MIMETOML,
+}
+
+// 将mime的别名转换为binding使用的mime
+func canonicalBindingMIME(mime string) string {
+	switch mime {
+	case binding.MIMEXML2:
+		return binding.MIMEXML
+	case mimeMsgPack2:
+		return mimeMsgPack
+	}
+	return mime
+}
+
+// 返回Engine.SetBinding为b对应的mime设置的binding，没有设置时返回b
+func (c *Context) engineBinding(b binding.Binding) binding.Binding {
+	if c.engine == nil || len(c.engine.bindings) == 0 || b == nil {
+		return b
+	}
+	mime, ok := bindingNameMIMEs[b.Name()]
+	if !ok {
+		return b
+	}
+	if override, ok := c.engine.bindings[mime]; ok {
+		return override
+	}
+	return b
+}
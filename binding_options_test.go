@@ -0,0 +1,212 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+type rejectAllValidator struct{}
+
+func (rejectAllValidator) ValidateStruct(any) error {
+	return errors.New("rejected")
+}
+
+func (rejectAllValidator) Engine() any {
+	return nil
+}
+
+func TestWithBindingOptions(t *testing.T) {
+	type payload struct {
+		Name string `json:"name" form:"name" binding:"required"`
+	}
+
+	router := New()
+	bind := func(c *Context) {
+		var obj payload
+		if err := c.ShouldBind(&obj); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, obj.Name)
+	}
+	router.POST("/public", bind)
+	router.POST("/strict", WithBindingOptions(BindingOptions{Validator: rejectAllValidator{}}), bind)
+	router.POST("/loose", WithBindingOptions(BindingOptions{DisableValidation: true}), bind)
+	router.POST("/json", WithBindingOptions(BindingOptions{Binding: binding.JSON}), bind)
+
+	for _, tt := range []struct {
+		path, body, contentType string
+		code                    int
+		response                string
+	}{
+		{"/public", `{"name":"gin"}`, MIMEJSON, http.StatusOK, "gin"},
+		{"/public", `{}`, MIMEJSON, http.StatusBadRequest, ""},
+		{"/strict", `{"name":"gin"}`, MIMEJSON, http.StatusBadRequest, "rejected"},
+		{"/loose", `{}`, MIMEJSON, http.StatusOK, ""},
+		{"/json", `{"name":"gin"}`, MIMEPOSTForm, http.StatusOK, "gin"},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.path)
+		if tt.response != "" {
+			assert.Equal(t, tt.response, w.Body.String(), tt.path)
+		}
+	}
+}
+
+func TestBindingOptionsUriAndBodyWith(t *testing.T) {
+	type uriPayload struct {
+		ID string `uri:"id" binding:"required"`
+	}
+	type bodyPayload struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	router := New()
+	bindUri := func(c *Context) {
+		var obj uriPayload
+		if err := c.ShouldBindUri(&obj); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, obj.ID)
+	}
+	bindBody := func(c *Context) {
+		var obj bodyPayload
+		if err := c.ShouldBindBodyWith(&obj, binding.JSON); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, obj.Name)
+	}
+	strict := WithBindingOptions(BindingOptions{Validator: rejectAllValidator{}})
+	loose := WithBindingOptions(BindingOptions{DisableValidation: true})
+	router.POST("/public/uri/:id", bindUri)
+	router.POST("/strict/uri/:id", strict, bindUri)
+	router.POST("/public/body", bindBody)
+	router.POST("/strict/body", strict, bindBody)
+	router.POST("/loose/body", loose, bindBody)
+
+	for _, tt := range []struct {
+		path, body string
+		code       int
+		response   string
+	}{
+		{"/public/uri/1", "", http.StatusOK, "1"},
+		{"/strict/uri/1", "", http.StatusBadRequest, "rejected"},
+		{"/public/body", `{"name":"gin"}`, http.StatusOK, "gin"},
+		{"/public/body", `{}`, http.StatusBadRequest, ""},
+		{"/strict/body", `{"name":"gin"}`, http.StatusBadRequest, "rejected"},
+		{"/loose/body", `{}`, http.StatusOK, ""},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+		req.Header.Set("Content-Type", MIMEJSON)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.path)
+		if tt.response != "" {
+			assert.Equal(t, tt.response, w.Body.String(), tt.path)
+		}
+	}
+}
+
+func TestBindingOptionsResetAndCopy(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	WithBindingOptions(BindingOptions{DisableValidation: true})(c)
+	assert.NotNil(t, c.bindingOpts)
+	assert.Equal(t, c.bindingOpts, c.Copy().bindingOpts)
+
+	c.reset()
+	assert.Nil(t, c.bindingOpts)
+}
+
+type fakeBinding struct {
+	name string
+	err  error
+}
+
+func (b fakeBinding) Name() string {
+	return b.name
+}
+
+func (b fakeBinding) Bind(_ *http.Request, obj any) error {
+	return b.BindBody(nil, obj)
+}
+
+func (b fakeBinding) BindBody(_ []byte, obj any) error {
+	if b.err != nil {
+		return b.err
+	}
+	if p, ok := obj.(*map[string]string); ok {
+		*p = map[string]string{"binding": "fake"}
+	}
+	return nil
+}
+
+func TestEngineSetBinding(t *testing.T) {
+	router := New()
+	router.SetBinding(MIMEJSON, fakeBinding{name: "fake", err: errors.New("fake json error")})
+	router.SetBinding(binding.MIMEXML2, fakeBinding{name: "fake"})
+
+	var errs []string
+	router.POST("/", func(c *Context) {
+		var obj map[string]string
+		errs = errs[:0]
+		for _, bind := range []func() error{
+			func() error { return c.ShouldBind(&obj) },
+			func() error { return c.ShouldBindJSON(&obj) },
+			func() error { return c.ShouldBindBodyWith(&obj, binding.JSON) },
+		} {
+			if err := bind(); err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				errs = append(errs, obj["binding"])
+			}
+		}
+	})
+
+	PerformRequest(router, "POST", "/", header{"Content-Type", MIMEJSON})
+	assert.Equal(t, []string{"fake json error", "fake json error", "fake json error"}, errs)
+
+	router.SetBinding(MIMEJSON, nil)
+	router.BodyRewindLimit = 1024
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"binding":"real"}`))
+	req.Header.Set("Content-Type", MIMEJSON)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, []string{"real", "real", "real"}, errs)
+
+	// MIMEXML2设置的binding也用于MIMEXML
+	var got map[string]string
+	router.PUT("/", func(c *Context) {
+		got = nil
+		assert.NoError(t, c.ShouldBindXML(&got))
+	})
+	PerformRequest(router, "PUT", "/", header{"Content-Type", MIMEXML})
+	assert.Equal(t, map[string]string{"binding": "fake"}, got)
+}
+
+func TestEngineSetBindingBind(t *testing.T) {
+	router := New()
+	router.SetBinding(MIMEPOSTForm, fakeBinding{name: "fake", err: errors.New("invalid form")})
+	router.GET("/", func(c *Context) {
+		var obj struct{}
+		_ = c.Bind(&obj)
+	})
+
+	w := PerformRequest(router, "GET", "/")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 熔断器的默认配置
+const (
+	defaultCircuitWindow           = 10 * time.Second
+	defaultCircuitMinRequests      = 20
+	defaultCircuitErrorRate        = 0.5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// 熔断器的状态
+type CircuitState int
+
+const (
+	// 正常处理请求，并统计失败率
+	CircuitClosed CircuitState = iota
+	// 直接返回503，OpenTimeout之后进入半开状态
+	CircuitOpen
+	// 允许少量探测请求，探测成功后关闭，失败后重新打开
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig 熔断器的配置
+type CircuitBreakerConfig struct {
+	// 返回熔断使用的key，例如路由或者上游服务的名字，返回空字符串时不进行熔断；
+	// 默认为"METHOD FullPath"，没有匹配到路由的请求不进行熔断
+	KeyFunc func(c *Context) string
+
+	// 统计失败率的窗口，窗口结束后重新计数，默认为10秒
+	Window time.Duration
+
+	// 窗口内的请求数达到该值之后才会根据失败率打开熔断器，默认为20
+	MinRequests int
+
+	// 打开熔断器的失败率，默认为0.5
+	ErrorRate float64
+
+	// 大于0时，处理时间超过该值的请求视为失败
+	SlowThreshold time.Duration
+
+	// 熔断器打开之后进入半开状态的时间，默认为30秒
+	OpenTimeout time.Duration
+
+	// 半开状态下允许的探测请求数，全部成功后关闭熔断器，默认为1
+	HalfOpenRequests int
+
+	// 返回请求是否失败，默认状态码为5xx时失败；handler panic时总是视为失败
+	IsFailure func(c *Context) bool
+
+	// 状态变化时调用，调用时持有熔断器的锁，不能调用熔断器的方法
+	OnStateChange func(key string, from, to CircuitState)
+}
+
+// CircuitStats 熔断器中一个key的状态
+type CircuitStats struct {
+	State CircuitState
+	// 当前窗口内的请求数和失败数
+	Requests int
+	Failures int
+	// 累计被拒绝的请求数
+	Rejected uint64
+}
+
+// CircuitBreaker 按key统计失败率和延迟的熔断器，并发安全；
+// 可以通过Handler作为middleware使用，也可以通过Allow保护对上游服务的调用
+type CircuitBreaker struct {
+	conf     CircuitBreakerConfig
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+// 单个key的状态
+type circuit struct {
+	state       CircuitState
+	generation  uint64
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	probes      int
+	successes   int
+	rejected    uint64
+}
+
+// 返回熔断器，未设置的配置使用默认值
+func NewCircuitBreaker(conf CircuitBreakerConfig) *CircuitBreaker {
+	if conf.KeyFunc == nil {
+		conf.KeyFunc = func(c *Context) string {
+			if c.FullPath() == "" {
+				return ""
+			}
+			return c.Request.Method + " " + c.FullPath()
+		}
+	}
+	if conf.Window <= 0 {
+		conf.Window = defaultCircuitWindow
+	}
+	if conf.MinRequests <= 0 {
+		conf.MinRequests = defaultCircuitMinRequests
+	}
+	if conf.ErrorRate <= 0 {
+		conf.ErrorRate = defaultCircuitErrorRate
+	}
+	if conf.OpenTimeout <= 0 {
+		conf.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if conf.HalfOpenRequests <= 0 {
+		conf.HalfOpenRequests = defaultCircuitHalfOpenRequests
+	}
+	if conf.IsFailure == nil {
+		conf.IsFailure = func(c *Context) bool {
+			return c.Writer.Status() >= http.StatusInternalServerError
+		}
+	}
+	return &CircuitBreaker{
+		conf:     conf,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// 返回熔断器的middleware，熔断器打开时返回503并设置Retry-After
+func (cb *CircuitBreaker) Handler() HandlerFunc {
+	return func(c *Context) {
+		key := cb.conf.KeyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		done, retryAfter, ok := cb.allow(key)
+		if !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+			return
+		}
+
+		failed := true
+		start := cb.now()
+		defer func() {
+			done(failed)
+		}()
+		c.Next()
+		failed = cb.conf.IsFailure(c) ||
+			(cb.conf.SlowThreshold > 0 && cb.now().Sub(start) > cb.conf.SlowThreshold)
+	}
+}
+
+// 检查key是否允许调用，允许时返回调用结束后必须调用的done，参数为调用是否失败
+//
+//	done, ok := cb.Allow("payments")
+//	if !ok {
+//		return errUnavailable
+//	}
+//	err := callPayments()
+//	done(err != nil)
+func (cb *CircuitBreaker) Allow(key string) (done func(failed bool), ok bool) {
+	done, _, ok = cb.allow(key)
+	return done, ok
+}
+
+// 返回key当前的状态
+func (cb *CircuitBreaker) State(key string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if ct, ok := cb.circuits[key]; ok {
+		cb.advance(key, ct, cb.now())
+		return ct.state
+	}
+	return CircuitClosed
+}
+
+// 返回全部key的状态
+func (cb *CircuitBreaker) Snapshot() map[string]CircuitStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	snapshot := make(map[string]CircuitStats, len(cb.circuits))
+	for key, ct := range cb.circuits {
+		cb.advance(key, ct, now)
+		snapshot[key] = CircuitStats{
+			State:    ct.state,
+			Requests: ct.requests,
+			Failures: ct.failures,
+			Rejected: ct.rejected,
+		}
+	}
+	return snapshot
+}
+
+// 返回已记录的key，按字母顺序排序
+func (cb *CircuitBreaker) Keys() []string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	keys := make([]string, 0, len(cb.circuits))
+	for key := range cb.circuits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 关闭key的熔断器并清除统计，key为空时重置全部
+func (cb *CircuitBreaker) Reset(key string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if key == "" {
+		cb.circuits = make(map[string]*circuit)
+		return
+	}
+	delete(cb.circuits, key)
+}
+
+// 检查key是否允许调用，不允许时返回距离进入半开状态的时间
+func (cb *CircuitBreaker) allow(key string) (func(failed bool), time.Duration, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	ct, ok := cb.circuits[key]
+	if !ok {
+		ct = &circuit{windowStart: now}
+		cb.circuits[key] = ct
+	}
+	cb.advance(key, ct, now)
+
+	probe := false
+	switch ct.state {
+	case CircuitOpen:
+		ct.rejected++
+		return nil, ct.openedAt.Add(cb.conf.OpenTimeout).Sub(now), false
+	case CircuitHalfOpen:
+		if ct.probes >= cb.conf.HalfOpenRequests {
+			ct.rejected++
+			return nil, 0, false
+		}
+		ct.probes++
+		probe = true
+	}
+
+	generation := ct.generation
+	var once sync.Once
+	return func(failed bool) {
+		once.Do(func() { cb.done(key, ct, generation, probe, failed) })
+	}, 0, true
+}
+
+// 记录一次调用的结果，状态已经变化时忽略之前状态下开始的调用
+func (cb *CircuitBreaker) done(key string, ct *circuit, generation uint64, probe, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if ct.generation != generation || cb.circuits[key] != ct {
+		return
+	}
+	now := cb.now()
+	if probe {
+		if failed {
+			cb.setState(key, ct, CircuitOpen, now)
+			return
+		}
+		ct.successes++
+		if ct.successes >= cb.conf.HalfOpenRequests {
+			cb.setState(key, ct, CircuitClosed, now)
+		}
+		return
+	}
+
+	cb.advance(key, ct, now)
+	if ct.state != CircuitClosed {
+		return
+	}
+	ct.requests++
+	if failed {
+		ct.failures++
+	}
+	if ct.requests >= cb.conf.MinRequests && float64(ct.failures)/float64(ct.requests) >= cb.conf.ErrorRate {
+		cb.setState(key, ct, CircuitOpen, now)
+	}
+}
+
+// 根据时间更新状态：打开超过OpenTimeout后进入半开状态，窗口结束后重新计数
+func (cb *CircuitBreaker) advance(key string, ct *circuit, now time.Time) {
+	switch ct.state {
+	case CircuitOpen:
+		if now.Sub(ct.openedAt) >= cb.conf.OpenTimeout {
+			cb.setState(key, ct, CircuitHalfOpen, now)
+		}
+	case CircuitClosed:
+		if now.Sub(ct.windowStart) >= cb.conf.Window {
+			ct.windowStart = now
+			ct.requests = 0
+			ct.failures = 0
+		}
+	}
+}
+
+// 切换状态并清除当前状态的统计
+func (cb *CircuitBreaker) setState(key string, ct *circuit, state CircuitState, now time.Time) {
+	from := ct.state
+	ct.state = state
+	ct.generation++
+	ct.requests = 0
+	ct.failures = 0
+	ct.probes = 0
+	ct.successes = 0
+	ct.windowStart = now
+	if state == CircuitOpen {
+		ct.openedAt = now
+	}
+	if cb.conf.OnStateChange != nil {
+		cb.conf.OnStateChange(key, from, state)
+	}
+}
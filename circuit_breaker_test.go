@@ -0,0 +1,166 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 返回使用可控时钟的熔断器
+func newTestCircuitBreaker(conf CircuitBreakerConfig) (*CircuitBreaker, *time.Time) {
+	cb := NewCircuitBreaker(conf)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+func TestCircuitStateString(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "unknown", CircuitState(10).String())
+}
+
+func TestCircuitBreakerHandler(t *testing.T) {
+	var transitions []string
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{
+		MinRequests: 4,
+		OpenTimeout: 5 * time.Second,
+		OnStateChange: func(key string, from, to CircuitState) {
+			transitions = append(transitions, key+" "+from.String()+"->"+to.String())
+		},
+	})
+	fail := true
+	router := New()
+	router.Use(cb.Handler())
+	router.GET("/upstream", func(c *Context) {
+		if fail {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/healthy", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	// 4个请求中失败率达到0.5后打开
+	PerformRequest(router, http.MethodGet, "/upstream")
+	fail = false
+	PerformRequest(router, http.MethodGet, "/upstream")
+	PerformRequest(router, http.MethodGet, "/upstream")
+	assert.Equal(t, CircuitClosed, cb.State("GET /upstream"))
+	fail = true
+	PerformRequest(router, http.MethodGet, "/upstream")
+	assert.Equal(t, CircuitOpen, cb.State("GET /upstream"))
+
+	w := PerformRequest(router, http.MethodGet, "/upstream")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+	// 其他路由不受影响，没有匹配的路由不进行熔断
+	w = PerformRequest(router, http.MethodGet, "/healthy")
+	assert.Equal(t, http.StatusOK, w.Code)
+	PerformRequest(router, http.MethodGet, "/missing")
+	assert.Equal(t, []string{"GET /healthy", "GET /upstream"}, cb.Keys())
+
+	// 半开状态下探测失败重新打开
+	*now = now.Add(5 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, cb.State("GET /upstream"))
+	w = PerformRequest(router, http.MethodGet, "/upstream")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Equal(t, CircuitOpen, cb.State("GET /upstream"))
+
+	// 探测成功后关闭
+	*now = now.Add(5 * time.Second)
+	fail = false
+	w = PerformRequest(router, http.MethodGet, "/upstream")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, CircuitClosed, cb.State("GET /upstream"))
+
+	assert.Equal(t, []string{
+		"GET /upstream closed->open",
+		"GET /upstream open->half-open",
+		"GET /upstream half-open->open",
+		"GET /upstream open->half-open",
+		"GET /upstream half-open->closed",
+	}, transitions)
+	assert.Equal(t, CircuitStats{State: CircuitClosed, Rejected: 1}, cb.Snapshot()["GET /upstream"])
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{MinRequests: 1, HalfOpenRequests: 2})
+
+	done, ok := cb.Allow("db")
+	assert.True(t, ok)
+	done(true)
+	_, ok = cb.Allow("db")
+	assert.False(t, ok)
+
+	*now = now.Add(defaultCircuitOpenTimeout)
+	probe1, ok := cb.Allow("db")
+	assert.True(t, ok)
+	probe2, ok := cb.Allow("db")
+	assert.True(t, ok)
+	_, ok = cb.Allow("db")
+	assert.False(t, ok)
+
+	probe1(false)
+	assert.Equal(t, CircuitHalfOpen, cb.State("db"))
+	probe2(false)
+	probe2(true)
+	assert.Equal(t, CircuitClosed, cb.State("db"))
+	assert.Equal(t, uint64(2), cb.Snapshot()["db"].Rejected)
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{MinRequests: 3, Window: time.Second})
+
+	for i := 0; i < 2; i++ {
+		done, _ := cb.Allow("k")
+		done(true)
+	}
+	assert.Equal(t, CircuitStats{State: CircuitClosed, Requests: 2, Failures: 2}, cb.Snapshot()["k"])
+
+	// 窗口结束后重新计数
+	*now = now.Add(time.Second)
+	done, _ := cb.Allow("k")
+	done(true)
+	assert.Equal(t, CircuitStats{State: CircuitClosed, Requests: 1, Failures: 1}, cb.Snapshot()["k"])
+
+	cb.Reset("k")
+	assert.Empty(t, cb.Keys())
+	// Reset之前开始的调用不会影响新的统计
+	done(true)
+	assert.Empty(t, cb.Snapshot())
+}
+
+func TestCircuitBreakerSlowAndPanic(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{MinRequests: 2, ErrorRate: 1, SlowThreshold: time.Second})
+	router := New()
+	router.Use(Recovery(), cb.Handler())
+	router.GET("/slow", func(c *Context) {
+		*now = now.Add(2 * time.Second)
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *Context) {
+		panic("oops")
+	})
+
+	PerformRequest(router, http.MethodGet, "/slow")
+	PerformRequest(router, http.MethodGet, "/slow")
+	assert.Equal(t, CircuitOpen, cb.State("GET /slow"))
+
+	PerformRequest(router, http.MethodGet, "/panic")
+	PerformRequest(router, http.MethodGet, "/panic")
+	assert.Equal(t, CircuitOpen, cb.State("GET /panic"))
+
+	cb.Reset("")
+	assert.Empty(t, cb.Keys())
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net"
+	"strings"
+)
+
+// ClientIPStrategy Context.ClientIP从RemoteIPHeaders中获取客户端IP的方式
+type ClientIPStrategy int
+
+const (
+	// 默认方式：设置了Engine.TrustedProxyHops时与ClientIPRightmostTrusted相同，否则与ClientIPFirstUntrusted相同
+	ClientIPDefault ClientIPStrategy = iota
+
+	// RemoteAddr为可信任的代理时，从右往左跳过可信任的代理（SetTrustedProxies），返回第一个不受信任的地址
+	ClientIPFirstUntrusted
+
+	// 信任恰好Engine.TrustedProxyHops层代理（未设置时为1层），返回从右往左数第N个地址，不检查SetTrustedProxies
+	ClientIPRightmostTrusted
+
+	// RemoteAddr为可信任的代理时返回最左边的有效地址，该地址可以被客户端伪造，只能用于日志等场景
+	ClientIPLeftmost
+
+	// 只使用Engine.TrustedPlatform指定的header，不使用RemoteIPHeaders，header不存在时返回RemoteAddr
+	ClientIPPlatformHeader
+)
+
+// 返回strategy的名称
+func (s ClientIPStrategy) String() string {
+	switch s {
+	case ClientIPDefault:
+		return "default"
+	case ClientIPFirstUntrusted:
+		return "first-untrusted"
+	case ClientIPRightmostTrusted:
+		return "rightmost-trusted"
+	case ClientIPLeftmost:
+		return "leftmost"
+	case ClientIPPlatformHeader:
+		return "platform-header"
+	}
+	return "unknown"
+}
+
+// 返回是否按照代理层数获取客户端IP
+func (engine *Engine) useProxyHops() bool {
+	switch engine.ClientIPStrategy {
+	case ClientIPRightmostTrusted:
+		return true
+	case ClientIPDefault:
+		return engine.TrustedProxyHops > 0
+	}
+	return false
+}
+
+// 返回是否信任RemoteAddr并解析RemoteIPHeaders
+func (engine *Engine) trustRemoteIP(ip net.IP) bool {
+	if engine.ClientIPStrategy == ClientIPPlatformHeader {
+		return false
+	}
+	return engine.useProxyHops() || engine.isTrustedProxy(ip)
+}
+
+// 按照Engine.ClientIPStrategy解析header，返回客户端IP
+func (engine *Engine) clientIPFromHeader(header string) (clientIP string, valid bool) {
+	switch {
+	case engine.useProxyHops():
+		return engine.validateHeaderHops(header)
+	case engine.ClientIPStrategy == ClientIPLeftmost:
+		return validateHeaderLeftmost(header)
+	}
+	return engine.validateHeader(header)
+}
+
+// 返回header中最左边的有效地址
+func validateHeaderLeftmost(header string) (clientIP string, valid bool) {
+	if header == "" {
+		return "", false
+	}
+	for _, item := range strings.Split(header, ",") {
+		ipStr := strings.TrimSpace(item)
+		if net.ParseIP(ipStr) != nil {
+			return ipStr, true
+		}
+	}
+	return "", false
+}
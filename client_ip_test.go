@@ -0,0 +1,61 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPStrategy(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	resetContextForClientIPTests(c)
+	c.Request.Header.Del("X-Real-IP")
+	c.Request.Header.Set("X-Forwarded-For", "unknown, 1.1.1.1, 20.20.20.20, 30.30.30.30")
+	assert.NoError(t, c.engine.SetTrustedProxies([]string{"40.40.40.40", "30.30.30.30"}))
+
+	assert.Equal(t, "20.20.20.20", c.ClientIP())
+
+	c.engine.ClientIPStrategy = ClientIPFirstUntrusted
+	assert.Equal(t, "20.20.20.20", c.ClientIP())
+	c.engine.TrustedProxyHops = 3
+	assert.Equal(t, "20.20.20.20", c.ClientIP())
+
+	c.engine.ClientIPStrategy = ClientIPRightmostTrusted
+	assert.Equal(t, "1.1.1.1", c.ClientIP())
+	c.engine.TrustedProxyHops = 0
+	assert.Equal(t, "30.30.30.30", c.ClientIP())
+
+	c.engine.ClientIPStrategy = ClientIPLeftmost
+	assert.Equal(t, "1.1.1.1", c.ClientIP())
+
+	c.engine.ClientIPStrategy = ClientIPPlatformHeader
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+	c.engine.TrustedPlatform = PlatformCloudflare
+	assert.Equal(t, "60.60.60.60", c.ClientIP())
+	c.engine.TrustedPlatform = ""
+
+	// RemoteAddr不受信任
+	c.Request.RemoteAddr = "50.50.50.50:1234"
+	for _, s := range []ClientIPStrategy{ClientIPDefault, ClientIPFirstUntrusted, ClientIPLeftmost} {
+		c.engine.ClientIPStrategy = s
+		assert.Equal(t, "50.50.50.50", c.ClientIP(), s.String())
+	}
+	c.engine.ClientIPStrategy = ClientIPRightmostTrusted
+	assert.Equal(t, "30.30.30.30", c.ClientIP())
+}
+
+func TestClientIPStrategyString(t *testing.T) {
+	assert.Equal(t, "default", ClientIPDefault.String())
+	assert.Equal(t, "first-untrusted", ClientIPFirstUntrusted.String())
+	assert.Equal(t, "rightmost-trusted", ClientIPRightmostTrusted.String())
+	assert.Equal(t, "leftmost", ClientIPLeftmost.String())
+	assert.Equal(t, "platform-header", ClientIPPlatformHeader.String())
+	assert.Equal(t, "unknown", ClientIPStrategy(100).String())
+}
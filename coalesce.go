@@ -0,0 +1,185 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// 合并的响应中添加的header
+const CoalescedHeader = "X-Coalesced"
+
+// 默认参与key计算的请求header，避免不同用户或者不同内容协商的请求共享响应
+var defaultCoalesceVaryHeaders = []string{"Accept", "Accept-Encoding", "Accept-Language", "Authorization", "Cookie"}
+
+// CoalesceConfig 合并请求middleware的配置
+type CoalesceConfig struct {
+	// 需要合并的method，默认只合并GET
+	Methods []string
+
+	// 参与key计算的请求header，默认为Accept、Accept-Encoding、Accept-Language、Authorization和Cookie
+	VaryHeaders []string
+
+	// 返回合并使用的key，返回空字符串时不合并；默认为method、path、query和VaryHeaders的值
+	KeyFunc func(c *Context) string
+}
+
+// 合并请求middleware的状态
+type coalescer struct {
+	methods map[string]bool
+	keyFunc func(c *Context) string
+	mu      sync.Mutex
+	calls   map[string]*coalesceCall
+}
+
+// 一次正在处理的请求
+type coalesceCall struct {
+	done chan struct{}
+	// 处理完成之后的响应，handler panic或者响应不能共享时为nil
+	resp *IdempotentResponse
+}
+
+// 返回使用默认配置的合并请求middleware
+func Coalesce() HandlerFunc {
+	return CoalesceWithConfig(CoalesceConfig{})
+}
+
+// 通过配置返回合并请求middleware：相同key的并发请求只有第一个会执行handler，
+// 其他请求等待并重放第一个请求的响应，用于防止缓存失效时大量请求同时访问后端；
+// 第一个请求panic，或者响应包含Set-Cookie、Cache-Control: private/no-store时，等待的请求各自执行handler
+func CoalesceWithConfig(conf CoalesceConfig) HandlerFunc {
+	return newCoalescer(conf).handle
+}
+
+// 返回合并请求middleware的状态，未设置的配置使用默认值
+func newCoalescer(conf CoalesceConfig) *coalescer {
+	methods := map[string]bool{http.MethodGet: true}
+	if len(conf.Methods) > 0 {
+		methods = make(map[string]bool, len(conf.Methods))
+		for _, method := range conf.Methods {
+			methods[method] = true
+		}
+	}
+	varyHeaders := conf.VaryHeaders
+	if varyHeaders == nil {
+		varyHeaders = defaultCoalesceVaryHeaders
+	}
+	keyFunc := conf.KeyFunc
+	if keyFunc == nil {
+		keyFunc = func(c *Context) string {
+			return coalesceKey(c, varyHeaders)
+		}
+	}
+
+	return &coalescer{
+		methods: methods,
+		keyFunc: keyFunc,
+		calls:   make(map[string]*coalesceCall),
+	}
+}
+
+// 合并相同key的并发请求
+func (g *coalescer) handle(c *Context) {
+	if !g.methods[c.Request.Method] {
+		c.Next()
+		return
+	}
+	key := g.keyFunc(c)
+	if key == "" {
+		c.Next()
+		return
+	}
+
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-c.Request.Context().Done():
+			// 客户端已经断开连接
+			c.Abort()
+			return
+		}
+		if call.resp == nil {
+			c.Next()
+			return
+		}
+		replayCoalescedResponse(c, call.resp)
+		return
+	}
+	call := &coalesceCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	w := &idempotencyWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	if !shareableResponse(w.Header()) {
+		return
+	}
+	call.resp = &IdempotentResponse{
+		Status: w.Status(),
+		Header: w.Header().Clone(),
+		Body:   w.body.Bytes(),
+	}
+}
+
+// 响应是否可以重放给其他请求，包含Set-Cookie或者Cache-Control为private、no-store时不共享
+func shareableResponse(header http.Header) bool {
+	if len(header.Values("Set-Cookie")) > 0 {
+		return false
+	}
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(name, "private") || strings.EqualFold(name, "no-store") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 默认的合并key："METHOD PATH?QUERY"加上VaryHeaders的值
+func coalesceKey(c *Context, varyHeaders []string) string {
+	var sb strings.Builder
+	sb.WriteString(c.Request.Method)
+	sb.WriteByte(' ')
+	sb.WriteString(c.Request.URL.Path)
+	sb.WriteByte('?')
+	sb.WriteString(c.Request.URL.RawQuery)
+	for _, name := range varyHeaders {
+		sb.WriteByte('\n')
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(c.Request.Header.Values(name), ","))
+	}
+	return sb.String()
+}
+
+// 重放第一个请求的响应
+func replayCoalescedResponse(c *Context, resp *IdempotentResponse) {
+	// 响应被多个请求共享，复制header的值
+	header := c.Writer.Header()
+	for key, value := range resp.Header {
+		header[key] = append([]string(nil), value...)
+	}
+	header.Set(CoalescedHeader, "true")
+	c.Writer.WriteHeader(resp.Status)
+	c.Writer.WriteHeaderNow()
+	_, _ = c.Writer.Write(resp.Body)
+	c.Abort()
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 并发执行n个相同的请求，等待全部请求进入等待之后调用release
+func performCoalesced(t *testing.T, router *Engine, g *coalescer, path string, n int, release func()) []*httptest.ResponseRecorder {
+	recorders := make([]*httptest.ResponseRecorder, n+1)
+	// 统计计算key的请求数，之后的请求会立即进入等待
+	var keys int32
+	keyFunc := g.keyFunc
+	g.keyFunc = func(c *Context) string {
+		atomic.AddInt32(&keys, 1)
+		return keyFunc(c)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recorders[0] = PerformRequest(router, http.MethodGet, path)
+	}()
+	key := http.MethodGet + " " + path
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		_, ok := g.calls[key]
+		return ok
+	}, time.Second, time.Millisecond)
+	for i := 1; i <= n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorders[i] = PerformRequest(router, http.MethodGet, path)
+		}(i)
+	}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&keys) == int32(n+1)
+	}, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	release()
+	wg.Wait()
+	return recorders
+}
+
+func TestCoalesce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	g := newCoalescer(CoalesceConfig{KeyFunc: func(c *Context) string {
+		return c.Request.Method + " " + c.Request.URL.RequestURI()
+	}})
+	router := New()
+	router.Use(g.handle)
+	router.GET("/items", func(c *Context) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		c.Header("X-Item", "1")
+		c.String(http.StatusOK, "items %s", c.Query("page"))
+	})
+
+	recorders := performCoalesced(t, router, g, "/items?page=1", 4, func() { close(release) })
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for i, w := range recorders {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "items 1", w.Body.String())
+		assert.Equal(t, "1", w.Header().Get("X-Item"))
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		if i == 0 {
+			assert.Empty(t, w.Header().Get(CoalescedHeader))
+		} else {
+			assert.Equal(t, "true", w.Header().Get(CoalescedHeader))
+		}
+	}
+
+	// 完成之后的请求重新执行handler
+	w := PerformRequest(router, http.MethodGet, "/items?page=2")
+	assert.Equal(t, "items 2", w.Body.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Empty(t, g.calls)
+}
+
+func TestCoalescePanic(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	g := newCoalescer(CoalesceConfig{KeyFunc: func(c *Context) string {
+		return c.Request.Method + " " + c.Request.URL.RequestURI()
+	}})
+	router := New()
+	router.Use(RecoveryWithWriter(io.Discard), g.handle)
+	router.GET("/items", func(c *Context) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+			panic("boom")
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	recorders := performCoalesced(t, router, g, "/items", 2, func() { close(release) })
+	assert.Equal(t, http.StatusInternalServerError, recorders[0].Code)
+	// 第一个请求panic之后，等待的请求各自执行handler
+	for _, w := range recorders[1:] {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(CoalescedHeader))
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCoalesceNotShared(t *testing.T) {
+	for _, header := range []header{
+		{Key: "Set-Cookie", Value: "session=new"},
+		{Key: "Cache-Control", Value: "max-age=0, private"},
+		{Key: "Cache-Control", Value: "no-store"},
+	} {
+		var calls int32
+		release := make(chan struct{})
+		g := newCoalescer(CoalesceConfig{KeyFunc: func(c *Context) string {
+			return c.Request.Method + " " + c.Request.URL.RequestURI()
+		}})
+		router := New()
+		router.Use(g.handle)
+		router.GET("/session", func(c *Context) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-release
+			}
+			c.Header(header.Key, header.Value)
+			c.String(http.StatusOK, "ok")
+		})
+
+		recorders := performCoalesced(t, router, g, "/session", 2, func() { close(release) })
+		// 等待的请求各自执行handler
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls), header.Key)
+		for _, w := range recorders {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get(CoalescedHeader))
+		}
+	}
+	assert.True(t, shareableResponse(http.Header{"Cache-Control": {"public, max-age=60"}}))
+}
+
+func TestCoalesceKey(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/items?page=1", nil)
+	c.Request.Header.Set("Authorization", "Bearer a")
+	key := coalesceKey(c, defaultCoalesceVaryHeaders)
+
+	c.Request.Header.Set("Authorization", "Bearer b")
+	assert.NotEqual(t, key, coalesceKey(c, defaultCoalesceVaryHeaders))
+	assert.Equal(t, "GET /items?page=1", coalesceKey(c, nil))
+
+	c.Request = httptest.NewRequest(http.MethodGet, "/items?page=2", nil)
+	assert.NotEqual(t, "GET /items?page=1", coalesceKey(c, nil))
+}
+
+func TestCoalesceSkipsOtherMethods(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(Coalesce())
+	router.POST("/items", func(c *Context) {
+		atomic.AddInt32(&calls, 1)
+		c.Status(http.StatusCreated)
+	})
+	w := PerformRequest(router, http.MethodPost, "/items")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(CoalescedHeader))
+	assert.Equal(t, int32(1), calls)
+}
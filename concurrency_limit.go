@@ -0,0 +1,117 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyLimitConfig 并发请求限制middleware的配置
+type ConcurrencyLimitConfig struct {
+	// 同时处理的最大请求数，必须大于0
+	MaxInFlight int
+
+	// 达到MaxInFlight时最多等待的请求数，等待队列已满时返回503，为0时不等待
+	QueueSize int
+
+	// 在等待队列中的最长时间，超时后返回503；为0时一直等待到请求的context结束
+	QueueTimeout time.Duration
+
+	// 为true时每个路由（"METHOD FullPath"）单独限制，没有匹配到路由的请求不受限制
+	PerRoute bool
+}
+
+// 返回限制同时处理的请求数的middleware，超过max的请求直接返回503
+func ConcurrencyLimit(max int) HandlerFunc {
+	return ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{MaxInFlight: max})
+}
+
+// 通过配置返回并发请求限制middleware，handler返回、abort或者panic时都会释放占用的名额
+func ConcurrencyLimitWithConfig(conf ConcurrencyLimitConfig) HandlerFunc {
+	if conf.MaxInFlight <= 0 {
+		panic("gin: ConcurrencyLimitConfig.MaxInFlight must be greater than 0")
+	}
+	if !conf.PerRoute {
+		l := newConcurrencyLimiter(conf)
+		return l.handle
+	}
+
+	var mu sync.Mutex
+	limiters := make(map[string]*concurrencyLimiter)
+	return func(c *Context) {
+		if c.FullPath() == "" {
+			c.Next()
+			return
+		}
+		key := c.Request.Method + " " + c.FullPath()
+		mu.Lock()
+		l, ok := limiters[key]
+		if !ok {
+			l = newConcurrencyLimiter(conf)
+			limiters[key] = l
+		}
+		mu.Unlock()
+		l.handle(c)
+	}
+}
+
+// 基于channel的信号量和有限的等待队列
+type concurrencyLimiter struct {
+	slots   chan struct{}
+	waiting int32
+	conf    ConcurrencyLimitConfig
+}
+
+func newConcurrencyLimiter(conf ConcurrencyLimitConfig) *concurrencyLimiter {
+	return &concurrencyLimiter{slots: make(chan struct{}, conf.MaxInFlight), conf: conf}
+}
+
+// 获取名额后执行后续的handler，结束后释放名额
+func (l *concurrencyLimiter) handle(c *Context) {
+	if !l.acquire(c) {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+	defer l.release()
+	c.Next()
+}
+
+// 获取名额，等待队列已满、等待超时或者请求的context结束时返回false
+func (l *concurrencyLimiter) acquire(c *Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if atomic.AddInt32(&l.waiting, 1) > int32(l.conf.QueueSize) {
+		atomic.AddInt32(&l.waiting, -1)
+		return false
+	}
+	defer atomic.AddInt32(&l.waiting, -1)
+
+	var timeout <-chan time.Time
+	if l.conf.QueueTimeout > 0 {
+		timer := time.NewTimer(l.conf.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timeout:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+// 释放名额
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
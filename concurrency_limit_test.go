@@ -0,0 +1,156 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	router := New()
+	router.Use(ConcurrencyLimit(2))
+	router.GET("/", func(c *Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, PerformRequest(router, http.MethodGet, "/").Code)
+		}()
+	}
+	<-entered
+	<-entered
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, PerformRequest(router, http.MethodGet, "/").Code)
+}
+
+func TestConcurrencyLimitQueue(t *testing.T) {
+	entered := make(chan struct{}, 10)
+	release := make(chan struct{})
+	router := New()
+	limit := ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{MaxInFlight: 1, QueueSize: 1})
+	router.GET("/", limit, func(c *Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	codes := make(chan int, 2)
+	go func() { codes <- PerformRequest(router, http.MethodGet, "/").Code }()
+	<-entered
+	go func() { codes <- PerformRequest(router, http.MethodGet, "/").Code }()
+
+	// 等待第二个请求进入等待队列，之后的请求超出队列长度
+	assert.Eventually(t, func() bool {
+		return PerformRequest(router, http.MethodGet, "/").Code == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-codes)
+	assert.Equal(t, http.StatusOK, <-codes)
+}
+
+func TestConcurrencyLimitQueueTimeout(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	router := New()
+	router.Use(ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{
+		MaxInFlight:  1,
+		QueueSize:    5,
+		QueueTimeout: 10 * time.Millisecond,
+	}))
+	router.GET("/", func(c *Context) {
+		close(entered)
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		PerformRequest(router, http.MethodGet, "/")
+		close(done)
+	}()
+	<-entered
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	// 请求的context结束时停止等待
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	close(release)
+	<-done
+}
+
+func TestConcurrencyLimitPerRoute(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	router := New()
+	router.Use(ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{MaxInFlight: 1, PerRoute: true}))
+	router.GET("/slow", func(c *Context) {
+		close(entered)
+		<-release
+	})
+	router.GET("/fast", func(c *Context) {
+		c.Status(http.StatusOK)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		PerformRequest(router, http.MethodGet, "/slow")
+		close(done)
+	}()
+	<-entered
+
+	assert.Equal(t, http.StatusServiceUnavailable, PerformRequest(router, http.MethodGet, "/slow").Code)
+	assert.Equal(t, http.StatusOK, PerformRequest(router, http.MethodGet, "/fast").Code)
+	assert.Equal(t, http.StatusNotFound, PerformRequest(router, http.MethodGet, "/missing").Code)
+
+	close(release)
+	<-done
+}
+
+func TestConcurrencyLimitReleasesOnPanicAndAbort(t *testing.T) {
+	router := New()
+	router.Use(Recovery(), ConcurrencyLimit(1))
+	router.GET("/panic", func(c *Context) {
+		panic("oops")
+	})
+	router.GET("/abort", func(c *Context) {
+		c.AbortWithStatus(http.StatusForbidden)
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusInternalServerError, PerformRequest(router, http.MethodGet, "/panic").Code)
+		assert.Equal(t, http.StatusForbidden, PerformRequest(router, http.MethodGet, "/abort").Code)
+	}
+}
+
+func TestConcurrencyLimitInvalidConfig(t *testing.T) {
+	assert.Panics(t, func() { ConcurrencyLimit(0) })
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"time"
+)
+
+// 设置Last-Modified header，精确到秒；t为零值时不设置
+func (c *Context) SetLastModified(t time.Time) {
+	if isZeroTime(t) {
+		return
+	}
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// 返回304并调用Abort，删除304响应不需要的实体header
+func (c *Context) NotModified() {
+	header := c.Writer.Header()
+	delete(header, "Content-Type")
+	delete(header, "Content-Length")
+	delete(header, "Content-Encoding")
+	c.AbortWithStatus(http.StatusNotModified)
+}
+
+// 根据If-Modified-Since判断资源是否修改，未修改时返回304并调用Abort，返回true表示已经写入响应：
+//
+//	if c.CheckIfModifiedSince(article.UpdatedAt) {
+//		return
+//	}
+//
+// 按照RFC 9110，只处理GET和HEAD请求，请求携带If-None-Match时忽略If-Modified-Since
+func (c *Context) CheckIfModifiedSince(modtime time.Time) bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if c.requestHeader("If-None-Match") != "" {
+		return false
+	}
+	since, ok := c.conditionalTime("If-Modified-Since", modtime)
+	if !ok || modtime.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.NotModified()
+	return true
+}
+
+// 根据If-Unmodified-Since判断资源是否在之后被修改，已修改时返回412并调用Abort，返回true表示已经写入响应；
+// 请求携带If-Match时忽略If-Unmodified-Since
+func (c *Context) CheckIfUnmodifiedSince(modtime time.Time) bool {
+	if c.requestHeader("If-Match") != "" {
+		return false
+	}
+	since, ok := c.conditionalTime("If-Unmodified-Since", modtime)
+	if !ok || !modtime.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusPreconditionFailed)
+	return true
+}
+
+// 解析条件请求header中的时间，header不存在、不合法或者modtime未知时返回false
+func (c *Context) conditionalTime(name string, modtime time.Time) (time.Time, bool) {
+	if isZeroTime(modtime) {
+		return time.Time{}, false
+	}
+	value := c.requestHeader(name)
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// 零值和Unix纪元都表示修改时间未知，与http.ServeContent相同
+func isZeroTime(t time.Time) bool {
+	return t.IsZero() || t.Equal(time.Unix(0, 0))
+}
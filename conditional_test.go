@@ -0,0 +1,110 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextSetLastModified(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	modtime := time.Date(2026, 1, 2, 3, 4, 5, 600, time.FixedZone("CST", 8*3600))
+	c.SetLastModified(modtime)
+	assert.Equal(t, "Thu, 01 Jan 2026 19:04:05 GMT", w.Header().Get("Last-Modified"))
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.SetLastModified(time.Time{})
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+}
+
+func TestContextNotModified(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Header("Content-Type", "text/plain")
+	c.Header("ETag", `"v1"`)
+	c.NotModified()
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.True(t, c.IsAborted())
+	assert.Empty(t, w.Header().Get("Content-Type"))
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+}
+
+func TestContextCheckIfModifiedSince(t *testing.T) {
+	modtime := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	router := New()
+	router.Any("/article", func(c *Context) {
+		if c.CheckIfModifiedSince(modtime) {
+			return
+		}
+		c.SetLastModified(modtime)
+		c.String(http.StatusOK, "article")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/article")
+	assert.Equal(t, http.StatusOK, w.Code)
+	lastModified := w.Header().Get("Last-Modified")
+
+	w = PerformRequest(router, http.MethodGet, "/article", header{Key: "If-Modified-Since", Value: lastModified})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = PerformRequest(router, http.MethodHead, "/article", header{Key: "If-Modified-Since", Value: lastModified})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	before := modtime.Add(-time.Minute).Format(http.TimeFormat)
+	w = PerformRequest(router, http.MethodGet, "/article", header{Key: "If-Modified-Since", Value: before})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 不合法的时间、If-None-Match和非GET请求忽略If-Modified-Since
+	w = PerformRequest(router, http.MethodGet, "/article", header{Key: "If-Modified-Since", Value: "yesterday"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = PerformRequest(router, http.MethodGet, "/article",
+		header{Key: "If-Modified-Since", Value: lastModified}, header{Key: "If-None-Match", Value: `"v1"`})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = PerformRequest(router, http.MethodPost, "/article", header{Key: "If-Modified-Since", Value: lastModified})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestContextCheckIfUnmodifiedSince(t *testing.T) {
+	modtime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	router := New()
+	router.PUT("/article", func(c *Context) {
+		if c.CheckIfUnmodifiedSince(modtime) {
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := PerformRequest(router, http.MethodPut, "/article")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = PerformRequest(router, http.MethodPut, "/article", header{Key: "If-Unmodified-Since", Value: modtime.Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	before := modtime.Add(-time.Second).Format(http.TimeFormat)
+	w = PerformRequest(router, http.MethodPut, "/article", header{Key: "If-Unmodified-Since", Value: before})
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = PerformRequest(router, http.MethodPut, "/article",
+		header{Key: "If-Unmodified-Since", Value: before}, header{Key: "If-Match", Value: "*"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestContextConditionalUnknownModtime(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("If-Modified-Since", time.Now().Format(http.TimeFormat))
+	c.Request.Header.Set("If-Unmodified-Since", "Thu, 01 Jan 1970 00:00:00 GMT")
+	assert.False(t, c.CheckIfModifiedSince(time.Time{}))
+	assert.False(t, c.CheckIfUnmodifiedSince(time.Unix(0, 0)))
+}
@@ -5,10 +5,16 @@
 package gin
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"html/template"
 	"io"
+	"io/fs"
 	"log"
 	"math"
+	"mime"
 	"mime/multipart"
 	"net"
 	"net/http"
@@ -17,6 +23,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/sse"
@@ -46,6 +53,24 @@ const ContextKey = "_gin-gonic/gin/contextkey"
 // 默认的abort方法的index
 const abortIndex int8 = math.MaxInt8 >> 1
 
+// 超过Engine.MultipartLimits时返回的错误
+var (
+	// part数量超过MultipartLimits.MaxParts
+	ErrMultipartTooManyParts = errors.New("multipart: too many parts")
+
+	// 文件大小超过MultipartLimits.MaxFileSize
+	ErrMultipartFileTooLarge = errors.New("multipart: file too large")
+
+	// body大小超过MultipartLimits.MaxTotalSize
+	ErrMultipartTooLarge = errors.New("multipart: request body too large")
+)
+
+// JSONP的callback不合法时返回的错误
+var ErrInvalidJSONPCallback = errors.New("gin: invalid JSONP callback")
+
+// HTMLRender不支持layout时Context.HTMLWithLayout返回的错误
+var ErrHTMLLayoutUnsupported = errors.New("gin: HTMLRender does not support layouts")
+
 // Context是gin中最重要的部分，可以通过Context在middleware中传递变量，请求链路控制、校验JSON参数以及response的JSON render
 type Context struct {
 	writermem responseWriter
@@ -81,6 +106,27 @@ type Context struct {
 
 	// 允许服务器定义cookie属性，使得浏览器无法将此 cookie与跨站请求一起发送
 	sameSite http.SameSite
+
+	// 通过WithBindingOptions设置的路由级别binding配置
+	bindingOpts *BindingOptions
+
+	// 通过SetHTMLFuncs设置的请求级别模板函数
+	htmlFuncs template.FuncMap
+
+	// 通过WithSecureJSONPrefix设置的SecureJSON前缀
+	secureJSONPrefix *string
+
+	// 通过WithJSONEnvelope设置的JSON响应转换
+	jsonEnvelope *JSONEnvelopeFunc
+
+	// Copy返回的Context，Deadline、Done和Err不继承c.Request.Context()的取消和超时
+	copied bool
+
+	// 通过Defer注册的函数，在响应写入后按注册的相反顺序执行
+	deferred []func()
+
+	// debug模式下Context放回对象池时记录的handler name，用于检测放回后继续使用Context
+	releasedBy atomic.Value
 }
 
 /************************************/
@@ -90,6 +136,9 @@ type Context struct {
 // 重置Context
 func (c *Context) reset() {
 	c.Writer = &c.writermem
+	if c.engine != nil {
+		c.writermem.defaultHeaders = c.engine.defaultHeaders
+	}
 	c.Params = c.Params[:0]
 	c.handlers = nil
 	c.index = -1
@@ -101,18 +150,31 @@ func (c *Context) reset() {
 	c.queryCache = nil
 	c.formCache = nil
 	c.sameSite = 0
+	c.bindingOpts = nil
+	c.htmlFuncs = nil
+	c.secureJSONPrefix = nil
+	c.jsonEnvelope = nil
+	c.deferred = nil
 	*c.params = (*c.params)[:0]
 	*c.skippedNodes = (*c.skippedNodes)[:0]
 }
 
 // 返回当前Context的copy（safe），仅当需要把context传入goroutine时使用
 func (c *Context) Copy() *Context {
+	c.checkReleased()
 	cp := Context{
 		writermem: c.writermem,
 		Request:   c.Request,
 		Params:    c.Params,
 		engine:    c.engine,
+
+		bindingOpts: c.bindingOpts,
+		htmlFuncs:   c.htmlFuncs,
+
+		secureJSONPrefix: c.secureJSONPrefix,
+		jsonEnvelope:     c.jsonEnvelope,
 	}
+	cp.copied = true
 	cp.writermem.ResponseWriter = nil
 	cp.Writer = &cp.writermem
 	cp.index = abortIndex
@@ -127,6 +189,44 @@ func (c *Context) Copy() *Context {
 	return &cp
 }
 
+// 注册在handler链执行完并写入响应后（Context放回对象池之前）调用的函数，按注册的相反顺序执行，
+// 可用于清理资源、异步审计日志或者与请求生命周期绑定的事务；通过Copy返回的Context注册的函数不会被调用
+func (c *Context) Defer(f func()) {
+	c.deferred = append(c.deferred, f)
+}
+
+// 执行通过Defer注册的函数
+func (c *Context) runDeferred() {
+	for i := len(c.deferred) - 1; i >= 0; i-- {
+		c.deferred[i]()
+	}
+	c.deferred = nil
+}
+
+// debug模式下标记Context已放回对象池，记录持有该Context的handler name
+func (c *Context) markReleased() {
+	name := "<none>"
+	if handler := c.handlers.Last(); handler != nil {
+		name = nameOfFunction(handler)
+	}
+	c.releasedBy.Store(name)
+}
+
+// 从对象池取出Context时清除标记
+func (c *Context) clearReleased() {
+	if name, _ := c.releasedBy.Load().(string); name != "" {
+		c.releasedBy.Store("")
+	}
+}
+
+// Context放回对象池后继续使用时panic，通常是在goroutine中使用了Context而没有调用Copy()
+func (c *Context) checkReleased() {
+	if name, _ := c.releasedBy.Load().(string); name != "" {
+		panic("gin: Context used after the request completed (retained by handler " + name +
+			"); use Context.Copy() when passing the Context to a goroutine")
+	}
+}
+
 // 返回mian的handler's name，eg：handleGetUsers()会返回main.handleGetUsers
 func (c *Context) HandlerName() string {
 	return nameOfFunction(c.handlers.Last())
@@ -161,6 +261,7 @@ func (c *Context) FullPath() string {
 
 // 在中间件内部使用，执行下一个handler
 func (c *Context) Next() {
+	c.checkReleased()
 	c.index++
 	for c.index < int8(len(c.handlers)) {
 		c.handlers[c.index](c)
@@ -176,6 +277,7 @@ func (c *Context) IsAborted() bool {
 // 调用Abort停止请求链路，防止调用待处理程序，但不会停止当前处理程序
 // eg：假设有个授权中间件，如果授权失败，调用Abort，可以防止调用此请求的其他处理程序
 func (c *Context) Abort() {
+	c.checkReleased()
 	c.index = abortIndex
 }
 
@@ -192,6 +294,30 @@ func (c *Context) AbortWithStatusJSON(code int, jsonObj any) {
 	c.JSON(code, jsonObj)
 }
 
+// 调用Abort停止请求链路，之后使用传入的obj返回xml对象，设置Content-Type为application/xml
+func (c *Context) AbortWithStatusXML(code int, obj any) {
+	c.Abort()
+	c.XML(code, obj)
+}
+
+// 调用Abort停止请求链路，之后使用传入的obj返回yaml对象，设置Content-Type为application/x-yaml
+func (c *Context) AbortWithStatusYAML(code int, obj any) {
+	c.Abort()
+	c.YAML(code, obj)
+}
+
+// 调用Abort停止请求链路，之后使用传入的obj返回toml对象，设置Content-Type为application/toml
+func (c *Context) AbortWithStatusTOML(code int, obj any) {
+	c.Abort()
+	c.TOML(code, obj)
+}
+
+// 调用Abort停止请求链路，之后使用传入的render写入响应
+func (c *Context) AbortWithRender(code int, r render.Render) {
+	c.Abort()
+	c.Render(code, r)
+}
+
 // 调用AbortWithStatus停止请求链路，之后写入c.Error，使用部分在Context.Error()
 func (c *Context) AbortWithError(code int, err error) *Error {
 	c.AbortWithStatus(code)
@@ -218,6 +344,10 @@ func (c *Context) Error(err error) *Error {
 			Type: ErrorTypePrivate,
 		}
 	}
+	// 记录调用Error的位置
+	if c.engine != nil && c.engine.CaptureErrorStack && parsedError.Stack == nil {
+		parsedError.Stack = captureStack(2)
+	}
 
 	c.Errors = append(c.Errors, parsedError)
 	return parsedError
@@ -229,6 +359,7 @@ func (c *Context) Error(err error) *Error {
 
 // 为Context存储新的key/value键值对，使用懒加载初始化c.Keys
 func (c *Context) Set(key string, value any) {
+	c.checkReleased()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.Keys == nil {
@@ -240,12 +371,33 @@ func (c *Context) Set(key string, value any) {
 
 // 获取指定的key
 func (c *Context) Get(key string) (value any, exists bool) {
+	c.checkReleased()
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	value, exists = c.Keys[key]
 	return
 }
 
+// 获取指定的key，不存在时调用compute计算并存储，同一个key的compute最多调用一次
+// compute在持有Keys的锁时调用，不能在compute中调用Set/Get等方法
+func (c *Context) GetOrSet(key string, compute func() any) any {
+	if value, exists := c.Get(key); exists {
+		return value
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, exists := c.Keys[key]; exists {
+		return value
+	}
+	if c.Keys == nil {
+		c.Keys = make(map[string]any)
+	}
+	value := compute()
+	c.Keys[key] = value
+	return value
+}
+
 // 获取指定的key，如果不存在则会panic
 func (c *Context) MustGet(key string) any {
 	if value, exists := c.Get(key); exists {
@@ -369,6 +521,7 @@ func (c *Context) GetStringMapStringSlice(key string) (smss map[string][]string)
 //	    id := c.Param("id") // id == "/john"
 //	})
 func (c *Context) Param(key string) string {
+	c.checkReleased()
 	return c.Params.ByName(key)
 }
 
@@ -428,6 +581,7 @@ func (c *Context) QueryArray(key string) (values []string) {
 
 // 初始化QueryCache
 func (c *Context) initQueryCache() {
+	c.checkReleased()
 	if c.queryCache == nil {
 		// c.Request不为空赋值为c.Request.URL.Query()
 		if c.Request != nil {
@@ -457,6 +611,13 @@ func (c *Context) GetQueryMap(key string) (map[string]string, bool) {
 	return c.get(c.queryCache, key)
 }
 
+// 将URL中key[name]形式的值绑定到obj指向的map中，map的元素按照其类型进行转换，
+// 例如*map[string]int、*map[string][]string或者a[b][c]=1形式的嵌套map，有一个存在返回true
+func (c *Context) GetQueryMapAs(key string, obj any) (bool, error) {
+	c.initQueryCache()
+	return binding.MapFormMap(obj, c.queryCache, key)
+}
+
 // 从urlencoded form或multipart form获取指定的key，不存在返回空字符串
 func (c *Context) PostForm(key string) (value string) {
 	value, _ = c.GetPostForm(key)
@@ -491,14 +652,19 @@ func (c *Context) PostFormArray(key string) (values []string) {
 
 // 初始化FormCache
 func (c *Context) initFormCache() {
+	c.checkReleased()
 	if c.formCache == nil {
 		c.formCache = make(url.Values)
 		req := c.Request
 		// 使用MaxMultipartMemory进行ParseMultipartForm
-		if err := req.ParseMultipartForm(c.engine.MaxMultipartMemory); err != nil {
+		if err := c.parseMultipartForm(); err != nil {
 			if !errors.Is(err, http.ErrNotMultipart) {
 				debugPrint("error on parse multipart form array: %v", err)
 			}
+			// 超过限制时不使用解析出的值
+			if isMultipartLimitError(err) {
+				return
+			}
 		}
 		c.formCache = req.PostForm
 	}
@@ -523,6 +689,12 @@ func (c *Context) GetPostFormMap(key string) (map[string]string, bool) {
 	return c.get(c.formCache, key)
 }
 
+// 将urlencoded form或multipart form中key[name]形式的值绑定到obj指向的map中，有一个存在返回true
+func (c *Context) GetPostFormMapAs(key string, obj any) (bool, error) {
+	c.initFormCache()
+	return binding.MapFormMap(obj, c.formCache, key)
+}
+
 // 私有方法，返回一个满足条件的map
 func (c *Context) get(m map[string][]string, key string) (map[string]string, bool) {
 	dicts := make(map[string]string)
@@ -546,7 +718,7 @@ func (c *Context) get(m map[string][]string, key string) (map[string]string, boo
 func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
 	// 获取file之前，需要对MultipartForm进行固定内存大小的解析，超过固定的内存大小，会将文件存储在磁盘上
 	if c.Request.MultipartForm == nil {
-		if err := c.Request.ParseMultipartForm(c.engine.MaxMultipartMemory); err != nil {
+		if err := c.parseMultipartForm(); err != nil {
 			return nil, err
 		}
 	}
@@ -562,10 +734,176 @@ func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
 // 解析MultipartForm，包括文件上传
 func (c *Context) MultipartForm() (*multipart.Form, error) {
 	// 解析成功的file会保存在c.Request.MultipartForm之中
-	err := c.Request.ParseMultipartForm(c.engine.MaxMultipartMemory)
+	err := c.parseMultipartForm()
 	return c.Request.MultipartForm, err
 }
 
+// 使用MaxMultipartMemory解析multipart form，读取body的同时检查Engine.MultipartLimits，
+// 超过限制时立即停止读取，不会将剩余的body写入临时文件
+func (c *Context) parseMultipartForm() error {
+	req := c.Request
+	if req.MultipartForm != nil {
+		return req.ParseMultipartForm(c.engine.MaxMultipartMemory)
+	}
+
+	limits := c.engine.MultipartLimits
+	if limits.MaxTotalSize > 0 && req.Body != nil {
+		req.Body = http.MaxBytesReader(c.Writer, req.Body, limits.MaxTotalSize)
+	}
+	var limiter *multipartLimitReader
+	if (limits.MaxParts > 0 || limits.MaxFileSize > 0) && req.Body != nil {
+		if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+			limiter = newMultipartLimitReader(req.Body, params["boundary"], limits)
+			req.Body = limiter
+		}
+	}
+	err := req.ParseMultipartForm(c.engine.MaxMultipartMemory)
+	if limiter != nil {
+		limiter.stop()
+		if limitErr := limiter.limitError(); limitErr != nil {
+			// 超过限制时删除已经写入磁盘的临时文件，并清空解析出的值
+			if req.MultipartForm != nil {
+				req.MultipartForm.RemoveAll() //nolint: errcheck
+			}
+			req.MultipartForm = &multipart.Form{Value: map[string][]string{}, File: map[string][]*multipart.FileHeader{}}
+			req.PostForm = url.Values{}
+			return limitErr
+		}
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return fmt.Errorf("%w: limit is %d bytes", ErrMultipartTooLarge, limits.MaxTotalSize)
+		}
+		return err
+	}
+	return nil
+}
+
+// 在form和multipart binding之前使用parseMultipartForm解析multipart body，
+// binding中的ParseMultipartForm随之直接返回，后续的FormFile、MultipartForm也使用同一个受限制的form
+func (c *Context) parseBindingMultipartForm(b binding.Binding) error {
+	if c.engine == nil || c.Request == nil || c.Request.MultipartForm != nil {
+		return nil
+	}
+	if name := b.Name(); name != "form" && name != "multipart/form-data" {
+		return nil
+	}
+	if c.ContentType() != binding.MIMEMultipartPOSTForm {
+		return nil
+	}
+	if err := c.parseMultipartForm(); err != nil {
+		return &binding.Error{Binding: b.Name(), Kind: binding.ErrDecode, Err: err}
+	}
+	return nil
+}
+
+// 在读取body的同时检查part数量以及单个文件的大小：读取的数据同步交给另一个multipart.Reader解析，
+// 超过限制时Read返回错误，ParseMultipartForm随之停止
+type multipartLimitReader struct {
+	body io.ReadCloser
+	pw   *io.PipeWriter
+	done chan struct{}
+	// 超过限制的错误，done关闭之后读取
+	err error
+}
+
+// 返回检查limits的reader，开始解析body的副本
+func newMultipartLimitReader(body io.ReadCloser, boundary string, limits MultipartLimits) *multipartLimitReader {
+	pr, pw := io.Pipe()
+	r := &multipartLimitReader{body: body, pw: pw, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		// 超过限制时使后续的写入失败，否则读取剩余的数据，避免写入阻塞；
+		// body格式错误由ParseMultipartForm处理
+		if err := scanMultipartLimits(multipart.NewReader(pr, boundary), limits); isMultipartLimitError(err) {
+			r.err = err
+			pr.CloseWithError(err)
+			return
+		}
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	return r
+}
+
+// 实现io.Reader Read函数接口
+func (r *multipartLimitReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if n > 0 {
+		// 等待解析的goroutine读取数据，超过限制时返回错误
+		if _, werr := r.pw.Write(p[:n]); werr != nil {
+			return 0, werr
+		}
+	}
+	if err == io.EOF {
+		// body结束时等待解析完成，最后一部分数据超过限制时同样返回错误
+		r.pw.Close()
+		<-r.done
+		if r.err != nil {
+			return n, r.err
+		}
+	}
+	return n, err
+}
+
+// 实现io.Closer Close函数接口
+func (r *multipartLimitReader) Close() error {
+	return r.body.Close()
+}
+
+// 停止解析并等待goroutine结束
+func (r *multipartLimitReader) stop() {
+	r.pw.CloseWithError(io.ErrUnexpectedEOF)
+	<-r.done
+}
+
+// 返回超过限制的错误，需要在stop之后调用
+func (r *multipartLimitReader) limitError() error {
+	return r.err
+}
+
+// 依次读取part，检查part数量以及单个文件的大小，body格式错误时返回该错误
+func scanMultipartLimits(reader *multipart.Reader, limits MultipartLimits) error {
+	parts := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		parts++
+		if limits.MaxParts > 0 && parts > limits.MaxParts {
+			return fmt.Errorf("%w: limit is %d", ErrMultipartTooManyParts, limits.MaxParts)
+		}
+		var r io.Reader = part
+		if part.FileName() != "" {
+			r = &uploadCountingReader{r: part, limit: limits.MaxFileSize, filename: part.FileName()}
+		}
+		if _, err = io.Copy(io.Discard, r); err != nil {
+			return err
+		}
+	}
+}
+
+// 删除c.Request和req的multipart form创建的临时文件
+func (c *Context) removeMultipartFiles(req *http.Request) {
+	if c.Request != nil && c.Request.MultipartForm != nil {
+		c.Request.MultipartForm.RemoveAll() //nolint: errcheck
+	}
+	if req != nil && req.MultipartForm != nil && (c.Request == nil || req.MultipartForm != c.Request.MultipartForm) {
+		req.MultipartForm.RemoveAll() //nolint: errcheck
+	}
+}
+
+// 是否为超过MultipartLimits的错误
+func isMultipartLimitError(err error) bool {
+	return errors.Is(err, ErrMultipartTooManyParts) ||
+		errors.Is(err, ErrMultipartFileTooLarge) ||
+		errors.Is(err, ErrMultipartTooLarge)
+}
+
 // 将上传的form file保存在指定的磁盘路径
 func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
 	src, err := file.Open()
@@ -596,8 +934,8 @@ func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error
 //	"application/json" --> JSON binding
 //	"application/xml"  --> XML binding
 func (c *Context) Bind(obj any) error {
-	// 通过Method和Content-Type获取默认的binding engine
-	b := binding.Default(c.Request.Method, c.ContentType())
+	// 通过Method和Content-Type获取默认的binding engine，路由设置了BindingOptions.Binding时使用该binding
+	b := c.defaultBinding()
 	return c.MustBindWith(obj, b)
 }
 
@@ -634,17 +972,17 @@ func (c *Context) BindHeader(obj any) error {
 // binding Uri类型
 func (c *Context) BindUri(obj any) error {
 	if err := c.ShouldBindUri(obj); err != nil {
-		// 出现错误重写status code为400
-		c.AbortWithError(http.StatusBadRequest, err).SetType(ErrorTypeBind)
+		// 出现错误重写status code，默认为400
+		c.abortWithBindError(err)
 		return err
 	}
 	return nil
 }
 
-// 通过指定的binding engine，出现错误重写status code为400，并且调用AbortWithError阻止后续请求
+// 通过指定的binding engine，出现错误重写status code（默认为400，参考Engine.BindErrors），并且调用AbortWithError阻止后续请求
 func (c *Context) MustBindWith(obj any, b binding.Binding) error {
 	if err := c.ShouldBindWith(obj, b); err != nil {
-		c.AbortWithError(http.StatusBadRequest, err).SetType(ErrorTypeBind) //nolint: errcheck
+		c.abortWithBindError(err)
 		return err
 	}
 	return nil
@@ -656,8 +994,8 @@ func (c *Context) MustBindWith(obj any, b binding.Binding) error {
 //	"application/json" --> JSON binding
 //	"application/xml"  --> XML binding
 func (c *Context) ShouldBind(obj any) error {
-	// 通过Method和Content-Type获取默认的binding engine
-	b := binding.Default(c.Request.Method, c.ContentType())
+	// 通过Method和Content-Type获取默认的binding engine，路由设置了BindingOptions.Binding时使用该binding
+	b := c.defaultBinding()
 	return c.ShouldBindWith(obj, b)
 }
 
@@ -698,12 +1036,71 @@ func (c *Context) ShouldBindUri(obj any) error {
 	for _, v := range c.Params {
 		m[v.Key] = []string{v.Value}
 	}
-	return binding.Uri.BindUri(m, obj)
+	// request的context传递给validator，路由设置了BindingOptions时使用路由的validator校验
+	ctx := context.Background()
+	if req := c.bindingRequest(); req != nil {
+		ctx = req.Context()
+	}
+	return binding.Uri.BindUriCtx(ctx, m, obj)
 }
 
 // 通过传入的obj进行参数绑定，obj需要是指针类型，should非强制性，不会报错和阻止请求
 func (c *Context) ShouldBindWith(obj any, b binding.Binding) error {
-	return b.Bind(c.Request, obj)
+	c.checkReleased()
+	// 使用Engine.SetBinding替换的binding
+	b = c.engineBinding(b)
+	// 开启了BodyRewindLimit时，先缓存body并重置c.Request.Body，支持重复绑定
+	if _, ok := b.(binding.BindingBody); ok && c.engine != nil && c.engine.BodyRewindLimit > 0 {
+		body, ok, err := c.rewindBody(c.engine.BodyRewindLimit)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// 绑定之后再次重置，后续的handler仍然可以读取完整的body
+			defer func() { c.Request.Body = io.NopCloser(bytes.NewReader(body)) }()
+		}
+	}
+	// form binding解析multipart body时同样遵守Engine.MultipartLimits
+	if err := c.parseBindingMultipartForm(b); err != nil {
+		return err
+	}
+	// 通过c.Request绑定，request的context会传递给validator，路由设置了BindingOptions时替换validator
+	return b.Bind(c.bindingRequest(), obj)
+}
+
+// 读取并缓存不超过limit的body，同时重置c.Request.Body以便再次读取
+// body超过limit时恢复c.Request.Body并返回false
+func (c *Context) rewindBody(limit int64) ([]byte, bool, error) {
+	if c.Request == nil {
+		return nil, false, nil
+	}
+	if cb, ok := c.Get(BodyBytesKey); ok {
+		if cbb, ok := cb.([]byte); ok {
+			c.Request.Body = io.NopCloser(bytes.NewReader(cbb))
+			return cbb, true, nil
+		}
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, false, nil
+	}
+
+	orig := c.Request.Body
+	body, err := io.ReadAll(io.LimitReader(orig, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > limit {
+		// 超过限制，拼接已读取的部分，保证后续读取的body完整
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), orig), orig}
+		return nil, false, nil
+	}
+
+	c.Set(BodyBytesKey, body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true, nil
 }
 
 // ShouldBindBodyWith和ShouldBindWith作用类似，但是ShouldBindBodyWith会保存request body到context，方便下次使用
@@ -726,10 +1123,81 @@ func (c *Context) ShouldBindBodyWith(obj any, bb binding.BindingBody) (err error
 		// 将body的值写入BodyBytesKey的key/value中
 		c.Set(BodyBytesKey, body)
 	}
+	// Engine.SetBinding替换的binding支持BindingBody时使用替换的binding
+	if override, ok := c.engineBinding(bb).(binding.BindingBody); ok {
+		bb = override
+	}
+	// request的context传递给validator，路由设置了BindingOptions时使用路由的validator校验
+	req := c.bindingRequest()
+	if bc, ok := bb.(binding.BindingBodyCtx); ok {
+		return bc.BindBodyCtx(req.Context(), body, obj)
+	}
+	// binding不支持context时，路由设置了BindingOptions则通过携带validator的request绑定
+	if req != c.Request {
+		// bindingRequest返回的是副本，可以直接替换body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return bb.Bind(req, obj)
+	}
 	// 使用[]body进行值绑定
 	return bb.BindBody(body, obj)
 }
 
+// 依次从uri、header、query和body绑定obj，全部绑定之后统一校验，后绑定的值覆盖先绑定的值；
+// GET和HEAD请求以及没有body的请求不绑定body；body的binding根据Content-Type选择（BindingOptions.Binding优先），
+// 不支持的Content-Type返回binding.ErrUnknownContentType
+//
+//	type UpdateUser struct {
+//		ID    int    `uri:"id" binding:"required"`
+//		Token string `header:"X-Token" binding:"required"`
+//		Name  string `json:"name" binding:"required"`
+//	}
+func (c *Context) ShouldBindAll(obj any) error {
+	m := make(map[string][]string, len(c.Params))
+	for _, v := range c.Params {
+		m[v.Key] = []string{v.Value}
+	}
+	if err := binding.MapFormWithTag(obj, m, "uri"); err != nil {
+		return &binding.Error{Binding: "uri", Kind: binding.ErrDecode, Err: err}
+	}
+
+	// 绑定时不校验，BindingOptions.Binding仍然生效
+	opts := c.bindingOpts
+	noValidation := BindingOptions{DisableValidation: true}
+	if opts != nil {
+		noValidation.Binding = opts.Binding
+	}
+	c.bindingOpts = &noValidation
+	err := c.shouldBindSources(obj)
+	c.bindingOpts = opts
+	if err != nil {
+		return err
+	}
+	return binding.Validate(c.bindingRequest().Context(), obj)
+}
+
+// 绑定header、query和body
+func (c *Context) shouldBindSources(obj any) error {
+	if err := c.ShouldBindHeader(obj); err != nil {
+		return err
+	}
+	if err := c.ShouldBindQuery(obj); err != nil {
+		return err
+	}
+	req := c.Request
+	if req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		return nil
+	}
+	if c.bindingOpts.Binding != nil {
+		return c.ShouldBindWith(obj, c.bindingOpts.Binding)
+	}
+	b, err := binding.Lookup(req.Method, c.ContentType())
+	if err != nil {
+		return err
+	}
+	return c.ShouldBindWith(obj, b)
+}
+
 // ClientIP方法尽可能获取到真实的访问IP，通过调用c.RemoteIP()来检查远程IP是否是受信任的代理。
 // 若是受信任的代理，将尝试解析Engine.RemoteIPHeaders中定义的标头（默认为[X-Forwarded-For, X-Real-Ip]）
 // 若不是受信任的代理，将返回来自Request.RemoteAddr的远程IP
@@ -755,14 +1223,14 @@ func (c *Context) ClientIP() string {
 	if remoteIP == nil {
 		return ""
 	}
-	// 校验是否为可信任的proxy
-	trusted := c.engine.isTrustedProxy(remoteIP)
+	// 校验是否为可信任的proxy，参考Engine.ClientIPStrategy
+	trusted := c.engine.trustRemoteIP(remoteIP)
 
 	// 如果不是信任的ip，直接返回
 	if trusted && c.engine.ForwardedByClientIP && c.engine.RemoteIPHeaders != nil {
 		for _, headerName := range c.engine.RemoteIPHeaders {
 			// 校验header
-			ip, valid := c.engine.validateHeader(c.requestHeader(headerName))
+			ip, valid := c.engine.clientIPFromHeader(c.requestHeader(headerName))
 			if valid {
 				return ip
 			}
@@ -818,11 +1286,13 @@ func bodyAllowedForStatus(status int) bool {
 
 // 设置http status code
 func (c *Context) Status(code int) {
+	c.checkReleased()
 	c.Writer.WriteHeader(code)
 }
 
 // 设置response header
 func (c *Context) Header(key, value string) {
+	c.checkReleased()
 	// 如果value为空，删除header的key
 	if value == "" {
 		c.Writer.Header().Del(key)
@@ -865,7 +1335,21 @@ func (c *Context) SetCookie(name, value string, maxAge int, path, domain string,
 	})
 }
 
-// 返回名为name的cookie，没找到会返回ErrNoCookie错误，返回的cookie是未转义的
+// 将cookie添加到ResponseWriter的header中，可以单独设置SameSite、Expires、Partitioned等属性
+// cookie.Path为空时使用"/"，cookie.SameSite为0时使用SetSameSite设置的值，cookie.Value会和SetCookie一样进行转义
+func (c *Context) SetCookieWithOptions(cookie *http.Cookie) {
+	ck := *cookie
+	if ck.Path == "" {
+		ck.Path = "/"
+	}
+	if ck.SameSite == 0 {
+		ck.SameSite = c.sameSite
+	}
+	ck.Value = url.QueryEscape(ck.Value)
+	http.SetCookie(c.Writer, &ck)
+}
+
+// 返回名为name的cookie，没找到会返回ErrNoCookie错误，返回的cookie是未转义的，无法转义时返回原始值
 // 如果匹配到多个cookie，则只会返回一个
 func (c *Context) Cookie(name string) (string, error) {
 	// 通过name获取cookie
@@ -874,12 +1358,42 @@ func (c *Context) Cookie(name string) (string, error) {
 		return "", err
 	}
 	// 返回未转义的cookie
-	val, _ := url.QueryUnescape(cookie.Value)
-	return val, nil
+	return unescapeCookie(cookie.Value), nil
+}
+
+// 返回未转义的cookie值，无法转义时返回原始值
+func unescapeCookie(value string) string {
+	if val, err := url.QueryUnescape(value); err == nil {
+		return val
+	}
+	return value
+}
+
+// 返回名为name的cookie，不存在时返回def
+func (c *Context) CookieOrDefault(name, def string) string {
+	if val, err := c.Cookie(name); err == nil {
+		return val
+	}
+	return def
+}
+
+// 以map的形式返回请求中的全部cookie，返回的cookie与Cookie相同是未转义的，无法转义时为原始值
+// 如果同名的cookie有多个，则只保留第一个
+func (c *Context) Cookies() map[string]string {
+	cookies := c.Request.Cookies()
+	m := make(map[string]string, len(cookies))
+	for _, cookie := range cookies {
+		if _, ok := m[cookie.Name]; ok {
+			continue
+		}
+		m[cookie.Name] = unescapeCookie(cookie.Value)
+	}
+	return m
 }
 
 // 写入response headers同时render数据
 func (c *Context) Render(code int, r render.Render) {
+	c.checkReleased()
 	// 写入status code
 	c.Status(code)
 
@@ -905,56 +1419,172 @@ func (c *Context) Render(code int, r render.Render) {
 // See http://golang.org/doc/articles/wiki/
 // 通过指定的file name进行HTTP template Render，设置status code，同时设置Content-Type为"text/html"
 func (c *Context) HTML(code int, name string, obj any) {
-	// 获取HTML Render实例
-	instance := c.engine.HTMLRender.Instance(name, obj)
+	// 获取HTML Render实例，存在请求级别模板函数时使用
+	var instance render.Render
+	if r, ok := c.engine.HTMLRender.(render.HTMLFuncsRender); ok && len(c.htmlFuncs) > 0 {
+		instance = r.InstanceWithFuncs(name, obj, c.htmlFuncs)
+	} else {
+		instance = c.engine.HTMLRender.Instance(name, obj)
+	}
 	// 使用HTML Render
 	c.Render(code, instance)
 }
 
+// 设置当前请求使用的模板函数（例如csrf token、当前用户、翻译函数），与已设置的函数合并
+// 函数名需要先通过Engine.SetFuncMap声明，渲染时会覆盖Engine.FuncMap中的同名函数
+func (c *Context) SetHTMLFuncs(funcs template.FuncMap) {
+	merged := make(template.FuncMap, len(c.htmlFuncs)+len(funcs))
+	for k, v := range c.htmlFuncs {
+		merged[k] = v
+	}
+	for k, v := range funcs {
+		merged[k] = v
+	}
+	c.htmlFuncs = merged
+}
+
+// 将name指定的page渲染到layout中，需要通过Engine.LoadHTMLLayouts加载模板
+func (c *Context) HTMLWithLayout(code int, layout, name string, obj any) {
+	r, ok := c.engine.HTMLRender.(render.HTMLLayoutRender)
+	if !ok {
+		_ = c.AbortWithError(http.StatusInternalServerError, ErrHTMLLayoutUnsupported)
+		return
+	}
+	// 存在请求级别模板函数时使用
+	if fr, ok := r.(render.HTMLLayoutFuncsRender); ok && len(c.htmlFuncs) > 0 {
+		c.Render(code, fr.InstanceWithLayoutFuncs(layout, name, obj, c.htmlFuncs))
+		return
+	}
+	c.Render(code, r.InstanceWithLayout(layout, name, obj))
+}
+
 // 生成IndentedJSON在response body，设置Content-Type为"application/json"
 // 使用IndentedJSON()会消耗更多的CPU和带宽，最好使用Context.JSON()来代替
 func (c *Context) IndentedJSON(code int, obj any) {
-	c.Render(code, render.IndentedJSON{Data: obj})
+	c.Render(code, render.IndentedJSON{Data: c.envelopeJSON(code, obj)})
 }
 
 // 生成SecureJSON写入response body，设置Content-Type为"application/json"
+// 优先使用WithSecureJSONPrefix设置的前缀，否则使用Engine.SecureJsonPrefix设置的前缀
 func (c *Context) SecureJSON(code int, obj any) {
-	c.Render(code, render.SecureJSON{Prefix: c.engine.secureJSONPrefix, Data: obj})
+	prefix := c.engine.secureJSONPrefix
+	if c.secureJSONPrefix != nil {
+		prefix = *c.secureJSONPrefix
+	}
+	c.SecureJSONWithPrefix(code, prefix, obj)
+}
+
+// 使用指定的prefix生成SecureJSON写入response body
+func (c *Context) SecureJSONWithPrefix(code int, prefix string, obj any) {
+	c.Render(code, render.SecureJSON{Prefix: prefix, Data: c.envelopeJSON(code, obj)})
+}
+
+// 返回为路由或者路由组设置SecureJSON前缀的middleware，
+// 例如旧版API和新版API在同一个Engine中使用不同的前缀
+//
+//	legacy := router.Group("/v1", gin.WithSecureJSONPrefix(")]}',\n"))
+func WithSecureJSONPrefix(prefix string) HandlerFunc {
+	return func(c *Context) {
+		c.secureJSONPrefix = &prefix
+		c.Next()
+	}
 }
 
 // 生成JSONP写入response body，设置Content-Type为"application/javascript"
+// callback不是合法的标识符或者不在Engine.JSONPAllowedCallbacks中时返回400
 func (c *Context) JSONP(code int, obj any) {
 	callback := c.DefaultQuery("callback", "")
 	if callback == "" {
-		c.Render(code, render.JSON{Data: obj})
+		c.Render(code, render.JSON{Data: c.envelopeJSON(code, obj)})
+		return
+	}
+	if !c.engine.validJSONPCallback(callback) {
+		_ = c.AbortWithError(http.StatusBadRequest, ErrInvalidJSONPCallback)
 		return
 	}
-	c.Render(code, render.JsonpJSON{Callback: callback, Data: obj})
+	c.Render(code, render.JsonpJSON{Callback: callback, Data: c.envelopeJSON(code, obj)})
+}
+
+// JSONP callback的最大长度
+const maxJSONPCallbackLen = 128
+
+// 校验callback是否为合法的标识符，例如cb、jQuery_123或者app.handlers.cb，engine为nil时只校验标识符
+func (engine *Engine) validJSONPCallback(callback string) bool {
+	if engine != nil && len(engine.JSONPAllowedCallbacks) > 0 {
+		for _, allowed := range engine.JSONPAllowedCallbacks {
+			if callback == allowed {
+				return true
+			}
+		}
+		return false
+	}
+	if len(callback) > maxJSONPCallbackLen {
+		return false
+	}
+	for _, part := range strings.Split(callback, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			switch {
+			case r == '_' || r == '$' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+			case i > 0 && '0' <= r && r <= '9':
+			default:
+				return false
+			}
+		}
+	}
+	return true
 }
 
 // 生成JSON写入response body，设置Content-Type为"application/json"
 func (c *Context) JSON(code int, obj any) {
-	c.Render(code, render.JSON{Data: obj})
+	c.Render(code, render.JSON{Data: c.envelopeJSON(code, obj)})
 }
 
 // 生成AsciiJSON写入response body，设置Content-Type为"application/json"
 func (c *Context) AsciiJSON(code int, obj any) {
-	c.Render(code, render.AsciiJSON{Data: obj})
+	c.Render(code, render.AsciiJSON{Data: c.envelopeJSON(code, obj)})
 }
 
 // 生成PureJSON写入response body，设置Content-Type为"application/json"
 func (c *Context) PureJSON(code int, obj any) {
-	c.Render(code, render.PureJSON{Data: obj})
+	c.Render(code, render.PureJSON{Data: c.envelopeJSON(code, obj)})
+}
+
+// 生成可嵌入HTML <script>标签的JSON写入response body，设置Content-Type为"application/json"
+// 与PureJSON相反，会转义<, >, &, U+2028和U+2029
+func (c *Context) JSONInHTML(code int, obj any) {
+	c.Render(code, render.JSONInHTML{Data: obj})
 }
 
-// 生成XML写入response body，设置Content-Type为"application/xml"
+// 生成XML写入response body，设置Content-Type为"application/xml"，使用Engine.XMLOptions编码
 func (c *Context) XML(code int, obj any) {
-	c.Render(code, render.XML{Data: obj})
+	var opts render.XMLOptions
+	if c.engine != nil {
+		opts = c.engine.XMLOptions
+	}
+	c.Render(code, render.XML{Data: obj, Options: opts})
+}
+
+// 使用指定的编码选项生成XML写入response body，可以设置缩进、根元素名、XML声明和namespace
+func (c *Context) XMLWithOptions(code int, obj any, opts render.XMLOptions) {
+	c.Render(code, render.XML{Data: obj, Options: opts})
 }
 
-// 生成YAML写入response body，设置Content-Type为"application/x-yaml"
+// 生成YAML写入response body，设置Content-Type为"application/x-yaml"，使用Engine.YAMLOptions编码
 func (c *Context) YAML(code int, obj any) {
-	c.Render(code, render.YAML{Data: obj})
+	var opts render.YAMLOptions
+	if c.engine != nil {
+		opts = c.engine.YAMLOptions
+	}
+	c.Render(code, render.YAML{Data: obj, Options: opts})
+}
+
+// 使用指定的编码选项生成YAML写入response body，可以设置缩进和文档分隔符，
+// 以及将slice或者channel的元素编码为多个文档
+func (c *Context) YAMLWithOptions(code int, obj any, opts render.YAMLOptions) {
+	c.Render(code, render.YAML{Data: obj, Options: opts})
 }
 
 // 生成TOML写入response body，设置Content-Type为"application/toml"
@@ -967,6 +1597,11 @@ func (c *Context) ProtoBuf(code int, obj any) {
 	c.Render(code, render.ProtoBuf{Data: obj})
 }
 
+// 使用protobuf规范的JSON映射生成JSON写入response body，设置Content-Type为"application/json"，obj需要为proto.Message
+func (c *Context) ProtoJSON(code int, obj any) {
+	c.Render(code, render.ProtoJSON{Data: obj})
+}
+
 // 生成String写入response body，设置Content-Type为"text/plain"
 func (c *Context) String(code int, format string, values ...any) {
 	c.Render(code, render.String{Format: format, Data: values})
@@ -999,11 +1634,70 @@ func (c *Context) DataFromReader(code int, contentLength int64, contentType stri
 	})
 }
 
+// ChunkedOptions DataFromReaderChunked的配置
+type ChunkedOptions struct {
+	// 刷新间隔, 0表示使用默认间隔, 负数表示每次写入后立即刷新
+	FlushInterval time.Duration
+	// 其他的headers
+	Headers map[string]string
+	// 写入完成后是否关闭reader
+	CloseReader bool
+}
+
+// DataFromReaderChunked 默认的刷新间隔
+const defaultChunkedFlushInterval = 100 * time.Millisecond
+
+// 以chunked方式将长度未知的reader写入body stream, 按间隔刷新并在请求取消时停止
+func (c *Context) DataFromReaderChunked(code int, contentType string, reader io.Reader, opts ChunkedOptions) {
+	interval := opts.FlushInterval
+	if interval == 0 {
+		interval = defaultChunkedFlushInterval
+	}
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	c.Render(code, render.Reader{
+		Headers:       opts.Headers,
+		ContentType:   contentType,
+		ContentLength: -1,
+		Reader:        &ctxReader{ctx: ctx, r: reader},
+		FlushInterval: interval,
+		CloseReader:   opts.CloseReader,
+	})
+}
+
+// ctxReader 在context取消后停止读取
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+func (r *ctxReader) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // 将指定的file写入body stream
 func (c *Context) File(filepath string) {
 	http.ServeFile(c.Writer, c.Request, filepath)
 }
 
+// 通过http.ServeContent将content写入body stream，支持Range和条件请求；
+// content为*os.File时，底层连接支持的情况下使用sendfile发送
+func (c *Context) ServeContent(name string, modtime time.Time, content io.ReadSeeker) {
+	http.ServeContent(c.Writer, c.Request, name, modtime, content)
+}
+
 // 将http.FileSystem的file以高效的方式写入body stream
 func (c *Context) FileFromFS(filepath string, fs http.FileSystem) {
 	defer func(old string) {
@@ -1016,6 +1710,88 @@ func (c *Context) FileFromFS(filepath string, fs http.FileSystem) {
 	http.FileServer(fs).ServeHTTP(c.Writer, c.Request)
 }
 
+// FileOptions File/FileFromFS的可选配置
+type FileOptions struct {
+	// 覆盖根据文件扩展名推断的Content-Type
+	ContentType string
+	// Content-Disposition类型, "inline"或"attachment", 为空则不设置
+	Disposition string
+	// Content-Disposition中的filename, 为空时使用文件名
+	Filename string
+	// Cache-Control header
+	CacheControl string
+	// 文件打开失败时的处理函数, 默认根据错误返回404/403/500
+	ErrorHandler func(c *Context, err error)
+}
+
+// 使用options将指定的file写入body stream
+func (c *Context) FileWithOptions(filepath string, opts FileOptions) {
+	c.serveFile(filepath, opts, func() (http.File, error) {
+		return os.Open(filepath)
+	})
+}
+
+// 使用options将http.FileSystem的file写入body stream
+func (c *Context) FileFromFSWithOptions(filepath string, fs http.FileSystem, opts FileOptions) {
+	c.serveFile(filepath, opts, func() (http.File, error) {
+		return fs.Open(filepath)
+	})
+}
+
+// 打开file并通过http.ServeContent写入, 支持Range和条件请求
+func (c *Context) serveFile(name string, opts FileOptions, open func() (http.File, error)) {
+	f, err := open()
+	if err != nil {
+		c.fileError(opts, err)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		c.fileError(opts, err)
+		return
+	}
+	// 不支持目录
+	if stat.IsDir() {
+		c.fileError(opts, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist})
+		return
+	}
+
+	header := c.Writer.Header()
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+	if opts.Disposition != "" {
+		filename := opts.Filename
+		if filename == "" {
+			filename = stat.Name()
+		}
+		header.Set("Content-Disposition", ContentDisposition(opts.Disposition, filename))
+	}
+	if opts.CacheControl != "" {
+		header.Set("Cache-Control", opts.CacheControl)
+	}
+	c.ServeContent(stat.Name(), stat.ModTime(), f)
+}
+
+// 处理file打开失败的错误
+func (c *Context) fileError(opts FileOptions, err error) {
+	if opts.ErrorHandler != nil {
+		opts.ErrorHandler(c, err)
+		return
+	}
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		code = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		code = http.StatusForbidden
+	}
+	_ = c.Error(err)
+	c.AbortWithStatus(code)
+}
+
 var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 
 // 替换quoteEscaper规则中的string
@@ -1025,14 +1801,26 @@ func escapeQuotes(s string) string {
 
 // 将指定的file以高效的方式写入body stream，客户端通过attachment指定filename进行下载
 func (c *Context) FileAttachment(filepath, filename string) {
-	if isASCII(filename) {
-		c.Writer.Header().Set("Content-Disposition", `attachment; filename="`+escapeQuotes(filename)+`"`)
-	} else {
-		c.Writer.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''`+url.QueryEscape(filename))
-	}
+	c.SetDisposition(DispositionAttachment, filename)
 	http.ServeFile(c.Writer, c.Request, filepath)
 }
 
+// 将reader以attachment的方式写入body stream，客户端通过filename进行下载
+// reader实现了io.ReadSeeker时支持Range/If-Range请求，size小于0表示长度未知
+func (c *Context) FileAttachmentFromReader(filename string, size int64, reader io.Reader) {
+	c.SetDisposition(DispositionAttachment, filename)
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		c.ServeContent(filename, time.Time{}, rs)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, size, contentType, reader, nil)
+}
+
 // 将服务器发送事件写入body stream
 func (c *Context) SSEvent(name string, message any) {
 	c.Render(-1, sse.Event{
@@ -1077,7 +1865,16 @@ type Negotiate struct {
 
 // 根据范围内的Content-Type类型调用对应的Render
 func (c *Context) Negotiate(code int, config Negotiate) {
-	switch c.NegotiateFormat(config.Offered...) {
+	format := c.NegotiateFormat(config.Offered...)
+	// 优先使用通过Engine.RegisterRender注册的render
+	if c.engine != nil {
+		if factory, ok := c.engine.renders[format]; ok {
+			c.Render(code, factory(config.Data))
+			return
+		}
+	}
+
+	switch format {
 	case binding.MIMEJSON:
 		data := chooseData(config.JSONData, config.Data)
 		c.JSON(code, data)
@@ -1103,6 +1900,21 @@ func (c *Context) Negotiate(code int, config Negotiate) {
 	}
 }
 
+// 根据Accept header在offered中选择格式渲染data，offered为空时依次使用通过Engine.RegisterRender注册的mime
+// 以及JSON、XML、YAML、TOML，没有可以接受的格式时返回StatusNotAcceptable错误
+func (c *Context) RenderNegotiated(code int, data any, offered ...string) {
+	if len(offered) == 0 {
+		var renderMIMEs []string
+		if c.engine != nil {
+			renderMIMEs = c.engine.renderMIMEs
+		}
+		offered = make([]string, 0, len(renderMIMEs)+4)
+		offered = append(offered, renderMIMEs...)
+		offered = append(offered, binding.MIMEJSON, binding.MIMEXML, binding.MIMEYAML, binding.MIMETOML)
+	}
+	c.Negotiate(code, Negotiate{Offered: offered, Data: data})
+}
+
 // 返回一个可以接受的Accept格式
 func (c *Context) NegotiateFormat(offered ...string) string {
 	assert1(len(offered) > 0, "you must provide at least one offer")
@@ -1146,17 +1958,65 @@ func (c *Context) SetAccepted(formats ...string) {
 
 // 返回是否有c.Request是否有Context和fallback
 func (c *Context) hasRequestContext() bool {
-	// 判断是否有fallback
-	hasFallback := c.engine != nil && c.engine.ContextWithFallback
-	// 判断是否有Context
-	hasRequestContext := c.Request != nil && c.Request.Context() != nil
-	// hasFallback和hasRequestContext与操作
-	return hasFallback && hasRequestContext
+	return c.Request != nil && c.Request.Context() != nil
+}
+
+// 返回附加了key/value的Context，同时更新c.Request.Context()，key为string时同时保存到Keys中，
+// 使得c.Value、c.Request.Context().Value和c.Get的结果保持一致
+func (c *Context) WithValue(key, value any) *Context {
+	if c.Request != nil {
+		ctx := c.Request.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		c.SetRequestContext(context.WithValue(ctx, key, value))
+	}
+	if keyAsString, ok := key.(string); ok {
+		c.Set(keyAsString, value)
+	}
+	return c
+}
+
+// 返回当前Context的copy，与Copy不同的是返回的Context的Request.Context()同样不继承取消和超时，
+// 保留Request Context的值，用于请求结束之后仍需使用cp.Request.Context()的goroutine
+func (c *Context) Detach() *Context {
+	cp := c.Copy()
+	if cp.hasRequestContext() {
+		cp.Request = cp.Request.WithContext(detachedContext{parent: cp.Request.Context()})
+	}
+	return cp
+}
+
+// detachedContext 保留父Context的值但不继承取消和超时，用于Detach返回的Context
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (detachedContext) Done() <-chan struct{}                   { return nil }
+func (detachedContext) Err() error                              { return nil }
+func (d detachedContext) Value(key any) any                     { return d.parent.Value(key) }
+
+// 使用ctx替换c.Request的Context，后续的middleware和handler通过c.Request.Context()、
+// c.RequestContext()或者c本身都可以获取到ctx；c.Request为nil或者ctx为nil时不做处理
+func (c *Context) SetRequestContext(ctx context.Context) {
+	if c.Request == nil || ctx == nil {
+		return
+	}
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// 返回c.Request的Context，c.Request为nil时返回context.Background()
+func (c *Context) RequestContext() context.Context {
+	if !c.hasRequestContext() {
+		return context.Background()
+	}
+	return c.Request.Context()
 }
 
 // 当c.Request没有Context时，返回context.Deadline()的值
 func (c *Context) Deadline() (deadline time.Time, ok bool) {
-	if !c.hasRequestContext() {
+	if c.copied || !c.hasRequestContext() {
 		return
 	}
 	// 返回context.Deadline
@@ -1165,7 +2025,7 @@ func (c *Context) Deadline() (deadline time.Time, ok bool) {
 
 // 当c.Request没有Context时，返回context.Done()的值
 func (c *Context) Done() <-chan struct{} {
-	if !c.hasRequestContext() {
+	if c.copied || !c.hasRequestContext() {
 		return nil
 	}
 	return c.Request.Context().Done()
@@ -1173,7 +2033,7 @@ func (c *Context) Done() <-chan struct{} {
 
 // 当c.Request没有Context时，返回context.Err()的值
 func (c *Context) Err() error {
-	if !c.hasRequestContext() {
+	if c.copied || !c.hasRequestContext() {
 		return nil
 	}
 	return c.Request.Context().Err()
@@ -7,6 +7,7 @@ package gin
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"html/template"
@@ -15,16 +16,17 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
 	testdata "github.com/gin-gonic/gin/testdata/protoexample"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
@@ -90,6 +92,188 @@ func TestContextFormFile(t *testing.T) {
 	assert.NoError(t, c.SaveUploadedFile(f, "test"))
 }
 
+func TestContextMultipartLimits(t *testing.T) {
+	newContext := func(limits MultipartLimits) *Context {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		must(mw.WriteField("foo", "bar"))
+		w, err := mw.CreateFormFile("file", "test")
+		must(err)
+		_, err = w.Write([]byte("0123456789"))
+		must(err)
+		mw.Close()
+
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.engine.MultipartLimits = limits
+		c.Request, _ = http.NewRequest("POST", "/", buf)
+		c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+		return c
+	}
+
+	c := newContext(MultipartLimits{MaxParts: 2, MaxFileSize: 10, MaxTotalSize: 1 << 20})
+	f, err := c.FormFile("file")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), f.Size)
+	assert.Equal(t, "bar", c.PostForm("foo"))
+
+	c = newContext(MultipartLimits{MaxParts: 1})
+	_, err = c.MultipartForm()
+	assert.ErrorIs(t, err, ErrMultipartTooManyParts)
+	_, err = c.FormFile("file")
+	assert.ErrorIs(t, err, http.ErrMissingFile)
+
+	c = newContext(MultipartLimits{MaxFileSize: 9})
+	_, err = c.FormFile("file")
+	assert.ErrorIs(t, err, ErrMultipartFileTooLarge)
+
+	c = newContext(MultipartLimits{MaxTotalSize: 16})
+	_, err = c.MultipartForm()
+	assert.ErrorIs(t, err, ErrMultipartTooLarge)
+
+	c = newContext(MultipartLimits{MaxParts: 1})
+	assert.Equal(t, "", c.PostForm("foo"))
+}
+
+func TestContextMultipartLimitsBinding(t *testing.T) {
+	type form struct {
+		Foo string `form:"foo"`
+	}
+	newContext := func(limits MultipartLimits) *Context {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		must(mw.WriteField("foo", "bar"))
+		w, err := mw.CreateFormFile("file", "test")
+		must(err)
+		_, err = w.Write([]byte("0123456789"))
+		must(err)
+		mw.Close()
+
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.engine.MultipartLimits = limits
+		c.Request, _ = http.NewRequest("POST", "/", buf)
+		c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+		return c
+	}
+
+	// 先绑定再调用FormFile，两者都遵守MultipartLimits
+	for _, b := range []binding.Binding{binding.Form, binding.FormMultipart} {
+		c := newContext(MultipartLimits{MaxFileSize: 9})
+		var obj form
+		err := c.ShouldBindWith(&obj, b)
+		assert.ErrorIs(t, err, ErrMultipartFileTooLarge, b.Name())
+		assert.Empty(t, obj.Foo)
+		_, err = c.FormFile("file")
+		assert.ErrorIs(t, err, http.ErrMissingFile, b.Name())
+
+		c = newContext(MultipartLimits{MaxFileSize: 10})
+		assert.NoError(t, c.ShouldBindWith(&obj, b))
+		assert.Equal(t, "bar", obj.Foo)
+		f, err := c.FormFile("file")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), f.Size)
+	}
+
+	c := newContext(MultipartLimits{MaxParts: 1})
+	var obj form
+	assert.ErrorIs(t, c.ShouldBind(&obj), ErrMultipartTooManyParts)
+}
+
+// 记录读取的字节数
+type countingBody struct {
+	r io.Reader
+	n int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += n
+	return n, err
+}
+
+func TestContextMultipartLimitsWhileReading(t *testing.T) {
+	newContext := func(limits MultipartLimits, write func(mw *multipart.Writer)) (*Context, *countingBody, int) {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		write(mw)
+		mw.Close()
+
+		body := &countingBody{r: bytes.NewReader(buf.Bytes())}
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.engine.MultipartLimits = limits
+		c.Request, _ = http.NewRequest("POST", "/", body)
+		c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+		return c, body, buf.Len()
+	}
+
+	// 超过限制之后停止读取剩余的body
+	c, body, size := newContext(MultipartLimits{MaxFileSize: 1 << 10}, func(mw *multipart.Writer) {
+		w, err := mw.CreateFormFile("file", "big")
+		must(err)
+		_, err = w.Write(bytes.Repeat([]byte("a"), 4<<20))
+		must(err)
+	})
+	_, err := c.MultipartForm()
+	assert.ErrorIs(t, err, ErrMultipartFileTooLarge)
+	assert.Less(t, body.n, size/2)
+
+	c, body, size = newContext(MultipartLimits{MaxParts: 10}, func(mw *multipart.Writer) {
+		for i := 0; i < 10000; i++ {
+			must(mw.WriteField("field", "value"))
+		}
+	})
+	_, err = c.MultipartForm()
+	assert.ErrorIs(t, err, ErrMultipartTooManyParts)
+	assert.Less(t, body.n, size/2)
+	assert.Empty(t, c.Request.MultipartForm.Value)
+
+	// 格式错误的body仍然返回ParseMultipartForm的错误
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.engine.MultipartLimits = MultipartLimits{MaxParts: 10}
+	c.Request, _ = http.NewRequest("POST", "/", strings.NewReader("--x\r\nbroken"))
+	c.Request.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	_, err = c.MultipartForm()
+	assert.Error(t, err)
+	assert.False(t, isMultipartLimitError(err))
+}
+
+func TestContextMultipartAutoCleanup(t *testing.T) {
+	newRequest := func() *http.Request {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		w, err := mw.CreateFormFile("file", "test")
+		must(err)
+		_, err = w.Write([]byte("0123456789"))
+		must(err)
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/upload", buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+
+	for _, cleanup := range []bool{true, false} {
+		var tmpFiles []string
+		router := New()
+		router.MaxMultipartMemory = 1
+		router.MultipartAutoCleanup = cleanup
+		router.POST("/upload", func(c *Context) {
+			// 替换c.Request之后解析的form不会被net/http清理
+			c.Request = c.Request.WithContext(context.Background())
+			fh, err := c.FormFile("file")
+			must(err)
+			f, err := fh.Open()
+			must(err)
+			tmpFiles = append(tmpFiles, f.(*os.File).Name())
+			f.Close()
+		})
+		router.ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		assert.Len(t, tmpFiles, 1)
+		_, err := os.Stat(tmpFiles[0])
+		assert.Equal(t, cleanup, os.IsNotExist(err))
+		os.Remove(tmpFiles[0])
+	}
+}
+
 func TestContextMultipartForm(t *testing.T) {
 	buf := new(bytes.Buffer)
 	mw := multipart.NewWriter(buf)
@@ -190,6 +374,31 @@ func TestContextHandlers(t *testing.T) {
 
 // TestContextSetGet tests that a parameter is set correctly on the
 // current context and can be retrieved using Get.
+func TestContextGetOrSet(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	var calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value := c.GetOrSet("token", func() any {
+				atomic.AddInt32(&calls, 1)
+				return "parsed"
+			})
+			assert.Equal(t, "parsed", value)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, "parsed", c.MustGet("token"))
+
+	c.Set("tenant", "existing")
+	assert.Equal(t, "existing", c.GetOrSet("tenant", func() any { return "computed" }))
+}
+
 func TestContextSetGet(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Set("foo", "bar")
@@ -521,6 +730,55 @@ func TestContextQueryAndPostForm(t *testing.T) {
 	assert.Equal(t, 0, len(dicts))
 }
 
+func TestContextGetQueryMapAs(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?qty[a]=1&qty[b]=2&tags[x]=a&tags[x]=b&nested[a][b]=3&nested[a][c]=4", nil)
+
+	var qty map[string]int
+	ok, err := c.GetQueryMapAs("qty", &qty)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, qty)
+
+	var tags map[string][]string
+	ok, err = c.GetQueryMapAs("tags", &tags)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"x": {"a", "b"}}, tags)
+
+	var nested map[string]map[string]int
+	ok, err = c.GetQueryMapAs("nested", &nested)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int{"a": {"b": 3, "c": 4}}, nested)
+
+	var nokey map[string]int
+	ok, err = c.GetQueryMapAs("nokey", &nokey)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+	assert.Nil(t, nokey)
+
+	var wrong map[string]int
+	_, err = c.GetQueryMapAs("tags", &wrong)
+	assert.Error(t, err)
+
+	_, err = c.GetQueryMapAs("qty", qty)
+	assert.Error(t, err)
+}
+
+func TestContextGetPostFormMapAs(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	body := bytes.NewBufferString("qty[a]=1&qty[b]=2")
+	c.Request, _ = http.NewRequest("POST", "/", body)
+	c.Request.Header.Add("Content-Type", MIMEPOSTForm)
+
+	var qty map[string]uint
+	ok, err := c.GetPostFormMapAs("qty", &qty)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint{"a": 1, "b": 2}, qty)
+}
+
 func TestContextPostFormMultipart(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request = createMultipartRequest()
@@ -627,6 +885,25 @@ func TestContextSetCookiePathEmpty(t *testing.T) {
 	assert.Equal(t, "user=gin; Path=/; Domain=localhost; Max-Age=1; HttpOnly; Secure; SameSite=Lax", c.Writer.Header().Get("Set-Cookie"))
 }
 
+func TestContextSetCookieWithOptions(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookieWithOptions(&http.Cookie{
+		Name:     "user",
+		Value:    "gin",
+		Expires:  time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+		SameSite: http.SameSiteStrictMode,
+		Secure:   true,
+	})
+	c.SetCookieWithOptions(&http.Cookie{Name: "lang", Value: "zh cn", Domain: "localhost"})
+
+	cookies := c.Writer.Header().Values("Set-Cookie")
+	assert.Equal(t, []string{
+		"user=gin; Path=/; Expires=Wed, 02 Jan 2030 03:04:05 GMT; Secure; SameSite=Strict",
+		"lang=zh+cn; Path=/; Domain=localhost; SameSite=Lax",
+	}, cookies)
+}
+
 func TestContextGetCookie(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request, _ = http.NewRequest("GET", "/get", nil)
@@ -638,6 +915,23 @@ func TestContextGetCookie(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestContextCookies(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/get", nil)
+	c.Request.Header.Set("Cookie", "user=gin; lang=zh+cn; user=other")
+
+	assert.Equal(t, map[string]string{"user": "gin", "lang": "zh cn"}, c.Cookies())
+	assert.Equal(t, "zh cn", c.CookieOrDefault("lang", "en"))
+	assert.Equal(t, "en", c.CookieOrDefault("nokey", "en"))
+
+	// 无法转义的cookie保留原始值
+	c.Request.Header.Set("Cookie", "user=gin; token=%zz")
+	assert.Equal(t, map[string]string{"user": "gin", "token": "%zz"}, c.Cookies())
+	token, err := c.Cookie("token")
+	assert.NoError(t, err)
+	assert.Equal(t, "%zz", token)
+}
+
 func TestContextBodyAllowedForStatus(t *testing.T) {
 	assert.False(t, false, bodyAllowedForStatus(http.StatusProcessing))
 	assert.False(t, false, bodyAllowedForStatus(http.StatusNoContent))
@@ -690,6 +984,58 @@ func TestContextRenderJSONP(t *testing.T) {
 	assert.Equal(t, "application/javascript; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderJSONPWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+	c.Request, _ = http.NewRequest("GET", "http://example.com/?callback=x", nil)
+
+	c.JSONP(http.StatusCreated, H{"foo": "bar"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "x({\"foo\":\"bar\"});", w.Body.String())
+
+	w = httptest.NewRecorder()
+	c = createContextWithoutEngine(w)
+	c.Request, _ = http.NewRequest("GET", "http://example.com/?callback=alert(1)//", nil)
+	c.JSONP(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestContextRenderJSONPInvalidCallback(t *testing.T) {
+	for _, callback := range []string{"alert(1)//", "a.", "1cb", "cb%3Cscript%3E", strings.Repeat("a", 129)} {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "http://example.com/?callback="+callback, nil)
+
+		c.JSONP(http.StatusOK, H{"foo": "bar"})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, callback)
+		assert.Empty(t, w.Body.String())
+		assert.ErrorIs(t, c.Errors.Last(), ErrInvalidJSONPCallback)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "http://example.com/?callback=app.handlers.$cb_1", nil)
+	c.JSONP(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, "app.handlers.$cb_1({\"foo\":\"bar\"});", w.Body.String())
+}
+
+func TestContextRenderJSONPAllowedCallbacks(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, router := CreateTestContext(w)
+	router.JSONPAllowedCallbacks = []string{"onData"}
+	c.Request, _ = http.NewRequest("GET", "http://example.com/?callback=other", nil)
+	c.JSONP(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.engine = router
+	c.Request, _ = http.NewRequest("GET", "http://example.com/?callback=onData", nil)
+	c.JSONP(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, "onData({\"foo\":\"bar\"});", w.Body.String())
+}
+
 // Tests that the response is serialized as JSONP
 // and Content-Type is set to application/json
 func TestContextRenderJSONPWithoutCallback(t *testing.T) {
@@ -730,6 +1076,33 @@ func TestContextRenderAPIJSON(t *testing.T) {
 	assert.Equal(t, "application/vnd.api+json", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderSecureJSONWithPrefix(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.SecureJSONWithPrefix(http.StatusOK, ")]}',\n", []string{"foo"})
+
+	assert.Equal(t, ")]}',\n[\"foo\"]", w.Body.String())
+}
+
+func TestWithSecureJSONPrefix(t *testing.T) {
+	router := New()
+	router.SecureJsonPrefix("while(1);")
+	legacy := router.Group("/v1", WithSecureJSONPrefix("for(;;);"))
+	legacy.GET("/list", func(c *Context) {
+		c.SecureJSON(http.StatusOK, []int{1})
+	})
+	router.GET("/v2/list", func(c *Context) {
+		c.SecureJSON(http.StatusOK, []int{1})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/v1/list")
+	assert.Equal(t, "for(;;);[1]", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/v2/list")
+	assert.Equal(t, "while(1);[1]", w.Body.String())
+}
+
 // Tests that no Custom JSON is rendered if code is 204
 func TestContextRenderNoContentAPIJSON(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -817,6 +1190,15 @@ func TestContextRenderPureJSON(t *testing.T) {
 	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderJSONInHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.JSONInHTML(http.StatusCreated, H{"html": "</script>"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"html":"\u003c/script\u003e"}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
 // Tests that the response executes the templates
 // and responds with Content-Type set to text/html
 func TestContextRenderHTML(t *testing.T) {
@@ -833,6 +1215,30 @@ func TestContextRenderHTML(t *testing.T) {
 	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderHTMLWithFuncs(t *testing.T) {
+	router := New()
+	router.SetFuncMap(template.FuncMap{"csrf": func() string { return "" }})
+	templ := template.Must(template.New("t").Funcs(router.FuncMap).Parse(`token={{csrf}}`))
+	router.SetHTMLTemplate(templ)
+
+	render := func(funcs template.FuncMap) string {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.engine = router
+		if funcs != nil {
+			c.SetHTMLFuncs(funcs)
+		}
+		c.HTML(http.StatusOK, "t", nil)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "token=", render(nil))
+	assert.Equal(t, "token=abc", render(template.FuncMap{"csrf": func() string { return "abc" }}))
+	assert.Equal(t, "token=def", render(template.FuncMap{"csrf": func() string { return "def" }}))
+	// 不影响共享的模板
+	assert.Equal(t, "token=", render(nil))
+}
+
 func TestContextRenderHTML2(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, router := CreateTestContext(w)
@@ -884,6 +1290,41 @@ func TestContextRenderXML(t *testing.T) {
 	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+// 创建没有Engine的Context
+func createContextWithoutEngine(w http.ResponseWriter) *Context {
+	c := &Context{}
+	c.writermem.reset(w)
+	c.Writer = &c.writermem
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	return c
+}
+
+func TestContextRenderXMLWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+
+	c.XML(http.StatusCreated, H{"foo": "bar"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "<map><foo>bar</foo></map>", w.Body.String())
+}
+
+func TestContextRenderXMLWithOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.XMLWithOptions(http.StatusOK, H{"foo": "bar"}, render.XMLOptions{RootName: "response", Header: true})
+
+	assert.Equal(t, xml.Header+"<response><foo>bar</foo></response>", w.Body.String())
+
+	w = httptest.NewRecorder()
+	c, router := CreateTestContext(w)
+	router.XMLOptions = render.XMLOptions{Indent: "  "}
+	c.XML(http.StatusOK, H{"foo": "bar"})
+
+	assert.Equal(t, "<map>\n  <foo>bar</foo>\n</map>", w.Body.String())
+}
+
 // Tests that no XML is rendered if code is 204
 func TestContextRenderNoContentXML(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -1004,6 +1445,38 @@ func TestContextRenderFile(t *testing.T) {
 	assert.NotEqual(t, "", w.Header().Get("Content-Type"))
 }
 
+func TestContextServeContent(t *testing.T) {
+	f, err := os.Open("./gin.go")
+	assert.NoError(t, err)
+	defer f.Close()
+	stat, err := f.Stat()
+	assert.NoError(t, err)
+
+	router := New()
+	router.GET("/gin.go", func(c *Context) {
+		_, _ = f.Seek(0, io.SeekStart)
+		c.ServeContent(stat.Name(), stat.ModTime(), f)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// 通过真实的连接测试ReadFrom路径
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/gin.go", nil)
+	req.Header.Set("Range", "bytes=0-9")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "// Copyrig", string(body))
+	assert.Equal(t, stat.ModTime().UTC().Format(http.TimeFormat), resp.Header.Get("Last-Modified"))
+
+	w := PerformRequest(router, http.MethodGet, "/gin.go")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "func New() *Engine {")
+}
+
 func TestContextRenderFileFromFS(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1019,6 +1492,71 @@ func TestContextRenderFileFromFS(t *testing.T) {
 	assert.Equal(t, "/some/path", c.Request.URL.Path)
 }
 
+func TestContextRenderFileWithOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.FileWithOptions("./gin.go", FileOptions{
+		ContentType:  "text/plain",
+		Disposition:  "inline",
+		CacheControl: "max-age=60",
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "func New() *Engine {")
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="gin.go"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+}
+
+func TestContextRenderFileFromFSWithOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest("GET", "/some/path", nil)
+	c.FileFromFSWithOptions("./gin.go", Dir(".", false), FileOptions{
+		Disposition: "attachment",
+		Filename:    "source.go",
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "func New() *Engine {")
+	assert.Equal(t, `attachment; filename="source.go"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "/some/path", c.Request.URL.Path)
+}
+
+func TestContextRenderFileWithOptionsError(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	c.FileWithOptions("./not_exist.go", FileOptions{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.True(t, c.IsAborted())
+
+	// 目录按不存在处理
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.FileFromFSWithOptions("/testdata", Dir(".", false), FileOptions{})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	var handled error
+	c.FileWithOptions("./not_exist.go", FileOptions{
+		ErrorHandler: func(c *Context, err error) {
+			handled = err
+			c.String(http.StatusTeapot, "missing")
+		},
+	})
+	assert.ErrorIs(t, handled, os.ErrNotExist)
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "missing", w.Body.String())
+}
+
 func TestContextRenderAttachment(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1032,6 +1570,33 @@ func TestContextRenderAttachment(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("attachment; filename=\"%s\"", newFilename), w.Header().Get("Content-Disposition"))
 }
 
+func TestContextRenderAttachmentFromReader(t *testing.T) {
+	body := "attachment content"
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.FileAttachmentFromReader("report.txt", int64(len(body)), io.MultiReader(strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, `attachment; filename="report.txt"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, fmt.Sprint(len(body)), w.Header().Get("Content-Length"))
+
+	// io.ReadSeeker支持Range请求
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Range", "bytes=0-9")
+	c.FileAttachmentFromReader("report.bin", int64(len(body)), strings.NewReader(body))
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, body[:10], w.Body.String())
+	assert.Equal(t, fmt.Sprintf("bytes 0-9/%d", len(body)), w.Header().Get("Content-Range"))
+	assert.Equal(t, `attachment; filename="report.bin"`, w.Header().Get("Content-Disposition"))
+}
+
 func TestContextRenderAndEscapeAttachment(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1056,7 +1621,7 @@ func TestContextRenderUTF8Attachment(t *testing.T) {
 
 	assert.Equal(t, 200, w.Code)
 	assert.Contains(t, w.Body.String(), "func New() *Engine {")
-	assert.Equal(t, `attachment; filename*=UTF-8''`+url.QueryEscape(newFilename), w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `attachment; filename="new__filename.go"; filename*=UTF-8''new%F0%9F%A7%A1_filename.go`, w.Header().Get("Content-Disposition"))
 }
 
 // TestContextRenderYAML tests that the response is serialized as YAML
@@ -1072,6 +1637,30 @@ func TestContextRenderYAML(t *testing.T) {
 	assert.Equal(t, "application/x-yaml; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderYAMLWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+
+	c.YAML(http.StatusCreated, H{"foo": "bar"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "foo: bar\n", w.Body.String())
+}
+
+func TestContextRenderYAMLWithOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.YAMLWithOptions(http.StatusOK, []H{{"id": 1}, {"id": 2}}, render.YAMLOptions{MultiDocument: true, ExplicitStart: true})
+	assert.Equal(t, "---\nid: 1\n---\nid: 2\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	c, router := CreateTestContext(w)
+	router.YAMLOptions = render.YAMLOptions{Indent: 2}
+	c.YAML(http.StatusOK, H{"foo": H{"bar": 1}})
+	assert.Equal(t, "foo:\n  bar: 1\n", w.Body.String())
+}
+
 // TestContextRenderTOML tests that the response is serialized as TOML
 // and Content-Type is set to application/toml
 func TestContextRenderTOML(t *testing.T) {
@@ -1109,6 +1698,23 @@ func TestContextRenderProtoBuf(t *testing.T) {
 	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderProtoJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	label := "test"
+	data := &testdata.Test{
+		Label: &label,
+		Reps:  []int64{int64(1), int64(2)},
+	}
+
+	c.ProtoJSON(http.StatusCreated, data)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"label":"test","reps":["1","2"]}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
 func TestContextHeaders(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Header("Content-Type", "text/plain")
@@ -1331,6 +1937,53 @@ func TestContextNegotiationFormat2(t *testing.T) {
 	assert.Equal(t, "", c.NegotiateFormat("image/tiff"))
 }
 
+func TestContextRenderNegotiatedWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+	c.Request.Header.Add("Accept", "application/x-yaml")
+
+	c.RenderNegotiated(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "foo: bar\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	c = createContextWithoutEngine(w)
+	c.Negotiate(http.StatusOK, Negotiate{Offered: []string{MIMEJSON}, Data: H{"foo": "bar"}})
+	assert.Equal(t, `{"foo":"bar"}`, w.Body.String())
+}
+
+func TestContextRenderNegotiatedRegistered(t *testing.T) {
+	const halJSON = "application/hal+json"
+	newContext := func(accept string) (*Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, r := CreateTestContext(w)
+		r.RegisterRender(halJSON, func(data any) render.Render {
+			return render.Data{ContentType: halJSON, Data: []byte(fmt.Sprintf(`{"_embedded":%q}`, data))}
+		})
+		c.Request, _ = http.NewRequest("GET", "/", nil)
+		c.Request.Header.Add("Accept", accept)
+		return c, w
+	}
+
+	c, w := newContext(halJSON)
+	c.RenderNegotiated(http.StatusOK, "gin")
+	assert.Equal(t, `{"_embedded":"gin"}`, w.Body.String())
+	assert.Equal(t, halJSON, w.Header().Get("Content-Type"))
+
+	c, w = newContext(MIMEXML)
+	c.RenderNegotiated(http.StatusOK, H{"foo": "bar"})
+	assert.Equal(t, "<map><foo>bar</foo></map>", w.Body.String())
+
+	c, w = newContext(halJSON)
+	c.Negotiate(http.StatusOK, Negotiate{Offered: []string{MIMEJSON, halJSON}, Data: "gin"})
+	assert.Equal(t, `{"_embedded":"gin"}`, w.Body.String())
+
+	c, w = newContext("image/png")
+	c.RenderNegotiated(http.StatusOK, "gin")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.True(t, c.IsAborted())
+}
+
 func TestContextIsAborted(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	assert.False(t, c.IsAborted())
@@ -1365,6 +2018,49 @@ type testJSONAbortMsg struct {
 	Bar string `json:"bar"`
 }
 
+func TestContextAbortWithStatusFormats(t *testing.T) {
+	tests := []struct {
+		abort       func(c *Context)
+		contentType string
+		body        string
+	}{
+		{
+			abort:       func(c *Context) { c.AbortWithStatusXML(http.StatusBadRequest, H{"foo": "bar"}) },
+			contentType: "application/xml; charset=utf-8",
+			body:        "<map><foo>bar</foo></map>",
+		},
+		{
+			abort:       func(c *Context) { c.AbortWithStatusYAML(http.StatusBadRequest, H{"foo": "bar"}) },
+			contentType: "application/x-yaml; charset=utf-8",
+			body:        "foo: bar\n",
+		},
+		{
+			abort:       func(c *Context) { c.AbortWithStatusTOML(http.StatusBadRequest, H{"foo": "bar"}) },
+			contentType: "application/toml; charset=utf-8",
+			body:        "foo = 'bar'\n",
+		},
+		{
+			abort: func(c *Context) {
+				c.AbortWithRender(http.StatusBadRequest, render.String{Format: "bad %s", Data: []any{"request"}})
+			},
+			contentType: "text/plain; charset=utf-8",
+			body:        "bad request",
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.index = 4
+
+		tt.abort(c)
+
+		assert.True(t, c.IsAborted())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, tt.body, w.Body.String())
+	}
+}
+
 func TestContextAbortWithStatusJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1576,6 +2272,32 @@ func TestContextClientIP(t *testing.T) {
 	assert.Empty(t, c.ClientIP())
 }
 
+func TestContextClientIPTrustedProxyHops(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", nil)
+	resetContextForClientIPTests(c)
+	_ = c.engine.SetTrustedProxies(nil)
+	c.engine.TrustedProxyHops = 2
+
+	c.Request.Header.Set("X-Forwarded-For", "1.1.1.1, 20.20.20.20, 30.30.30.30")
+	assert.Equal(t, "20.20.20.20", c.ClientIP())
+
+	c.engine.TrustedProxyHops = 1
+	assert.Equal(t, "30.30.30.30", c.ClientIP())
+
+	// 地址数量不足时使用RemoteAddr
+	c.engine.TrustedProxyHops = 4
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+
+	c.engine.TrustedProxyHops = 2
+	c.Request.Header.Set("X-Forwarded-For", "1.1.1.1, unknown, 30.30.30.30")
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+
+	// 未设置时使用SetTrustedProxies
+	c.engine.TrustedProxyHops = 0
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+}
+
 func resetContextForClientIPTests(c *Context) {
 	c.Request.Header.Set("X-Real-IP", " 10.10.10.10  ")
 	c.Request.Header.Set("X-Forwarded-For", "  20.20.20.20, 30.30.30.30")
@@ -1968,6 +2690,83 @@ func TestContextShouldBindBodyWith(t *testing.T) {
 	}
 }
 
+type tenantCtxKey struct{}
+
+// 记录校验时ctx中的值
+type ctxRecordingValidator struct {
+	got []any
+}
+
+func (v *ctxRecordingValidator) ValidateStruct(any) error { return nil }
+
+func (v *ctxRecordingValidator) Engine() any { return nil }
+
+func (v *ctxRecordingValidator) ValidateStructCtx(ctx context.Context, _ any) error {
+	v.got = append(v.got, ctx.Value(tenantCtxKey{}))
+	return nil
+}
+
+func TestContextShouldBindWithRequestContextValidator(t *testing.T) {
+	v := &ctxRecordingValidator{}
+	old := binding.Validator
+	binding.Validator = v
+	defer func() { binding.Validator = old }()
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"foo":"bar"}`))
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), tenantCtxKey{}, "acme"))
+	c.Params = Params{{Key: "id", Value: "1"}}
+
+	var uri struct {
+		ID string `uri:"id"`
+	}
+	assert.NoError(t, c.ShouldBindUri(&uri))
+	assert.Equal(t, "1", uri.ID)
+
+	var body struct {
+		Foo string `json:"foo"`
+	}
+	assert.NoError(t, c.ShouldBindBodyWith(&body, binding.JSON))
+	assert.Equal(t, "bar", body.Foo)
+
+	assert.Equal(t, []any{"acme", "acme"}, v.got)
+}
+
+func TestContextBodyRewindLimit(t *testing.T) {
+	type typeA struct {
+		Foo string `json:"foo" binding:"required"`
+	}
+	type typeB struct {
+		Bar string `json:"bar" binding:"required"`
+	}
+	body := `{"foo":"FOO","bar":"BAR"}`
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.engine.BodyRewindLimit = 1024
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+
+	objA := typeA{}
+	assert.NoError(t, c.ShouldBindJSON(&objA))
+	assert.Equal(t, typeA{"FOO"}, objA)
+	objB := typeB{}
+	assert.NoError(t, c.ShouldBindJSON(&objB))
+	assert.Equal(t, typeB{"BAR"}, objB)
+
+	raw, err := io.ReadAll(c.Request.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(raw))
+
+	// body larger than the limit is read once as before
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.engine.BodyRewindLimit = 4
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+
+	objA = typeA{}
+	assert.NoError(t, c.ShouldBindJSON(&objA))
+	assert.Equal(t, typeA{"FOO"}, objA)
+	assert.Error(t, c.ShouldBindJSON(&typeB{}))
+}
+
 func TestContextGolangContext(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString("{\"foo\":\"bar\", \"bar\":\"foo\"}"))
@@ -2046,6 +2845,40 @@ func TestContextRenderDataFromReader(t *testing.T) {
 	assert.Equal(t, extraHeaders["Content-Disposition"], w.Header().Get("Content-Disposition"))
 }
 
+func TestContextRenderDataFromReaderChunked(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	body := "upstream data of unknown size"
+	c.DataFromReaderChunked(http.StatusOK, "text/plain", io.NopCloser(strings.NewReader(body)), ChunkedOptions{
+		Headers:     map[string]string{"X-Upstream": "1"},
+		CloseReader: true,
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Equal(t, "chunked", w.Header().Get("Transfer-Encoding"))
+	assert.Equal(t, "1", w.Header().Get("X-Upstream"))
+	assert.True(t, w.Flushed)
+}
+
+func TestContextRenderDataFromReaderChunkedCanceled(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Request, _ = http.NewRequestWithContext(ctx, "GET", "/", nil)
+
+	c.DataFromReaderChunked(http.StatusOK, "text/plain", strings.NewReader("data"), ChunkedOptions{})
+
+	assert.Empty(t, w.Body.String())
+	assert.True(t, c.IsAborted())
+	assert.ErrorIs(t, c.Errors.Last(), context.Canceled)
+}
+
 func TestContextRenderDataFromReaderNoHeaders(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -2178,20 +3011,57 @@ func TestRemoteIPFail(t *testing.T) {
 
 func TestHasRequestContext(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
-	assert.False(t, c.hasRequestContext(), "no request, no fallback")
-	c.engine.ContextWithFallback = true
-	assert.False(t, c.hasRequestContext(), "no request, has fallback")
+	assert.False(t, c.hasRequestContext(), "no request")
 	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
-	assert.True(t, c.hasRequestContext(), "has request, has fallback")
+	assert.True(t, c.hasRequestContext(), "has request")
 	c.Request, _ = http.NewRequestWithContext(nil, "", "", nil) //nolint:staticcheck
-	assert.False(t, c.hasRequestContext(), "has request with nil ctx, has fallback")
-	c.engine.ContextWithFallback = false
-	assert.False(t, c.hasRequestContext(), "has request, no fallback")
+	assert.False(t, c.hasRequestContext(), "has request with nil ctx")
 
 	c = &Context{}
 	assert.False(t, c.hasRequestContext(), "no request, no engine")
 	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
-	assert.False(t, c.hasRequestContext(), "has request, no engine")
+	assert.True(t, c.hasRequestContext(), "has request, no engine")
+}
+
+func TestContextWithoutFallbackUsesRequestContext(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	cancel()
+
+	assert.ErrorIs(t, c.Err(), context.Canceled)
+	assert.NotNil(t, <-c.Done())
+
+	// Copy不继承取消，cp.Request.Context()仍然是请求的Context
+	cp := c.Copy()
+	assert.NoError(t, cp.Err())
+	assert.Nil(t, cp.Done())
+	assert.ErrorIs(t, cp.Request.Context().Err(), context.Canceled)
+
+	// Detach的Request.Context()同样不继承取消
+	dp := c.Detach()
+	assert.NoError(t, dp.Err())
+	assert.NoError(t, dp.Request.Context().Err())
+}
+
+func TestContextWithValue(t *testing.T) {
+	type ctxKey struct{}
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	c.WithValue(ctxKey{}, "trace").WithValue("user", "gin")
+
+	assert.Equal(t, "trace", c.Value(ctxKey{}))
+	assert.Equal(t, "trace", c.Request.Context().Value(ctxKey{}))
+	assert.Equal(t, "gin", c.Request.Context().Value("user"))
+	assert.Equal(t, "gin", c.MustGet("user"))
+	assert.Equal(t, "trace", c.Copy().Value(ctxKey{}))
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	c.SetRequestContext(ctx)
+	cancel()
+	assert.ErrorIs(t, c.Err(), context.Canceled)
+	assert.Equal(t, "trace", c.Value(ctxKey{}))
 }
 
 func TestContextWithFallbackDeadlineFromRequestContext(t *testing.T) {
@@ -2318,6 +3188,23 @@ func TestContextWithFallbackValueFromRequestContext(t *testing.T) {
 	}
 }
 
+func TestContextSetRequestContext(t *testing.T) {
+	type ctxKey struct{}
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	assert.Equal(t, context.Background(), c.RequestContext())
+	assert.NotPanics(t, func() { c.SetRequestContext(context.TODO()) })
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx := context.WithValue(c.RequestContext(), ctxKey{}, "span")
+	c.SetRequestContext(ctx)
+	assert.Equal(t, ctx, c.RequestContext())
+	assert.Equal(t, ctx, c.Request.Context())
+	assert.Equal(t, "span", c.Value(ctxKey{}))
+
+	c.SetRequestContext(nil) //nolint:staticcheck
+	assert.Equal(t, ctx, c.RequestContext())
+}
+
 func TestContextCopyShouldNotCancel(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -2407,6 +3294,50 @@ func TestCreateTestContextWithRouteParams(t *testing.T) {
 	assert.Equal(t, "hello gin", w.Body.String())
 }
 
+func TestCreateTestContextRouted(t *testing.T) {
+	w := httptest.NewRecorder()
+	engine := New()
+	engine.Use(func(c *Context) {
+		c.Set("middleware", true)
+		c.Next()
+	})
+	engine.GET("/users/:id", func(c *Context) {
+		_ = c.Error(errors.New("oops"))
+		c.String(http.StatusOK, "user %s", c.Param("id"))
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/42?x=1", nil)
+	c := CreateTestContextRouted(w, engine, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user 42", w.Body.String())
+	assert.Equal(t, "42", c.Param("id"))
+	assert.Equal(t, "/users/:id", c.FullPath())
+	assert.Equal(t, "1", c.Query("x"))
+	assert.True(t, c.GetBool("middleware"))
+	assert.Len(t, c.Errors, 1)
+	assert.Equal(t, http.StatusOK, c.Writer.Status())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/missing", nil)
+	c = CreateTestContextRouted(w, engine, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, c.FullPath())
+
+	// 与ServeHTTP一样执行OnRequest和OnResponse hook
+	var status int
+	engine.OnRequest(func(c *Context) {
+		c.Set("hook", true)
+	}).OnResponse(func(c *Context) {
+		status = c.Writer.Status()
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/users/1", nil)
+	c = CreateTestContextRouted(w, engine, req)
+	assert.True(t, c.GetBool("hook"))
+	assert.Equal(t, http.StatusOK, status)
+}
+
 type interceptedWriter struct {
 	ResponseWriter
 	b *bytes.Buffer
@@ -2445,3 +3376,41 @@ func TestInterceptedHeader(t *testing.T) {
 	assert.Equal(t, "", w.Result().Header.Get("X-Test"))
 	assert.Equal(t, "present", w.Result().Header.Get("X-Test-2"))
 }
+
+func TestContextShouldBindAll(t *testing.T) {
+	type request struct {
+		ID    int    `uri:"id" binding:"required"`
+		Token string `header:"X-Token" binding:"required"`
+		Page  int    `form:"page"`
+		Name  string `json:"name" binding:"required"`
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Params = Params{{Key: "id", Value: "7"}}
+	c.Request, _ = http.NewRequest(http.MethodPut, "/users/7?page=2", strings.NewReader(`{"name":"gin"}`))
+	c.Request.Header.Set("Content-Type", MIMEJSON)
+	c.Request.Header.Set("X-Token", "secret")
+
+	var obj request
+	assert.NoError(t, c.ShouldBindAll(&obj))
+	assert.Equal(t, request{ID: 7, Token: "secret", Page: 2, Name: "gin"}, obj)
+	assert.Nil(t, c.bindingOpts)
+
+	// 全部绑定之后才校验
+	c.Request, _ = http.NewRequest(http.MethodGet, "/users/7", nil)
+	c.Request.Header.Set("X-Token", "secret")
+	obj = request{}
+	err := c.ShouldBindAll(&obj)
+	assert.ErrorIs(t, err, binding.ErrValidation)
+	assert.Contains(t, err.Error(), "Name")
+	assert.Equal(t, 7, obj.ID)
+
+	c.Params = Params{{Key: "id", Value: "x"}}
+	assert.ErrorIs(t, c.ShouldBindAll(&obj), binding.ErrDecode)
+
+	// BindingOptions中的validator用于最后的校验
+	c.Params = Params{{Key: "id", Value: "7"}}
+	c.bindingOpts = &BindingOptions{DisableValidation: true}
+	assert.NoError(t, c.ShouldBindAll(&request{}))
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"strings"
+)
+
+// Content-Disposition的类型
+const (
+	// 浏览器直接显示响应
+	DispositionInline = "inline"
+	// 浏览器下载响应
+	DispositionAttachment = "attachment"
+)
+
+// 生成符合RFC 6266的Content-Disposition header的值，filename为空时只返回disposition；
+// filename包含非ASCII字符、控制字符或者%时，filename使用替换为_的ASCII名称，
+// 并通过RFC 5987编码的filename*提供原始名称，例如
+//
+//	attachment; filename="_.txt"; filename*=UTF-8''%E6%8A%A5.txt
+func ContentDisposition(disposition, filename string) string {
+	if filename == "" {
+		return disposition
+	}
+	fallback := dispositionFallback(filename)
+	value := disposition + `; filename="` + escapeQuotes(fallback) + `"`
+	if fallback != filename {
+		value += "; filename*=UTF-8''" + encodeRFC5987(filename)
+	}
+	return value
+}
+
+// 设置Content-Disposition header，disposition为DispositionInline或者DispositionAttachment
+func (c *Context) SetDisposition(disposition, filename string) {
+	c.Header("Content-Disposition", ContentDisposition(disposition, filename))
+}
+
+// 返回filename的ASCII名称，非ASCII字符、控制字符和%替换为_；
+// RFC 6266建议不在filename中使用%，部分浏览器会将其解码
+func dispositionFallback(filename string) string {
+	var sb strings.Builder
+	for _, r := range filename {
+		if r < 0x20 || r > 0x7e || r == '%' {
+			sb.WriteByte('_')
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// RFC 5987 attr-char中除字母和数字之外的字符
+const rfc5987AttrChars = "!#$&+-.^_`|~"
+
+// 按照RFC 5987编码filename*的值，attr-char之外的字节使用%XX编码
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || strings.IndexByte(rfc5987AttrChars, b) >= 0 {
+			sb.WriteByte(b)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[b>>4])
+		sb.WriteByte(hex[b&0x0f])
+	}
+	return sb.String()
+}
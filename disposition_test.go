@@ -0,0 +1,38 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		disposition string
+		filename    string
+		want        string
+	}{
+		{DispositionAttachment, "", "attachment"},
+		{DispositionInline, "report.pdf", `inline; filename="report.pdf"`},
+		{DispositionAttachment, `a"b\c.txt`, `attachment; filename="a\"b\\c.txt"`},
+		{DispositionAttachment, "报告 2026.pdf", `attachment; filename="__ 2026.pdf"; filename*=UTF-8''%E6%8A%A5%E5%91%8A%202026.pdf`},
+		{DispositionAttachment, "100%.txt", `attachment; filename="100_.txt"; filename*=UTF-8''100%25.txt`},
+		{DispositionAttachment, "a\r\nb.txt", `attachment; filename="a__b.txt"; filename*=UTF-8''a%0D%0Ab.txt`},
+		{DispositionAttachment, "naïve's file.txt", `attachment; filename="na_ve's file.txt"; filename*=UTF-8''na%C3%AFve%27s%20file.txt`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ContentDisposition(tt.disposition, tt.filename), tt.filename)
+	}
+}
+
+func TestContextSetDisposition(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.SetDisposition(DispositionInline, "résumé.pdf")
+	assert.Equal(t, `inline; filename="r_sum_.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`, w.Header().Get("Content-Disposition"))
+}
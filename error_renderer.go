@@ -0,0 +1,95 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ErrorRendererConfig ErrorRenderer middleware的配置
+type ErrorRendererConfig struct {
+	// 只渲染匹配该ErrorType的错误，为0时使用ErrorTypeAny
+	Types ErrorType
+
+	// ErrorType到status code的映射，按最后一个错误的类型匹配，优先完全相等的类型；
+	// 未匹配时ErrorTypeBind返回400，其他返回500。响应头已经写入时（例如AbortWithError）使用已写入的status code
+	StatusByType map[ErrorType]int
+
+	// 为true时返回ErrorTypePrivate错误的信息，否则只返回status code对应的文本
+	ExposePrivate bool
+
+	// 内容协商提供的格式，为空时使用JSON、XML、YAML和TOML
+	Offered []string
+
+	// 设置后替代默认的内容协商渲染
+	Render func(c *Context, code int, errs []*Error)
+}
+
+// 默认内容协商提供的格式
+var defaultErrorOffered = []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEYAML, binding.MIMETOML}
+
+// 返回在c.Next()之后将c.Errors渲染为响应的middleware，已经写入body的响应不会被修改；
+// 最后一个错误匹配Engine.RespondOnError注册的matcher时使用对应的responder
+func ErrorRenderer(conf ErrorRendererConfig) HandlerFunc {
+	types := conf.Types
+	if types == 0 {
+		types = ErrorTypeAny
+	}
+	offered := conf.Offered
+	if len(offered) == 0 {
+		offered = defaultErrorOffered
+	}
+
+	return func(c *Context) {
+		c.Next()
+
+		errs := c.Errors.ByType(types)
+		if len(errs) == 0 || c.Writer.Size() > 0 {
+			return
+		}
+
+		// 优先使用Engine.RespondOnError注册的响应
+		if respond := c.errorResponder(errs.Last().Err); respond != nil {
+			respond(c, errs.Last().Err)
+			return
+		}
+		code := conf.status(c, errs.Last())
+		if conf.Render != nil {
+			conf.Render(c, code, errs)
+			return
+		}
+
+		list := make([]any, len(errs))
+		for i, err := range errs {
+			if err.IsType(ErrorTypePrivate) && !conf.ExposePrivate {
+				list[i] = H{"error": http.StatusText(code)}
+				continue
+			}
+			list[i] = err.JSON()
+		}
+		c.Negotiate(code, Negotiate{Offered: offered, Data: H{"errors": list}})
+	}
+}
+
+// 获取错误对应的status code
+func (conf ErrorRendererConfig) status(c *Context, last *Error) int {
+	if c.Writer.Written() {
+		return c.Writer.Status()
+	}
+	if code, ok := conf.StatusByType[last.Type]; ok {
+		return code
+	}
+	for typ, code := range conf.StatusByType {
+		if last.IsType(typ) {
+			return code
+		}
+	}
+	if last.IsType(ErrorTypeBind) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorRenderer(t *testing.T) {
+	router := New()
+	router.Use(ErrorRenderer(ErrorRendererConfig{}))
+	router.GET("/private", func(c *Context) {
+		_ = c.Error(errors.New("db password leaked"))
+	})
+	router.GET("/public", func(c *Context) {
+		_ = c.Error(errors.New("name is required")).SetType(ErrorTypePublic | ErrorTypeBind)
+	})
+	router.GET("/abort", func(c *Context) {
+		_ = c.AbortWithError(http.StatusForbidden, errors.New("forbidden"))
+	})
+	router.GET("/written", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+		_ = c.Error(errors.New("after write"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/private")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"errors":[{"error":"Internal Server Error"}]}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/public")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"errors":[{"error":"name is required"}]}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/public", header{Key: "Accept", Value: MIMEXML})
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<error>name is required</error>")
+
+	w = PerformRequest(router, http.MethodGet, "/abort")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"errors":[{"error":"Forbidden"}]}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/written")
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestErrorRendererConfig(t *testing.T) {
+	var rendered []*Error
+	router := New()
+	router.Use(ErrorRenderer(ErrorRendererConfig{
+		Types:         ErrorTypePublic,
+		StatusByType:  map[ErrorType]int{ErrorTypePublic: http.StatusUnprocessableEntity},
+		ExposePrivate: true,
+	}))
+	router.GET("/", func(c *Context) {
+		_ = c.Error(errors.New("private"))
+		_ = c.Error(errors.New("public")).SetType(ErrorTypePublic)
+	})
+	router.GET("/private", func(c *Context) {
+		_ = c.Error(errors.New("private"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"errors":[{"error":"public"}]}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/private")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	router = New()
+	router.Use(ErrorRenderer(ErrorRendererConfig{
+		Render: func(c *Context, code int, errs []*Error) {
+			rendered = errs
+			c.String(code, "custom")
+		},
+	}))
+	router.GET("/", func(c *Context) {
+		_ = c.Error(errors.New("boom"))
+	})
+	w = PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "custom", w.Body.String())
+	assert.Len(t, rendered, 1)
+}
+
+func TestErrorRendererRespondOnError(t *testing.T) {
+	errMissing := errors.New("missing")
+	router := New()
+	router.RespondOnError(ErrorIs(errMissing), func(c *Context, err error) {
+		c.String(http.StatusNotFound, "custom: %v", err)
+	})
+	router.Use(ErrorRenderer(ErrorRendererConfig{}))
+	router.GET("/missing", func(c *Context) {
+		_ = c.Error(errors.New("other"))
+		_ = c.Error(errMissing)
+	})
+	router.GET("/other", func(c *Context) {
+		_ = c.Error(errMissing)
+		_ = c.Error(errors.New("other"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "custom: missing", w.Body.String())
+
+	// 只匹配最后一个错误
+	w = PerformRequest(router, http.MethodGet, "/other")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
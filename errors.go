@@ -5,9 +5,11 @@
 package gin
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin/internal/json"
 	"reflect"
+	"runtime"
 	"strings"
 )
 
@@ -34,6 +36,35 @@ type Error struct {
 	Err  error
 	Type ErrorType
 	Meta any
+	// Engine.CaptureErrorStack为true时，调用Context.Error记录的调用栈，格式为"函数 文件:行号"
+	Stack []string
+}
+
+// 记录的调用栈最大帧数
+const maxErrorStackDepth = 32
+
+// 记录调用栈，跳过skip帧，略过Context.Next和Context.AbortWithError，并在进入gin的路由处理或net/http时停止
+func captureStack(skip int) []string {
+	pcs := make([]uintptr, maxErrorStackDepth)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, "net/http.") ||
+			strings.HasSuffix(frame.Function, "gin.(*Engine).handleHTTPRequest") {
+			break
+		}
+		if !strings.HasSuffix(frame.Function, "gin.(*Context).Next") &&
+			!strings.HasSuffix(frame.Function, "gin.(*Context).AbortWithError") {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
 }
 
 // Error列表
@@ -75,6 +106,12 @@ func (msg *Error) JSON() any {
 	if _, ok := jsonData["error"]; !ok {
 		jsonData["error"] = msg.Error()
 	}
+	// debug模式下返回调用栈
+	if len(msg.Stack) > 0 && IsDebugging() {
+		if _, ok := jsonData["stack"]; !ok {
+			jsonData["stack"] = msg.Stack
+		}
+	}
 	return jsonData
 }
 
@@ -118,6 +155,54 @@ func (a errorMsgs) ByType(typ ErrorType) errorMsgs {
 	return result
 }
 
+// 返回包含的全部错误，与errors.Join生成的错误一样可以遍历错误树
+func (a errorMsgs) Unwrap() []error {
+	if len(a) == 0 {
+		return nil
+	}
+	errs := make([]error, len(a))
+	for i, msg := range a {
+		errs[i] = msg
+	}
+	return errs
+}
+
+// 将errorMsgs合并为一个error，为空时返回nil，可以直接用于errors.Is和errors.As
+func (a errorMsgs) Err() error {
+	return errors.Join(a.Unwrap()...)
+}
+
+// 判断是否有错误与target匹配（errors.Is）
+func (a errorMsgs) Is(target error) bool {
+	for _, msg := range a {
+		if errors.Is(msg, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// 查找第一个与target匹配的错误并赋值给target（errors.As）
+func (a errorMsgs) As(target any) bool {
+	for _, msg := range a {
+		if errors.As(msg, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// 返回包装了target的错误（errors.Is）
+func (a errorMsgs) ByError(target error) errorMsgs {
+	var result errorMsgs
+	for _, msg := range a {
+		if errors.Is(msg, target) {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
 // 返回errorMsgs最后一位的Error元素，如果errorMsgs为空则返回nil
 func (a errorMsgs) Last() *Error {
 	if length := len(a); length > 0 {
@@ -171,6 +256,9 @@ func (a errorMsgs) String() string {
 		if msg.Meta != nil {
 			fmt.Fprintf(&buffer, "     Meta: %v\n", msg.Meta)
 		}
+		for _, frame := range msg.Stack {
+			fmt.Fprintf(&buffer, "     at %s\n", frame)
+		}
 	}
 	// 返回buffer的字符串
 	return buffer.String()
@@ -126,3 +126,67 @@ func TestErrorUnwrap(t *testing.T) {
 	var testErr TestErr
 	assert.True(t, errors.As(err, &testErr))
 }
+
+func errorStackHandler(c *Context) {
+	_ = c.Error(errors.New("boom"))
+}
+
+func TestErrorCaptureStack(t *testing.T) {
+	var captured *Error
+	router := New()
+	router.CaptureErrorStack = true
+	router.Use(func(c *Context) {
+		c.Next()
+		captured = c.Errors.Last()
+	})
+	router.GET("/", errorStackHandler)
+	PerformRequest(router, "GET", "/")
+
+	assert.NotEmpty(t, captured.Stack)
+	assert.Contains(t, captured.Stack[0], "gin.errorStackHandler")
+	assert.Contains(t, captured.Stack[0], "errors_test.go")
+	for _, frame := range captured.Stack {
+		assert.NotContains(t, frame, "(*Context).Next")
+		assert.NotContains(t, frame, "net/http")
+	}
+	assert.Contains(t, errorMsgs{captured}.String(), "     at "+captured.Stack[0]+"\n")
+
+	SetMode(DebugMode)
+	assert.Equal(t, captured.Stack, captured.JSON().(H)["stack"])
+	SetMode(ReleaseMode)
+	assert.NotContains(t, captured.JSON(), "stack")
+	SetMode(TestMode)
+
+	// 未开启时不记录
+	router.CaptureErrorStack = false
+	PerformRequest(router, "GET", "/")
+	assert.Nil(t, captured.Stack)
+}
+
+func TestErrorSliceUnwrap(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errs := errorMsgs{
+		{Err: fmt.Errorf("user: %w", errNotFound), Type: ErrorTypePrivate},
+		{Err: TestErr("bad input"), Type: ErrorTypePublic},
+		{Err: errors.Join(errors.New("a"), errNotFound), Type: ErrorTypePrivate},
+	}
+
+	assert.Len(t, errs.Unwrap(), 3)
+	assert.True(t, errs.Is(errNotFound))
+	assert.False(t, errs.Is(errors.New("other")))
+
+	var testErr TestErr
+	assert.True(t, errs.As(&testErr))
+	assert.Equal(t, TestErr("bad input"), testErr)
+
+	assert.Equal(t, errorMsgs{errs[0], errs[2]}, errs.ByError(errNotFound))
+	assert.Nil(t, errs.ByError(errors.New("other")))
+
+	err := errs.Err()
+	assert.ErrorIs(t, err, errNotFound)
+	assert.ErrorAs(t, err, &testErr)
+	assert.Equal(t, "user: not found\nbad input\na\nnot found", err.Error())
+
+	assert.NoError(t, errorMsgs{}.Err())
+	assert.Nil(t, errorMsgs{}.Unwrap())
+}
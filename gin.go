@@ -5,19 +5,26 @@
 package gin
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin/internal/bytesconv"
 	"html/template"
+	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/internal/json"
 	"github.com/gin-gonic/gin/render"
-	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 )
 
@@ -27,6 +34,7 @@ const defaultMultipartMemory = 32 << 20
 var (
 	default404Body = []byte("404 page not found")
 	default405Body = []byte("405 method not allowed")
+	default400Body = []byte("400 bad request")
 )
 
 var defaultPlatform string
@@ -143,16 +151,78 @@ type Engine struct {
 	// that platform, for example to determine the client IP
 	TrustedPlatform string
 
+	// ClientIPStrategy为ClientIPDefault或ClientIPRightmostTrusted且大于0时ClientIP不再使用SetTrustedProxies设置的网络判断代理是否可信任，而是信任恰好N层代理：
+	// Request.RemoteAddr视为最近的代理，客户端IP为RemoteIPHeaders中从右往左数第N个地址，
+	// 地址数量不足N个时返回Request.RemoteAddr，适用于固定层数的负载均衡部署
+	TrustedProxyHops int
+
+	// ClientIPStrategy ClientIP从RemoteIPHeaders中获取客户端IP的方式，默认为ClientIPDefault
+	ClientIPStrategy ClientIPStrategy
+
 	// MaxMultipartMemory value of 'maxMemory' param that is given to http.Request's ParseMultipartForm
 	// method call.
 	MaxMultipartMemory int64
 
+	// 解析multipart form时的限制，在PostForm、FormFile、MultipartForm等方法中生效
+	MultipartLimits MultipartLimits
+
+	// 为true时在handler链和Context.Defer注册的函数执行完成后删除解析multipart form时创建的临时文件，
+	// 包括handler替换c.Request之后才解析的form（net/http只会清理原始请求的form）；
+	// 临时文件总是创建在os.TempDir()中，mime/multipart不支持指定目录
+	MultipartAutoCleanup bool
+
+	// Context.XML使用的编码选项，例如缩进和XML声明
+	XMLOptions render.XMLOptions
+
+	// Context.YAML使用的编码选项，例如缩进和文档分隔符
+	YAMLOptions render.YAMLOptions
+
+	// 不为nil时在编码之前转换JSON、IndentedJSON、SecureJSON、JSONP、AsciiJSON和PureJSON的响应，
+	// 例如使用DefaultJSONEnvelope将响应包装为{"data":...,"error":...,"request_id":...}；
+	// 路由组可以通过WithJSONEnvelope覆盖
+	JSONEnvelope JSONEnvelopeFunc
+
 	// 是否启用h2c支持，H2C：不使用TLS加密的http2协议
 	UseH2C bool
 
+	// h2c使用的HTTP/2配置，例如最大并发stream数、空闲超时和帧大小
+	HTTP2Config HTTP2Config
+
 	// ContextWithFallback enable fallback Context.Deadline(), Context.Done(), Context.Err() and Context.Value() when Context.Request.Context() is not nil.
+	//
+	// Deprecated: Context总是使用c.Request.Context()的Deadline、Done、Err和Value，该字段不再生效
 	ContextWithFallback bool
 
+	// 大于0时，第一次通过ShouldBindJSON等方法绑定body时会缓存不超过该大小的body，
+	// 后续的middleware和handler可以重复绑定，无需使用ShouldBindBodyWith；超过该大小的body按照原方式读取
+	BodyRewindLimit int64
+
+	// Bind、BindJSON等方法以及HandlerOf绑定失败时的status code和渲染方式，
+	// 例如校验失败返回422：BindErrorConfig{ValidationStatus: http.StatusUnprocessableEntity}
+	BindErrors BindErrorConfig
+
+	// Logger和Recovery输出中需要隐藏的header、query参数和Logger记录的请求body中的JSON字段，
+	// Authorization、Proxy-Authorization和Cookie header总是被隐藏
+	Redaction Redaction
+
+	// 为true时Context.Error会记录调用栈并保存到Error.Stack，debug模式下Error.JSON()会返回调用栈
+	CaptureErrorStack bool
+
+	// 为true时静态路径段总是优先于同级的参数：请求的路径段与某个静态路由的路径段完全相同时，
+	// 即使该静态路由的后续部分没有匹配，也不会再回滚到参数路由（catch-all不能与静态路由同级，不受影响）。
+	// 例如注册了/users/new和/users/:id/edit，默认/users/new/edit会匹配/users/:id/edit，开启后返回404
+	StrictStaticPriority bool
+
+	// 使用RawPath匹配路由时参数中编码的'/'（%2F）的处理方式，默认为EncodedSlashDecode
+	EncodedSlashPolicy EncodedSlashPolicy
+
+	// 查找路由之前对请求路径进行的Unicode规范化，默认为PathNormalizationNone；
+	// 需要在注册路由之前设置，注册的路由中的静态部分会进行相同的规范化
+	PathNormalization PathNormalization
+
+	// Context.JSONP允许的callback名字，为空时只校验callback是否为合法的标识符
+	JSONPAllowedCallbacks []string
+
 	delims           render.Delims
 	secureJSONPrefix string
 	HTMLRender       render.HTMLRender
@@ -168,6 +238,52 @@ type Engine struct {
 	maxSections    uint16
 	trustedProxies []string
 	trustedCIDRs   []*net.IPNet
+	grpcHandler    http.Handler
+	renders        map[string]func(data any) render.Render
+	bindings       map[string]binding.Binding
+	// 通过SetMsgPackHandle设置的*codec.MsgpackHandle，nomsgpack构建时不使用
+	msgpackHandle  any
+	rawPathRoutes  map[string]map[string]rawPathOption
+	optionalRoutes map[string]map[string]optionalRoute
+	defaultHeaders http.Header
+	renderMIMEs    []string
+	// 处理请求时使用的路由表，注册路由或者ReplaceRoutes时发布
+	table atomic.Pointer[routeTable]
+	// ReplaceRoutes使用的锁，以及是否正在构建新的路由表
+	replaceMu sync.Mutex
+	replacing bool
+	// 通过UseNamed注册的middleware
+	named namedMiddlewares
+	// 通过OnStart、OnRequest等注册的生命周期函数
+	hooks engineHooks
+	// 通过MapErrorStatus注册的错误
+	errorStatuses []errorStatus
+	// 通过RespondOnError注册的错误响应
+	errorResponders []errorResponder
+}
+
+// 使用RawPath匹配路由时，参数中编码的'/'（%2F）的处理方式
+type EncodedSlashPolicy int
+
+const (
+	// 解码为'/'，与其他编码的字符相同
+	EncodedSlashDecode EncodedSlashPolicy = iota
+	// 保留%2F的编码，同时保留%25的编码以区分请求中的%2F和%252F，其他字符正常解码
+	EncodedSlashKeep
+	// 参数包含%2F时返回400
+	EncodedSlashReject
+)
+
+// MultipartLimits multipart form的解析限制，字段为0时不限制
+type MultipartLimits struct {
+	// 最多的part数量，包括普通字段和文件
+	MaxParts int
+
+	// 单个文件的最大大小
+	MaxFileSize int64
+
+	// 整个body的最大大小，超过时停止读取
+	MaxTotalSize int64
 }
 
 // 接口实现校验
@@ -202,9 +318,10 @@ func New() *Engine {
 	}
 	// TODO
 	engine.RouterGroup.engine = engine
+	engine.publishRoutes()
 	// 对象池中返回并发安全的Context
 	engine.pool.New = func() any {
-		return engine.allocateContext(engine.maxParams)
+		return engine.allocateContext(engine.currentRoutes().maxParams)
 	}
 	// 返回创建的的Engine实例
 	return engine
@@ -221,20 +338,52 @@ func Default() *Engine {
 }
 
 func (engine *Engine) Handler() http.Handler {
-	// 不启用H2C，直接返回engine
-	if !engine.UseH2C {
-		return engine
+	var h http.Handler = engine
+	// 设置了gRPC handler时，gRPC请求转发给gRPC handler
+	if engine.grpcHandler != nil {
+		h = grpcMux{grpc: engine.grpcHandler, http: engine}
 	}
 
-	// 使用h2c包装engine
-	h2s := &http2.Server{}
-	return h2c.NewHandler(engine, h2s)
+	// 不启用H2C并且没有gRPC handler，直接返回
+	if !engine.UseH2C && engine.grpcHandler == nil {
+		return h
+	}
+
+	// 使用h2c包装，未使用TLS时同一端口也可以处理HTTP/2（gRPC）请求
+	return h2c.NewHandler(h, engine.HTTP2Config.server())
+}
+
+// 设置与gin共用同一个端口的gRPC handler（例如*grpc.Server），Handler()返回的handler会将
+// HTTP/2并且Content-Type为application/grpc的请求转发给h，其他请求由gin处理，未使用TLS时会自动启用h2c
+func (engine *Engine) ServeGRPC(h http.Handler) *Engine {
+	engine.grpcHandler = h
+	return engine
+}
+
+// 根据协议版本和Content-Type区分gRPC请求和普通请求
+type grpcMux struct {
+	grpc http.Handler
+	http http.Handler
+}
+
+// 符合http.Handler的接口
+func (m grpcMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if isGRPCRequest(req) {
+		m.grpc.ServeHTTP(w, req)
+		return
+	}
+	m.http.ServeHTTP(w, req)
+}
+
+// gRPC请求使用HTTP/2，并且Content-Type为application/grpc或者application/grpc+proto等
+func isGRPCRequest(req *http.Request) bool {
+	return req.ProtoMajor == 2 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
 }
 
 // 分配Context
 func (engine *Engine) allocateContext(maxParams uint16) *Context {
 	v := make(Params, 0, maxParams)
-	skippedNodes := make([]skippedNode, 0, engine.maxSections)
+	skippedNodes := make([]skippedNode, 0, engine.currentRoutes().maxSections)
 	return &Context{engine: engine, params: &v, skippedNodes: &skippedNodes}
 }
 
@@ -250,6 +399,36 @@ func (engine *Engine) SecureJsonPrefix(prefix string) *Engine {
 	return engine
 }
 
+// 为mime注册render，在Negotiate和Context.RenderNegotiated中使用，
+// 例如application/hal+json或者厂商自定义的media type，注册相同的mime会覆盖之前的render
+func (engine *Engine) RegisterRender(mime string, factory func(data any) render.Render) *Engine {
+	if engine.renders == nil {
+		engine.renders = make(map[string]func(data any) render.Render)
+	}
+	if _, ok := engine.renders[mime]; !ok {
+		engine.renderMIMEs = append(engine.renderMIMEs, mime)
+	}
+	engine.renders[mime] = factory
+	return engine
+}
+
+// 替换mime对应的binding，Bind、ShouldBind、ShouldBindJSON等方法绑定该mime时使用b，
+// 可以在测试中模拟绑定或者校验失败，b为nil时恢复默认的binding
+//
+//	router.SetBinding(gin.MIMEJSON, fakeJSONBinding{err: errors.New("invalid")})
+func (engine *Engine) SetBinding(mime string, b binding.Binding) *Engine {
+	mime = canonicalBindingMIME(mime)
+	if b == nil {
+		delete(engine.bindings, mime)
+		return engine
+	}
+	if engine.bindings == nil {
+		engine.bindings = make(map[string]binding.Binding)
+	}
+	engine.bindings[mime] = b
+	return engine
+}
+
 // 加载由glob模式标识的HTML文件并将结果与HTML Render关联
 func (engine *Engine) LoadHTMLGlob(pattern string) {
 	// 生成template
@@ -279,13 +458,54 @@ func (engine *Engine) LoadHTMLFiles(files ...string) {
 	engine.SetHTMLTemplate(templ)
 }
 
+// 加载layout、partial和page模板文件并将结果与HTML Render关联，
+// 通过Context.HTMLWithLayout将page渲染到layout的content block中
+func (engine *Engine) LoadHTMLLayouts(layouts, partials, pages []string) {
+	r := &render.HTMLLayout{
+		Layouts:  layouts,
+		Partials: partials,
+		Pages:    pages,
+		Delims:   engine.delims,
+		FuncMap:  engine.FuncMap,
+		Debug:    IsDebugging(),
+	}
+	if err := r.Load(); err != nil {
+		panic(err)
+	}
+	engine.HTMLRender = r
+}
+
 // 设置和HTML Render关联的template
 func (engine *Engine) SetHTMLTemplate(templ *template.Template) {
 	if len(engine.trees) > 0 {
 		debugPrintWARNINGSetHTMLTemplate()
 	}
 
-	engine.HTMLRender = render.HTMLProduction{Template: templ.Funcs(engine.FuncMap)}
+	templ = templ.Funcs(engine.FuncMap)
+	// 保留未执行过的副本，用于请求级别的FuncMap
+	source, _ := templ.Clone()
+	engine.HTMLRender = render.HTMLProduction{Template: templ, Source: source}
+}
+
+// 使用TemplateCache按需编译模板并将其与HTML Render关联
+func (engine *Engine) SetHTMLTemplateCache(cache render.TemplateCache) {
+	engine.HTMLRender = render.HTMLCache{Cache: cache}
+}
+
+// 设置每个响应都会携带的默认header，例如Server、X-Content-Type-Options，
+// 包括404、405以及不经过middleware的错误响应；handler已经设置的header不会被覆盖，
+// 再次调用时替换之前的设置，headers为空时不再添加默认header
+func (engine *Engine) DefaultHeaders(headers map[string]string) *Engine {
+	if len(headers) == 0 {
+		engine.defaultHeaders = nil
+		return engine
+	}
+	h := make(http.Header, len(headers))
+	for key, value := range headers {
+		h.Set(key, value)
+	}
+	engine.defaultHeaders = h
+	return engine
 }
 
 // 通过template.FuncMap设置engine.FuncMap
@@ -330,6 +550,18 @@ func (engine *Engine) addRoute(method, path string, handlers HandlersChain) {
 	assert1(method != "", "HTTP method can not be empty")
 	assert1(len(handlers) > 0, "there must be at least one handler")
 
+	if engine.PathNormalization != PathNormalizationNone {
+		path = engine.PathNormalization.normalizeRoute(path)
+	}
+
+	// 以可选参数结尾的路由展开为包含和不包含可选参数的两个路由
+	if full, short, key, ok := splitOptionalParam(path); ok {
+		engine.addRoute(method, full, handlers)
+		engine.addRoute(method, short, handlers)
+		engine.addOptionalRoute(method, path, full, short, key)
+		return
+	}
+
 	// debug mode打印信息
 	debugPrintRoute(method, path, handlers)
 
@@ -351,16 +583,120 @@ func (engine *Engine) addRoute(method, path string, handlers HandlersChain) {
 	if sectionsCount := countSections(path); sectionsCount > engine.maxSections {
 		engine.maxSections = sectionsCount
 	}
+	engine.publishRoutes()
+	engine.routeRegistered(method, path, handlers)
+}
+
+// 返回查找路由的配置，unescape为true时根据EncodedSlashPolicy解码参数
+func (engine *Engine) lookupOptions(unescape bool) lookupOptions {
+	opts := lookupOptions{strictStatic: engine.StrictStaticPriority}
+	if unescape {
+		opts.unescape = url.QueryUnescape
+		if engine.EncodedSlashPolicy != EncodedSlashDecode {
+			opts.unescape = unescapeKeepSlash
+		}
+	}
+	return opts
+}
+
+// 检查参数中是否包含编码的'/'，包含时返回false；
+// 参数已经通过unescapeKeepSlash解码时，同时解码保留的%25
+func checkEncodedSlash(params Params, unescaped bool) bool {
+	for i := range params {
+		if strings.Contains(params[i].Value, "%2F") || (!unescaped && strings.Contains(params[i].Value, "%2f")) {
+			return false
+		}
+		if unescaped {
+			params[i].Value = strings.ReplaceAll(params[i].Value, "%25", "%")
+		}
+	}
+	return true
+}
+
+// 记录通过WithRawPath注册的路由
+func (engine *Engine) addRawPathRoute(method, path string, opt rawPathOption) {
+	if engine.PathNormalization != PathNormalizationNone {
+		path = engine.PathNormalization.normalizeRoute(path)
+	}
+	if full, short, _, ok := splitOptionalParam(path); ok {
+		engine.addRawPathRoute(method, full, opt)
+		engine.addRawPathRoute(method, short, opt)
+		return
+	}
+	if engine.rawPathRoutes == nil {
+		engine.rawPathRoutes = make(map[string]map[string]rawPathOption)
+	}
+	if engine.rawPathRoutes[method] == nil {
+		engine.rawPathRoutes[method] = make(map[string]rawPathOption)
+	}
+	engine.rawPathRoutes[method][path] = opt
+	engine.publishRoutes()
+}
+
+// 使用RawPath查找路由，匹配到通过WithRawPath注册的路由时返回RawPath和路由的配置，
+// 查找使用的params和skippedNodes会被重置，由后续的查找重新填充
+func (engine *Engine) matchRawPath(c *Context, rt *routeTable, method string) (string, rawPathOption, bool) {
+	root := rt.trees.get(method)
+	if root == nil {
+		return "", rawPathOption{}, false
+	}
+	rPath := c.Request.URL.RawPath
+	if engine.RemoveExtraSlash {
+		rPath = cleanPath(rPath)
+	}
+	value := root.getValueWithOptions(rPath, c.params, c.skippedNodes, engine.lookupOptions(false))
+	*c.params = (*c.params)[:0]
+	*c.skippedNodes = (*c.skippedNodes)[:0]
+	if value.handlers == nil {
+		return "", rawPathOption{}, false
+	}
+	opt, ok := rt.rawPathRoutes[method][value.fullPath]
+	return rPath, opt, ok
 }
 
 // 返回注册router的切片，包含http method、path、handler name等信息
 func (engine *Engine) Routes() (routes RoutesInfo) {
-	for _, tree := range engine.trees {
+	for _, tree := range engine.currentRoutes().trees {
 		routes = iterate("", tree.method, routes, tree.root)
 	}
 	return routes
 }
 
+// 返回按Path和Method排序的路由信息，顺序与注册顺序无关，可以用于路由表的快照测试
+func (engine *Engine) RoutesSorted() RoutesInfo {
+	routes := engine.Routes()
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// 返回路由表的规范文本形式，每行为"METHOD PATH HANDLER"
+func (routes RoutesInfo) String() string {
+	var b strings.Builder
+	for _, route := range routes {
+		b.WriteString(route.Method + " " + route.Path + " " + route.Handler + "\n")
+	}
+	return b.String()
+}
+
+// 返回路由表的规范JSON形式，不包含HandlerFunc
+func (routes RoutesInfo) CanonicalJSON() ([]byte, error) {
+	type canonicalRoute struct {
+		Method  string `json:"method"`
+		Path    string `json:"path"`
+		Handler string `json:"handler"`
+	}
+	list := make([]canonicalRoute, 0, len(routes))
+	for _, route := range routes {
+		list = append(list, canonicalRoute{Method: route.Method, Path: route.Path, Handler: route.Handler})
+	}
+	return json.MarshalIndent(list, "", "  ")
+}
+
 // 遍历node，返回RoutesInfo
 func iterate(path, method string, routes RoutesInfo, root *node) RoutesInfo {
 	path += root.path
@@ -391,11 +727,96 @@ func (engine *Engine) Run(addr ...string) (err error) {
 	// 解析IP和端口号
 	address := resolveAddress(addr)
 	debugPrint("Listening and serving HTTP on %s\n", address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return
+	}
 	// 启动http服务
-	err = http.ListenAndServe(address, engine.Handler())
+	err = engine.serveListener(listener, func(l net.Listener) error {
+		return http.Serve(l, engine.Handler())
+	})
 	return
 }
 
+// Server使用的默认超时
+const (
+	defaultServerReadHeaderTimeout = 10 * time.Second
+	defaultServerIdleTimeout       = 120 * time.Second
+	// RunWithContext等待正在处理的请求完成的最长时间
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// 返回使用engine.Handler()处理请求的http.Server，addr为空时与Run相同使用PORT环境变量或者:8080；
+// 设置了ReadHeaderTimeout和IdleTimeout，ReadTimeout和WriteTimeout为0以支持流式响应和大文件上传，
+// ErrorLog写入DefaultErrorWriter。返回之后可以继续修改，例如设置MaxHeaderBytes或者TLSConfig
+//
+//	srv := router.Server(":8080")
+//	srv.MaxHeaderBytes = 64 << 10
+//	go srv.ListenAndServe()
+//	...
+//	srv.Shutdown(ctx)
+func (engine *Engine) Server(addr string) *http.Server {
+	var address string
+	if addr == "" {
+		address = resolveAddress(nil)
+	} else {
+		address = resolveAddress([]string{addr})
+	}
+	return &http.Server{
+		Addr:              address,
+		Handler:           engine.Handler(),
+		ReadHeaderTimeout: defaultServerReadHeaderTimeout,
+		IdleTimeout:       defaultServerIdleTimeout,
+		ErrorLog:          log.New(DefaultErrorWriter, "[GIN-server] ", log.LstdFlags),
+	}
+}
+
+// 与Run相同启动http服务，ctx取消时调用http.Server.Shutdown，等待正在处理的请求完成（最多10秒）后返回Shutdown的错误；
+// 启动失败时直接返回错误
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	err := router.RunWithContext(ctx, ":8080")
+func (engine *Engine) RunWithContext(ctx context.Context, addr ...string) (err error) {
+	defer func() { debugPrintError(err) }()
+
+	if engine.isUnsafeTrustedProxies() {
+		debugPrint("[WARNING] You trusted all proxies, this is NOT safe. We recommend you to set a value.\n" +
+			"Please check https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies for details.")
+	}
+
+	address := resolveAddress(addr)
+	srv := engine.Server(address)
+	debugPrint("Listening and serving HTTP on %s\n", address)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	// 等待正在处理的请求完成之后才调用OnStop注册的函数
+	engine.started(listener)
+	defer engine.stopped()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(listener)
+	}()
+
+	select {
+	case err = <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	// 等待Serve返回，ctx取消之前已经启动失败时返回启动的错误
+	if serveErr := <-errCh; err == nil && serveErr != http.ErrServerClosed {
+		err = serveErr
+	}
+	return err
+}
+
 // 对trustedProxies进行预处理，包括添加子网掩码和转换类型等
 func (engine *Engine) prepareTrustedCIDRs() ([]*net.IPNet, error) {
 	// 判断是否有trustedProxies
@@ -492,6 +913,27 @@ func (engine *Engine) validateHeader(header string) (clientIP string, valid bool
 	return "", false
 }
 
+// 按照Engine.TrustedProxyHops解析X-Forwarded-For header，返回从右往左数第TrustedProxyHops个地址，未设置时返回最右边的地址
+func (engine *Engine) validateHeaderHops(header string) (clientIP string, valid bool) {
+	if header == "" {
+		return "", false
+	}
+	hops := engine.TrustedProxyHops
+	if hops < 1 {
+		hops = 1
+	}
+	items := strings.Split(header, ",")
+	i := len(items) - hops
+	if i < 0 {
+		return "", false
+	}
+	ipStr := strings.TrimSpace(items[i])
+	if net.ParseIP(ipStr) == nil {
+		return "", false
+	}
+	return ipStr, true
+}
+
 // 解析string类型的IP为最小byte表示的net.IP，如果输入无效则返回nil
 func parseIP(ip string) net.IP {
 	// 转换解析ip
@@ -517,12 +959,19 @@ func (engine *Engine) RunTLS(addr, certFile, keyFile string) (err error) {
 			"Please check https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies for details.")
 	}
 
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return
+	}
 	// 启动https服务
-	err = http.ListenAndServeTLS(addr, certFile, keyFile, engine.Handler())
+	srv := &http.Server{Addr: addr, Handler: engine.Handler()}
+	err = engine.serveListener(listener, func(l net.Listener) error {
+		return srv.ServeTLS(l, certFile, keyFile)
+	})
 	return
 }
 
-// 通过http.Server进行http服务（unix环境的套接字，即file）
+// 通过http.Server进行http服务（unix环境的套接字，即file），linux下可以通过Context.PeerCredentials获取对端进程的凭证
 func (engine *Engine) RunUnix(file string) (err error) {
 	debugPrint("Listening and serving HTTP on unix:/%s", file)
 	defer func() { debugPrintError(err) }()
@@ -540,8 +989,9 @@ func (engine *Engine) RunUnix(file string) (err error) {
 	defer listener.Close()
 	defer os.Remove(file)
 
-	// 启动http服务
-	err = http.Serve(listener, engine.Handler())
+	// 启动http服务，请求中可以通过Context.PeerCredentials获取对端进程的凭证
+	srv := &http.Server{Handler: engine.Handler(), ConnContext: PeerCredentialsConnContext}
+	err = engine.serveListener(listener, srv.Serve)
 	return
 }
 
@@ -579,7 +1029,9 @@ func (engine *Engine) RunListener(listener net.Listener) (err error) {
 	}
 
 	// 启动http服务
-	err = http.Serve(listener, engine.Handler())
+	err = engine.serveListener(listener, func(l net.Listener) error {
+		return http.Serve(l, engine.Handler())
+	})
 	return
 }
 
@@ -587,21 +1039,45 @@ func (engine *Engine) RunListener(listener net.Listener) (err error) {
 func (engine *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// 对象池获取Context并进行资源重置
 	c := engine.pool.Get().(*Context)
+	c.clearReleased()
+	engine.serveContext(c, w, req)
+
+	// debug模式下标记Context已放回对象池，用于检测误用
+	if IsDebugging() {
+		c.markReleased()
+	}
+	// 使用完之后返回Context
+	engine.pool.Put(c)
+}
+
+// 使用c处理req，执行OnRequest和OnResponse hook、路由的handler以及Context.Defer注册的函数
+func (engine *Engine) serveContext(c *Context, w http.ResponseWriter, req *http.Request) {
 	c.writermem.reset(w)
 	c.Request = req
 	c.reset()
 
+	for _, f := range engine.hooks.request {
+		f(c)
+	}
 	// 接收http request
 	engine.handleHTTPRequest(c)
-
-	// 使用完之后返回Context
-	engine.pool.Put(c)
+	for _, f := range engine.hooks.response {
+		f(c)
+	}
+	// 执行通过Context.Defer注册的函数
+	c.runDeferred()
+	if engine.MultipartAutoCleanup {
+		c.removeMultipartFiles(req)
+	}
 }
 
 // 通过重新设置c.Request.URL.Path来进入被重写的Context
 func (engine *Engine) HandleContext(c *Context) {
 	oldIndexValue := c.index
+	// 保留重写前通过Context.Defer注册的函数
+	deferred := c.deferred
 	c.reset()
+	c.deferred = deferred
 	engine.handleHTTPRequest(c)
 
 	c.index = oldIndexValue
@@ -612,30 +1088,64 @@ func (engine *Engine) handleHTTPRequest(c *Context) {
 	httpMethod := c.Request.Method
 	rPath := c.Request.URL.Path
 	unescape := false
+	rawPath := false
 	if engine.UseRawPath && len(c.Request.URL.RawPath) > 0 {
 		rPath = c.Request.URL.RawPath
 		unescape = engine.UnescapePathValues
+		rawPath = true
 	}
 
 	if engine.RemoveExtraSlash {
 		rPath = cleanPath(rPath)
 	}
+	if engine.PathNormalization != PathNormalizationNone {
+		rPath = engine.PathNormalization.normalize(rPath)
+	}
+
+	// 整个请求使用同一个路由表，ReplaceRoutes之后的路由表可能需要更多的参数
+	rt := engine.currentRoutes()
+	if cap(*c.params) < int(rt.maxParams) {
+		params := make(Params, 0, rt.maxParams)
+		c.params = &params
+	}
+	if cap(*c.skippedNodes) < int(rt.maxSections) {
+		skippedNodes := make([]skippedNode, 0, rt.maxSections)
+		c.skippedNodes = &skippedNodes
+	}
+
+	// 通过WithRawPath注册的路由使用RawPath匹配
+	if !engine.UseRawPath && len(c.Request.URL.RawPath) > 0 && len(rt.rawPathRoutes[httpMethod]) > 0 {
+		if path, opt, ok := engine.matchRawPath(c, rt, httpMethod); ok {
+			rPath = path
+			unescape = opt.unescape
+			rawPath = true
+		}
+	}
+	opts := engine.lookupOptions(unescape)
 
 	// 通过http method找到对应的handler
-	t := engine.trees
+	t := rt.trees
 	for i, tl := 0, len(t); i < tl; i++ {
 		if t[i].method != httpMethod {
 			continue
 		}
 		root := t[i].root
 		// 找到对应的router
-		value := root.getValue(rPath, c.params, c.skippedNodes, unescape)
+		value := root.getValueWithOptions(rPath, c.params, c.skippedNodes, opts)
 		if value.params != nil {
 			c.Params = *value.params
 		}
 		if value.handlers != nil {
+			// EncodedSlashReject时参数包含编码的'/'返回400
+			if rawPath && engine.EncodedSlashPolicy == EncodedSlashReject && !checkEncodedSlash(c.Params, unescape) {
+				abortWithDefaultBody(c, http.StatusBadRequest, default400Body)
+				return
+			}
 			c.handlers = value.handlers
 			c.fullPath = value.fullPath
+			if routes := rt.optionalRoutes[httpMethod]; routes != nil {
+				c.fullPath, c.Params = applyOptionalRoute(routes, value.fullPath, c.Params)
+			}
 			c.Next()
 			c.writermem.WriteHeaderNow()
 			return
@@ -654,11 +1164,11 @@ func (engine *Engine) handleHTTPRequest(c *Context) {
 
 	// http method不被允许,返回405
 	if engine.HandleMethodNotAllowed {
-		for _, tree := range engine.trees {
+		for _, tree := range rt.trees {
 			if tree.method == httpMethod {
 				continue
 			}
-			if value := tree.root.getValue(rPath, nil, c.skippedNodes, unescape); value.handlers != nil {
+			if value := tree.root.getValueWithOptions(rPath, nil, c.skippedNodes, opts); value.handlers != nil {
 				c.handlers = engine.allNoMethod
 				serveError(c, http.StatusMethodNotAllowed, default405Body)
 				return
@@ -690,6 +1200,16 @@ func serveError(c *Context, code int, defaultMessage []byte) {
 	c.writermem.WriteHeaderNow()
 }
 
+// 直接返回code和默认的错误信息，不执行NoRoute、NoMethod等handler
+func abortWithDefaultBody(c *Context, code int, defaultMessage []byte) {
+	c.Abort()
+	c.writermem.Header()["Content-Type"] = mimePlain
+	c.writermem.WriteHeader(code)
+	if _, err := c.Writer.Write(defaultMessage); err != nil {
+		debugPrint("cannot write message to writer during serve error: %v", err)
+	}
+}
+
 // TODO:重定向请求
 func redirectTrailingSlash(c *Context) {
 	req := c.Request
@@ -5,6 +5,8 @@
 package gin
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html/template"
@@ -118,6 +120,200 @@ func TestH2c(t *testing.T) {
 	assert.Equal(t, "<h1>Hello world</h1>", string(resp))
 }
 
+func TestEngineServer(t *testing.T) {
+	var buf bytes.Buffer
+	oldWriter := DefaultErrorWriter
+	DefaultErrorWriter = &buf
+	defer func() { DefaultErrorWriter = oldWriter }()
+
+	r := New()
+	r.GET("/", func(c *Context) {
+		c.String(200, "ok")
+	})
+	srv := r.Server("127.0.0.1:0")
+	assert.Equal(t, "127.0.0.1:0", srv.Addr)
+	assert.Equal(t, defaultServerReadHeaderTimeout, srv.ReadHeaderTimeout)
+	assert.Equal(t, defaultServerIdleTimeout, srv.IdleTimeout)
+	assert.Zero(t, srv.WriteTimeout)
+
+	srv.ErrorLog.Print("boom")
+	assert.Contains(t, buf.String(), "[GIN-server] ")
+	assert.Contains(t, buf.String(), "boom")
+
+	w := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "ok", w.Body.String())
+
+	t.Setenv("PORT", "9090")
+	assert.Equal(t, ":9090", r.Server("").Addr)
+}
+
+func TestRunWithContext(t *testing.T) {
+	// 获取一个空闲的端口
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	release := make(chan struct{})
+	r := New()
+	r.GET("/slow", func(c *Context) {
+		<-release
+		c.String(200, "done")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- r.RunWithContext(ctx, addr)
+	}()
+
+	var res *http.Response
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	resCh := make(chan *http.Response, 1)
+	go func() {
+		res, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			resCh <- nil
+			return
+		}
+		resCh <- res
+	}()
+	// 等待请求开始处理之后取消ctx，正在处理的请求完成之后才返回
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-runErr:
+		t.Fatalf("RunWithContext returned before the request finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	assert.NoError(t, <-runErr)
+	res = <-resCh
+	if assert.NotNil(t, res) {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, "done", string(body))
+	}
+}
+
+func TestRunWithContextListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	err = New().RunWithContext(context.Background(), ln.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestH2cWithHTTP2Config(t *testing.T) {
+	conf := HTTP2Config{
+		MaxConcurrentStreams: 1000,
+		IdleTimeout:          time.Minute,
+		MaxReadFrameSize:     1 << 20,
+	}
+	h2s := conf.server()
+	assert.Equal(t, uint32(1000), h2s.MaxConcurrentStreams)
+	assert.Equal(t, time.Minute, h2s.IdleTimeout)
+	assert.Equal(t, uint32(1<<20), h2s.MaxReadFrameSize)
+	assert.Zero(t, h2s.MaxUploadBufferPerStream)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+	}
+	r := New()
+	r.UseH2C = true
+	r.HTTP2Config = conf
+	r.GET("/", func(c *Context) {
+		c.String(200, c.Request.Proto)
+	})
+	go func() {
+		_ = http.Serve(ln, r.Handler())
+	}()
+	defer ln.Close()
+
+	httpClient := http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+		},
+	}
+	res, err := httpClient.Get("http://" + ln.Addr().String() + "/")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	resp, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "HTTP/2.0", string(resp))
+}
+
+func TestServeGRPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Error(err)
+	}
+	r := New()
+	r.GET("/", func(c *Context) {
+		c.String(200, "rest")
+	})
+	r.POST("/", func(c *Context) {
+		c.String(200, "rest")
+	})
+	r.ServeGRPC(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		_, _ = w.Write([]byte("grpc"))
+	}))
+	go func() {
+		err := http.Serve(ln, r.Handler())
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+	defer ln.Close()
+
+	url := "http://" + ln.Addr().String() + "/"
+	h2Client := http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+		},
+	}
+
+	// gRPC over h2c
+	res, err := h2Client.Post(url, "application/grpc+proto", bytes.NewBufferString(""))
+	if assert.NoError(t, err) {
+		resp, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "grpc", string(resp))
+	}
+
+	// REST over h2c
+	res, err = h2Client.Post(url, MIMEJSON, bytes.NewBufferString("{}"))
+	if assert.NoError(t, err) {
+		resp, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "rest", string(resp))
+	}
+
+	// REST over HTTP/1.1
+	res, err = http.Get(url)
+	if assert.NoError(t, err) {
+		resp, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "rest", string(resp))
+	}
+}
+
 func TestLoadHTMLGlobTestMode(t *testing.T) {
 	ts := setupHTMLFiles(
 		t,
@@ -276,6 +472,78 @@ func TestLoadHTMLFilesReleaseMode(t *testing.T) {
 	assert.Equal(t, "<h1>Hello world</h1>", string(resp))
 }
 
+func TestLoadHTMLLayouts(t *testing.T) {
+	for _, mode := range []string{DebugMode, ReleaseMode} {
+		SetMode(mode)
+		var router *Engine
+		captureOutput(t, func() {
+			router = New()
+			router.LoadHTMLLayouts(
+				[]string{"./testdata/layout/base.tmpl"},
+				[]string{"./testdata/layout/nav.tmpl"},
+				[]string{"./testdata/layout/index.tmpl", "./testdata/layout/about.tmpl"},
+			)
+		})
+		SetMode(TestMode)
+		router.GET("/:page", func(c *Context) {
+			c.HTMLWithLayout(http.StatusOK, "base.tmpl", c.Param("page")+".tmpl", H{"name": "gin"})
+		})
+		router.GET("/partial/nav", func(c *Context) {
+			c.HTML(http.StatusOK, "nav.tmpl", H{"name": "gin"})
+		})
+
+		w := PerformRequest(router, http.MethodGet, "/index")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<title>Default</title><nav>gin</nav><main><p>Hello gin</p></main>\n", w.Body.String())
+
+		w = PerformRequest(router, http.MethodGet, "/about")
+		assert.Equal(t, "<title>About</title><nav>gin</nav><main><p>About gin</p></main>\n", w.Body.String())
+
+		w = PerformRequest(router, http.MethodGet, "/partial/nav")
+		assert.Equal(t, "<nav>gin</nav>", w.Body.String())
+	}
+}
+
+func TestHTMLWithLayoutFuncs(t *testing.T) {
+	for _, mode := range []string{DebugMode, ReleaseMode} {
+		SetMode(mode)
+		var router *Engine
+		captureOutput(t, func() {
+			router = New()
+			router.SetFuncMap(template.FuncMap{"csrf": func() string { return "" }})
+			router.LoadHTMLLayouts(
+				[]string{"./testdata/layout/base.tmpl"},
+				[]string{"./testdata/layout/nav.tmpl"},
+				[]string{"./testdata/layout/token.tmpl"},
+			)
+		})
+		SetMode(TestMode)
+		router.GET("/", func(c *Context) {
+			if token := c.Query("token"); token != "" {
+				c.SetHTMLFuncs(template.FuncMap{"csrf": func() string { return token }})
+			}
+			c.HTMLWithLayout(http.StatusOK, "base.tmpl", "token.tmpl", H{"name": "gin"})
+		})
+
+		w := PerformRequest(router, http.MethodGet, "/?token=abc")
+		assert.Equal(t, "<title>Default</title><nav>gin</nav><main><p>token=abc</p></main>\n", w.Body.String())
+		// 不影响共享的模板
+		w = PerformRequest(router, http.MethodGet, "/")
+		assert.Equal(t, "<title>Default</title><nav>gin</nav><main><p>token=</p></main>\n", w.Body.String())
+	}
+}
+
+func TestHTMLWithLayoutUnsupported(t *testing.T) {
+	router := New()
+	router.LoadHTMLFiles("./testdata/template/hello.tmpl")
+	router.GET("/", func(c *Context) {
+		c.HTMLWithLayout(http.StatusOK, "base.tmpl", "hello.tmpl", nil)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func TestLoadHTMLFilesUsingTLS(t *testing.T) {
 	ts := setupHTMLFiles(
 		t,
@@ -517,6 +785,42 @@ func TestListOfRoutes(t *testing.T) {
 	})
 }
 
+func TestEngineRoutesSorted(t *testing.T) {
+	build := func(reverse bool) *Engine {
+		router := New()
+		register := []func(){
+			func() { router.POST("/users/:id", handlerTest2) },
+			func() { router.GET("/users/:id", handlerTest1) },
+			func() { router.GET("/", handlerTest1) },
+			func() { router.DELETE("/users/:id", handlerTest1) },
+		}
+		for i := range register {
+			if reverse {
+				register[len(register)-1-i]()
+			} else {
+				register[i]()
+			}
+		}
+		return router
+	}
+
+	routes := build(false).RoutesSorted()
+	assert.Equal(t, routes.String(), build(true).RoutesSorted().String())
+	assert.Equal(t, ""+
+		"GET / github.com/gin-gonic/gin.handlerTest1\n"+
+		"DELETE /users/:id github.com/gin-gonic/gin.handlerTest1\n"+
+		"GET /users/:id github.com/gin-gonic/gin.handlerTest1\n"+
+		"POST /users/:id github.com/gin-gonic/gin.handlerTest2\n", routes.String())
+
+	data, err := routes[:1].CanonicalJSON()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"method":"GET","path":"/","handler":"github.com/gin-gonic/gin.handlerTest1"}]`, string(data))
+
+	data, err = RoutesInfo{}.CanonicalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
+
 func TestEngineHandleContext(t *testing.T) {
 	r := New()
 	r.GET("/", func(c *Context) {
@@ -534,6 +838,76 @@ func TestEngineHandleContext(t *testing.T) {
 	})
 }
 
+func TestContextDefer(t *testing.T) {
+	var order []string
+	var written bool
+	r := New()
+	r.Use(func(c *Context) {
+		c.Defer(func() { order = append(order, "middleware") })
+		c.Next()
+	})
+	r.GET("/", func(c *Context) {
+		c.Defer(func() {
+			written = c.Writer.Written()
+			order = append(order, "handler")
+		})
+		c.Request.URL.Path = "/rewrite"
+		r.HandleContext(c)
+	})
+	r.GET("/rewrite", func(c *Context) {
+		c.Defer(func() { order = append(order, "rewrite") })
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := PerformRequest(r, http.MethodGet, "/")
+	assert.Equal(t, "ok", w.Body.String())
+	// HandleContext会重新执行middleware
+	assert.Equal(t, []string{"rewrite", "middleware", "handler", "middleware"}, order)
+	assert.True(t, written)
+}
+
+func retainContextHandler(retained **Context) HandlerFunc {
+	return func(c *Context) {
+		*retained = c
+	}
+}
+
+func TestContextUsedAfterRelease(t *testing.T) {
+	SetMode(DebugMode)
+	defer SetMode(TestMode)
+
+	var retained *Context
+	r := New()
+	r.GET("/", retainContextHandler(&retained))
+	PerformRequest(r, http.MethodGet, "/")
+
+	assert.PanicsWithValue(t, "gin: Context used after the request completed (retained by handler "+
+		"github.com/gin-gonic/gin.retainContextHandler.func1); use Context.Copy() when passing the Context to a goroutine", func() {
+		retained.Set("key", "value")
+	})
+	assert.Panics(t, func() { retained.Copy() })
+
+	// 重新取出后可以正常使用
+	r.GET("/ok", func(c *Context) { c.Set("key", "value") })
+	assert.NotPanics(t, func() {
+		for i := 0; i < 10; i++ {
+			PerformRequest(r, http.MethodGet, "/ok")
+		}
+	})
+}
+
+func TestContextUsedAfterReleaseReleaseMode(t *testing.T) {
+	SetMode(ReleaseMode)
+	defer SetMode(TestMode)
+
+	var retained *Context
+	r := New()
+	r.GET("/", retainContextHandler(&retained))
+	PerformRequest(r, http.MethodGet, "/")
+
+	assert.NotPanics(t, func() { retained.Set("key", "value") })
+}
+
 func TestEngineHandleContextManyReEntries(t *testing.T) {
 	expectValue := 10000
 
@@ -0,0 +1,279 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// gintest 提供handler测试使用的Engine、请求构造器和响应断言
+package gintest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/internal/json"
+)
+
+// Engine 包装gin.Engine，失败的断言会报告给t
+type Engine struct {
+	*gin.Engine
+	t testing.TB
+}
+
+// 实例化一个没有middleware的测试Engine
+func NewTestEngine(t testing.TB) *Engine {
+	return &Engine{Engine: gin.New(), t: t}
+}
+
+// 构造一个发送到Engine的请求，path中的:name和*name可以通过WithParam替换
+func (e *Engine) NewRequest(method, path string) *RequestBuilder {
+	return &RequestBuilder{
+		engine: e,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+}
+
+// RequestBuilder 以链式调用构造请求
+type RequestBuilder struct {
+	engine *Engine
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	params []gin.Param
+	body   []byte
+	err    error
+}
+
+// 设置JSON body和Content-Type
+func (b *RequestBuilder) WithJSON(obj any) *RequestBuilder {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	return b.WithBody("application/json", body)
+}
+
+// 设置url编码的表单body和Content-Type
+func (b *RequestBuilder) WithForm(form url.Values) *RequestBuilder {
+	return b.WithBody("application/x-www-form-urlencoded", []byte(form.Encode()))
+}
+
+// 设置body和Content-Type
+func (b *RequestBuilder) WithBody(contentType string, body []byte) *RequestBuilder {
+	b.body = body
+	b.header.Set("Content-Type", contentType)
+	return b
+}
+
+// 添加请求header
+func (b *RequestBuilder) WithHeader(key, value string) *RequestBuilder {
+	b.header.Add(key, value)
+	return b
+}
+
+// 添加query参数
+func (b *RequestBuilder) WithQuery(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// 替换path中的路由参数:key或*key
+func (b *RequestBuilder) WithParam(key, value string) *RequestBuilder {
+	b.params = append(b.params, gin.Param{Key: key, Value: value})
+	return b
+}
+
+// 构造http.Request
+func (b *RequestBuilder) Build() (*http.Request, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	path, err := b.resolvePath()
+	if err != nil {
+		return nil, err
+	}
+	if len(b.query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + b.query.Encode()
+	}
+	req, err := http.NewRequest(b.method, path, bytes.NewReader(b.body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range b.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	return req, nil
+}
+
+// 发送请求并返回Response，构造请求失败时调用t.Fatal
+func (b *RequestBuilder) Do() *Response {
+	b.engine.t.Helper()
+	req := b.mustBuild()
+	w := httptest.NewRecorder()
+	b.engine.ServeHTTP(w, req)
+	return &Response{ResponseRecorder: w, t: b.engine.t}
+}
+
+// 通过路由处理请求，同时返回处理后的Context，用于检查Params、Keys和Errors
+func (b *RequestBuilder) DoContext() (*gin.Context, *Response) {
+	b.engine.t.Helper()
+	req := b.mustBuild()
+	w := httptest.NewRecorder()
+	c := gin.CreateTestContextRouted(w, b.engine.Engine, req)
+	return c, &Response{ResponseRecorder: w, t: b.engine.t}
+}
+
+func (b *RequestBuilder) mustBuild() *http.Request {
+	b.engine.t.Helper()
+	req, err := b.Build()
+	if err != nil {
+		b.engine.t.Fatalf("gintest: build request: %v", err)
+	}
+	return req
+}
+
+// 用params替换path中的路由参数，:key会被转义，*key保留/
+func (b *RequestBuilder) resolvePath() (string, error) {
+	segments := strings.Split(b.path, "/")
+	for i, segment := range segments {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		value, ok := b.param(segment[1:])
+		if !ok {
+			return "", fmt.Errorf("missing param %q for path %q", segment[1:], b.path)
+		}
+		if segment[0] == ':' {
+			value = url.PathEscape(value)
+		} else {
+			value = strings.TrimPrefix(value, "/")
+		}
+		segments[i] = value
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func (b *RequestBuilder) param(key string) (string, bool) {
+	for _, p := range b.params {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// Response 包装httptest.ResponseRecorder，断言失败时调用t.Errorf并返回自身以便链式调用
+type Response struct {
+	*httptest.ResponseRecorder
+	t testing.TB
+}
+
+// 断言status code
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.Code != code {
+		r.t.Errorf("gintest: expected status %d, got %d; body: %s", code, r.Code, r.Body.String())
+	}
+	return r
+}
+
+// 断言response header
+func (r *Response) Header(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Result().Header.Get(key); got != value {
+		r.t.Errorf("gintest: expected header %s %q, got %q", key, value, got)
+	}
+	return r
+}
+
+// 断言response body
+func (r *Response) BodyEquals(body string) *Response {
+	r.t.Helper()
+	if got := r.Body.String(); got != body {
+		r.t.Errorf("gintest: expected body %q, got %q", body, got)
+	}
+	return r
+}
+
+// 断言JSON body中path对应的值，path以.分隔，数组使用下标，例如"data.items.0.name"
+// expected会先编码为JSON再解码，因此数字、struct和map可以直接比较
+func (r *Response) JSONPath(path string, expected any) *Response {
+	r.t.Helper()
+	got, err := r.lookupJSON(path)
+	if err != nil {
+		r.t.Errorf("gintest: JSONPath %q: %v", path, err)
+		return r
+	}
+	want, err := normalizeJSON(expected)
+	if err != nil {
+		r.t.Errorf("gintest: JSONPath %q: encode expected: %v", path, err)
+		return r
+	}
+	if !reflect.DeepEqual(got, want) {
+		r.t.Errorf("gintest: JSONPath %q: expected %v, got %v", path, want, got)
+	}
+	return r
+}
+
+// 将JSON body解码到obj
+func (r *Response) DecodeJSON(obj any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Body.Bytes(), obj); err != nil {
+		r.t.Errorf("gintest: decode JSON body: %v", err)
+	}
+	return r
+}
+
+func (r *Response) lookupJSON(path string) (any, error) {
+	var value any
+	if err := json.Unmarshal(r.Body.Bytes(), &value); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return value, nil
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("index %q out of range", key)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("cannot index %T with %q", value, key)
+		}
+	}
+	return value, nil
+}
+
+func normalizeJSON(obj any) (any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gintest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type user struct {
+	Name string `json:"name" form:"name"`
+	Age  int    `json:"age" form:"age"`
+}
+
+func TestRequestBuilderJSON(t *testing.T) {
+	e := NewTestEngine(t)
+	e.POST("/users/:id", func(c *gin.Context) {
+		var u user
+		if err := c.ShouldBindJSON(&u); err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Header("X-Trace", c.GetHeader("X-Trace"))
+		c.JSON(http.StatusCreated, gin.H{
+			"id":   c.Param("id"),
+			"user": u,
+			"tags": []string{c.Query("tag")},
+		})
+	})
+
+	e.NewRequest("POST", "/users/:id").
+		WithParam("id", "a b").
+		WithQuery("tag", "admin").
+		WithHeader("X-Trace", "abc").
+		WithJSON(user{Name: "gin", Age: 10}).
+		Do().
+		Status(http.StatusCreated).
+		Header("X-Trace", "abc").
+		JSONPath("id", "a b").
+		JSONPath("user", user{Name: "gin", Age: 10}).
+		JSONPath("user.age", 10).
+		JSONPath("tags.0", "admin")
+}
+
+func TestRequestBuilderForm(t *testing.T) {
+	e := NewTestEngine(t)
+	e.PUT("/files/*path", func(c *gin.Context) {
+		var u user
+		_ = c.ShouldBind(&u)
+		c.String(http.StatusOK, "%s %s %d", c.Param("path"), u.Name, u.Age)
+	})
+
+	e.NewRequest("PUT", "/files/*path").
+		WithParam("path", "/a/b.txt").
+		WithForm(url.Values{"name": {"gin"}, "age": {"3"}}).
+		Do().
+		Status(http.StatusOK).
+		BodyEquals("/a/b.txt gin 3")
+}
+
+func TestRequestBuilderBuildError(t *testing.T) {
+	e := NewTestEngine(t)
+	_, err := e.NewRequest("GET", "/users/:id").Build()
+	assert.EqualError(t, err, `missing param "id" for path "/users/:id"`)
+
+	_, err = e.NewRequest("POST", "/").WithJSON(make(chan int)).Build()
+	assert.Error(t, err)
+
+	req, err := e.NewRequest("GET", "/search?q=1").WithQuery("page", "2").Build()
+	require.NoError(t, err)
+	assert.Equal(t, "/search?q=1&page=2", req.URL.RequestURI())
+}
+
+func TestResponseAssertionsFail(t *testing.T) {
+	e := NewTestEngine(t)
+	e.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"items": []int{1}})
+	})
+
+	mock := &mockT{TB: t}
+	resp := e.NewRequest("GET", "/").Do()
+	resp.t = mock
+	resp.Status(http.StatusNotFound).
+		Header("X-Missing", "1").
+		BodyEquals("").
+		JSONPath("items.0", 2).
+		JSONPath("items.5", 1).
+		JSONPath("missing", 1).
+		JSONPath("items.0.name", 1)
+	assert.Equal(t, 7, mock.errors)
+
+	var body struct{ Items []int }
+	resp.DecodeJSON(&body)
+	assert.Equal(t, []int{1}, body.Items)
+	assert.Equal(t, 7, mock.errors)
+}
+
+type mockT struct {
+	testing.TB
+	errors int
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(string, ...any) {
+	m.errors++
+}
+
+func TestRequestBuilderDoContext(t *testing.T) {
+	e := NewTestEngine(t)
+	e.GET("/users/:id", func(c *gin.Context) {
+		c.Set("user", c.Param("id"))
+		c.Status(http.StatusNoContent)
+	})
+
+	c, resp := e.NewRequest("GET", "/users/:id").WithParam("id", "7").DoContext()
+	resp.Status(http.StatusNoContent)
+	assert.Equal(t, "7", c.Param("id"))
+	assert.Equal(t, "7", c.GetString("user"))
+	assert.Equal(t, "/users/:id", c.FullPath())
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+// 返回通过ShouldBindAll将请求绑定到T之后调用f的HandlerFunc，T一般为结构体类型；
+// 绑定失败时不调用f，不支持的Content-Type返回415，body太大返回413，其他错误的status code与Bind相同（参考Engine.BindErrors），
+// 错误的格式与ErrorRenderer相同
+//
+//	router.PUT("/users/:id", gin.HandlerOf(func(c *gin.Context, req UpdateUser) {
+//		c.JSON(http.StatusOK, update(req))
+//	}))
+func HandlerOf[T any](f func(*Context, T)) HandlerFunc {
+	return func(c *Context) {
+		var req T
+		if err := c.ShouldBindAll(&req); err != nil {
+			code := c.handlerBindErrorStatus(err)
+			c.AbortWithError(code, err).SetType(ErrorTypeBind) //nolint: errcheck
+			c.renderBindError(code, err, defaultErrorOffered)
+			return
+		}
+		f(c, req)
+	}
+}
+
+// 与HandlerOf相同，f返回的响应使用c.JSON渲染，status code为handler设置的status（默认为200）；
+// f返回错误时的status code和渲染方式与WrapResult相同，参考Engine.MapErrorStatus
+//
+//	router.POST("/users", gin.HandlerOfResponse(func(c *gin.Context, req CreateUser) (User, error) {
+//		c.Status(http.StatusCreated)
+//		return create(req)
+//	}))
+func HandlerOfResponse[T, R any](f func(*Context, T) (R, error)) HandlerFunc {
+	return HandlerOf(func(c *Context, req T) {
+		resp, err := f(c, req)
+		if err != nil {
+			c.abortWithResultError(err)
+			return
+		}
+		if c.Writer.Written() {
+			return
+		}
+		c.JSON(c.Writer.Status(), resp)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type handlerOfRequest struct {
+	ID   int    `uri:"id" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+func TestHandlerOf(t *testing.T) {
+	router := New()
+	router.PUT("/users/:id", HandlerOf(func(c *Context, req handlerOfRequest) {
+		c.String(http.StatusOK, "%d %s", req.ID, req.Name)
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`{"name":"gin"}`))
+	req.Header.Set("Content-Type", MIMEJSON)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1 gin", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", MIMEJSON)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `{"errors":[{"error":"Key: 'handlerOfRequest.Name'`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`name`))
+	req.Header.Set("Content-Type", "application/unknown")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+func TestHandlerOfResponse(t *testing.T) {
+	router := New()
+	var recorded []*Error
+	router.Use(func(c *Context) {
+		c.Next()
+		recorded = c.Errors
+	})
+	router.POST("/users/:id", HandlerOfResponse(func(c *Context, req handlerOfRequest) (H, error) {
+		switch req.Name {
+		case "fail":
+			return nil, errors.New("database is down")
+		case "missing":
+			c.Status(http.StatusNotFound)
+			return nil, errors.New("no such user")
+		}
+		c.Status(http.StatusCreated)
+		return H{"id": req.ID, "name": req.Name}, nil
+	}))
+
+	perform := func(name string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/users/3", strings.NewReader(`{"name":"`+name+`"}`))
+		req.Header.Set("Content-Type", MIMEJSON)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := perform("gin")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":3,"name":"gin"}`, w.Body.String())
+
+	// 不返回错误的内容
+	w = perform("fail")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"Internal Server Error"}]}`, w.Body.String())
+	assert.Len(t, recorded, 1)
+	assert.True(t, recorded[0].IsType(ErrorTypePrivate))
+
+	w = perform("missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"Not Found"}]}`, w.Body.String())
+}
+
+func TestBindErrorStatus(t *testing.T) {
+	router := New()
+	router.POST("/", HandlerOf(func(c *Context, req struct {
+		Name string `json:"name"`
+	}) {
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gin"}`))
+	req.Header.Set("Content-Type", "application/unknown")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gin"}`))
+	req.Header.Set("Content-Type", MIMEJSON)
+	req.Body = http.MaxBytesReader(w, req.Body, 4)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
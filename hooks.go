@@ -0,0 +1,89 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import "net"
+
+// Engine生命周期中调用的函数，需要在开始处理请求之前注册
+type engineHooks struct {
+	routeRegistered []func(RouteInfo)
+	start           []func(net.Listener)
+	request         []func(*Context)
+	response        []func(*Context)
+	stop            []func()
+}
+
+// 注册在添加路由之后调用的函数，已经注册的路由会立即按顺序调用f，
+// 因此f不依赖与路由注册的先后顺序，例如生成文档或者检查路由策略
+func (engine *Engine) OnRouteRegistered(f func(RouteInfo)) *Engine {
+	engine.hooks.routeRegistered = append(engine.hooks.routeRegistered, f)
+	for _, route := range engine.Routes() {
+		f(route)
+	}
+	return engine
+}
+
+// 注册在Run、RunTLS、RunUnix、RunFd、RunListener和RunWithContext开始监听之后、处理请求之前调用的函数
+func (engine *Engine) OnStart(f func(net.Listener)) *Engine {
+	engine.hooks.start = append(engine.hooks.start, f)
+	return engine
+}
+
+// 注册在每个请求匹配路由之前调用的函数，在所有middleware之前执行，包括404和405；
+// f中调用Abort并写入响应时不再执行handler
+func (engine *Engine) OnRequest(f func(*Context)) *Engine {
+	engine.hooks.request = append(engine.hooks.request, f)
+	return engine
+}
+
+// 注册在每个请求的handler执行完成之后调用的函数，此时可以通过c.Writer获取status和body大小
+func (engine *Engine) OnResponse(f func(*Context)) *Engine {
+	engine.hooks.response = append(engine.hooks.response, f)
+	return engine
+}
+
+// 注册在服务停止之后调用的函数，Run等函数返回之前按注册的相反顺序调用
+func (engine *Engine) OnStop(f func()) *Engine {
+	engine.hooks.stop = append(engine.hooks.stop, f)
+	return engine
+}
+
+// 调用OnRouteRegistered注册的函数
+func (engine *Engine) routeRegistered(method, path string, handlers HandlersChain) {
+	if len(engine.hooks.routeRegistered) == 0 {
+		return
+	}
+	handler := handlers.Last()
+	route := RouteInfo{
+		Method:      method,
+		Path:        path,
+		Handler:     nameOfFunction(handler),
+		HandlerFunc: handler,
+	}
+	for _, f := range engine.hooks.routeRegistered {
+		f(route)
+	}
+}
+
+// 在listener上启动服务，启动之前调用OnStart注册的函数，返回之前调用OnStop注册的函数
+func (engine *Engine) serveListener(listener net.Listener, serve func(net.Listener) error) error {
+	engine.started(listener)
+	defer engine.stopped()
+	return serve(listener)
+}
+
+// 调用OnStart注册的函数
+func (engine *Engine) started(listener net.Listener) {
+	for _, f := range engine.hooks.start {
+		f(listener)
+	}
+}
+
+// 调用OnStop注册的函数
+func (engine *Engine) stopped() {
+	for i := len(engine.hooks.stop) - 1; i >= 0; i-- {
+		engine.hooks.stop[i]()
+	}
+}
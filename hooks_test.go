@@ -0,0 +1,120 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnRouteRegistered(t *testing.T) {
+	router := New()
+	router.GET("/before", handlerTest1)
+
+	var routes []string
+	router.OnRouteRegistered(func(route RouteInfo) {
+		routes = append(routes, route.Method+" "+route.Path+" "+route.Handler)
+	})
+	router.Group("/api").POST("/after/:id", handlerTest2)
+
+	assert.Equal(t, []string{
+		"GET /before github.com/gin-gonic/gin.handlerTest1",
+		"POST /api/after/:id github.com/gin-gonic/gin.handlerTest2",
+	}, routes)
+}
+
+func TestOnRequestOnResponse(t *testing.T) {
+	router := New()
+	var calls []string
+	router.Use(func(c *Context) {
+		calls = append(calls, "middleware")
+	})
+	router.OnRequest(func(c *Context) {
+		calls = append(calls, "request "+c.Request.URL.Path)
+		if c.Query("deny") != "" {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+	})
+	router.OnResponse(func(c *Context) {
+		calls = append(calls, "response "+http.StatusText(c.Writer.Status()))
+	})
+	router.GET("/", func(c *Context) {
+		calls = append(calls, "handler")
+		c.Status(http.StatusAccepted)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, []string{"request /", "middleware", "handler", "response Accepted"}, calls)
+
+	calls = nil
+	w = PerformRequest(router, http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, []string{"request /missing", "middleware", "response Not Found"}, calls)
+
+	// OnRequest中Abort时不执行handler
+	calls = nil
+	w = PerformRequest(router, http.MethodGet, "/?deny=1")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, []string{"request /", "response Forbidden"}, calls)
+}
+
+func TestOnStartOnStop(t *testing.T) {
+	router := New()
+	started := make(chan net.Addr, 1)
+	var stopped []int
+	router.OnStart(func(l net.Listener) {
+		started <- l.Addr()
+	})
+	router.OnStop(func() {
+		stopped = append(stopped, 1)
+	}).OnStop(func() {
+		stopped = append(stopped, 2)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- router.RunWithContext(ctx, "127.0.0.1:0")
+	}()
+	select {
+	case addr := <-started:
+		assert.Contains(t, addr.String(), "127.0.0.1:")
+	case <-time.After(time.Second):
+		t.Fatal("OnStart was not called")
+	}
+	assert.Empty(t, stopped)
+	cancel()
+	assert.NoError(t, <-errs)
+	// 按注册的相反顺序调用
+	assert.Equal(t, []int{2, 1}, stopped)
+}
+
+func TestOnStartOnStopRunListener(t *testing.T) {
+	router := New()
+	var events []string
+	router.OnStart(func(net.Listener) {
+		events = append(events, "start")
+	})
+	router.OnStop(func() {
+		events = append(events, "stop")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	listener.Close()
+	assert.Error(t, router.RunListener(listener))
+	assert.Equal(t, []string{"start", "stop"}, events)
+
+	// 监听失败时不调用
+	events = nil
+	assert.Error(t, router.Run("127.0.0.1:-1"))
+	assert.Empty(t, events)
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// HTTP2Config h2c（UseH2C或者ServeGRPC）使用的HTTP/2服务端配置，为0的字段使用http2的默认值；
+// 内部服务的连接数少、每个连接的并发高，通常需要调大MaxConcurrentStreams和流控窗口
+type HTTP2Config struct {
+	// 每个连接允许的最大并发stream数，默认为250
+	MaxConcurrentStreams uint32
+
+	// 连接空闲多久之后关闭，默认不关闭
+	IdleTimeout time.Duration
+
+	// 允许读取的最大帧大小，范围为16KB到16MB，默认为1MB
+	MaxReadFrameSize uint32
+
+	// 每个连接和每个stream的上传流控窗口，默认为1MB
+	MaxUploadBufferPerConnection int32
+	MaxUploadBufferPerStream     int32
+
+	// HPACK解码和编码的动态表大小，默认为4KB
+	MaxDecoderHeaderTableSize uint32
+	MaxEncoderHeaderTableSize uint32
+}
+
+// 返回使用该配置的http2.Server
+func (conf HTTP2Config) server() *http2.Server {
+	return &http2.Server{
+		MaxConcurrentStreams:         conf.MaxConcurrentStreams,
+		IdleTimeout:                  conf.IdleTimeout,
+		MaxReadFrameSize:             conf.MaxReadFrameSize,
+		MaxUploadBufferPerConnection: conf.MaxUploadBufferPerConnection,
+		MaxUploadBufferPerStream:     conf.MaxUploadBufferPerStream,
+		MaxDecoderHeaderTableSize:    conf.MaxDecoderHeaderTableSize,
+		MaxEncoderHeaderTableSize:    conf.MaxEncoderHeaderTableSize,
+	}
+}
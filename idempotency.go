@@ -0,0 +1,340 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 默认的幂等key header和保存时间
+const (
+	defaultIdempotencyHeader = "Idempotency-Key"
+	defaultIdempotencyTTL    = 24 * time.Hour
+	// 计算摘要时默认允许读取的最大body
+	defaultIdempotencyMaxBodySize = 1 << 20
+)
+
+// 重放保存的响应时添加的header
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// IdempotentResponse 幂等请求保存的响应
+type IdempotentResponse struct {
+	// 第一次请求的method、path以及body的摘要，同一个key用于其他请求（包括body不同的重试）时返回422
+	Fingerprint string
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore 保存幂等请求的响应，实现需要并发安全，
+// 多实例部署时可以使用Redis等共享存储实现
+type IdempotencyStore interface {
+	// 返回key对应的已完成的响应，不存在或者仍在处理中时返回nil
+	Get(key string) (*IdempotentResponse, error)
+	// 标记key为处理中，key已经处理中或者已经保存了响应时返回false；ttl之后锁自动失效
+	Lock(key string, ttl time.Duration) (bool, error)
+	// 保存key对应的响应并解除处理中的标记
+	Set(key string, resp *IdempotentResponse, ttl time.Duration) error
+	// 解除处理中的标记，不保存响应，之后相同key的请求会重新处理
+	Unlock(key string) error
+}
+
+// IdempotencyConfig 幂等middleware的配置
+type IdempotencyConfig struct {
+	// 保存响应的store，默认为NewMemoryIdempotencyStore()
+	Store IdempotencyStore
+
+	// 响应的保存时间，默认为24小时
+	TTL time.Duration
+
+	// 携带幂等key的header，默认为Idempotency-Key
+	Header string
+
+	// 需要处理的method，默认只处理POST
+	Methods []string
+
+	// 返回保存使用的key，用于按用户等维度隔离key；默认按认证的用户（AuthUserKey）、
+	// Authorization或者Cookie header隔离，都不存在时直接使用header的值，
+	// 匿名客户端之间不隔离，这种情况需要设置KeyFunc
+	KeyFunc func(c *Context, key string) string
+
+	// 返回是否保存该状态码的响应，默认不保存5xx响应，客户端可以重试
+	ShouldStore func(status int) bool
+
+	// 计算摘要时允许读取的最大body，超过时返回413，默认为1MB，小于0时不限制；
+	// 需要读取完整的body计算摘要，限制用于避免大body占用过多内存
+	MaxBodySize int64
+}
+
+// 返回使用内存store的幂等middleware，POST请求携带Idempotency-Key header时，
+// 保存第一次的响应并在TTL内重放给相同key的重试请求；相同key的请求正在处理时返回409
+func Idempotency() HandlerFunc {
+	return IdempotencyWithConfig(IdempotencyConfig{})
+}
+
+// 通过配置返回幂等middleware
+func IdempotencyWithConfig(conf IdempotencyConfig) HandlerFunc {
+	store := conf.Store
+	if store == nil {
+		store = NewMemoryIdempotencyStore()
+	}
+	ttl := conf.TTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	header := conf.Header
+	if header == "" {
+		header = defaultIdempotencyHeader
+	}
+	methods := map[string]bool{http.MethodPost: true}
+	if len(conf.Methods) > 0 {
+		methods = make(map[string]bool, len(conf.Methods))
+		for _, method := range conf.Methods {
+			methods[method] = true
+		}
+	}
+	shouldStore := conf.ShouldStore
+	if shouldStore == nil {
+		shouldStore = func(status int) bool { return status < http.StatusInternalServerError }
+	}
+	maxBodySize := conf.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = defaultIdempotencyMaxBodySize
+	}
+
+	return func(c *Context) {
+		key := c.GetHeader(header)
+		if key == "" || !methods[c.Request.Method] {
+			c.Next()
+			return
+		}
+		if conf.KeyFunc != nil {
+			key = conf.KeyFunc(c, key)
+		} else {
+			key = scopeIdempotencyKey(c, key)
+		}
+		fingerprint, err := idempotencyFingerprint(c, maxBodySize)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				_ = c.AbortWithError(http.StatusRequestEntityTooLarge, err)
+				return
+			}
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		resp, err := store.Get(key)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		if resp != nil {
+			replayIdempotentResponse(c, resp, fingerprint)
+			return
+		}
+
+		locked, err := store.Lock(key, ttl)
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		if !locked {
+			// 加锁之前其他请求可能已经完成
+			if resp, err = store.Get(key); err == nil && resp != nil {
+				replayIdempotentResponse(c, resp, fingerprint)
+				return
+			}
+			c.AbortWithStatus(http.StatusConflict)
+			return
+		}
+
+		stored := false
+		defer func() {
+			if !stored {
+				_ = store.Unlock(key)
+			}
+		}()
+
+		w := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		status := w.Status()
+		if !shouldStore(status) {
+			return
+		}
+		err = store.Set(key, &IdempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      w.Header().Clone(),
+			Body:        w.body.Bytes(),
+		}, ttl)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		stored = true
+	}
+}
+
+// 默认的key隔离：按认证的用户、Authorization或者Cookie header的摘要添加前缀
+func scopeIdempotencyKey(c *Context, key string) string {
+	var identity string
+	switch {
+	case c.GetString(AuthUserKey) != "":
+		identity = "user:" + c.GetString(AuthUserKey)
+	case c.requestHeader("Authorization") != "":
+		identity = "authorization:" + c.requestHeader("Authorization")
+	case c.requestHeader("Cookie") != "":
+		identity = "cookie:" + c.requestHeader("Cookie")
+	default:
+		return key
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:]) + ":" + key
+}
+
+// 返回"METHOD PATH BODY-SHA256"，读取body之后重新设置c.Request.Body，
+// maxBodySize大于0时body超过该大小返回*http.MaxBytesError
+func idempotencyFingerprint(c *Context, maxBodySize int64) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		r := c.Request.Body
+		if maxBodySize > 0 {
+			r = http.MaxBytesReader(c.Writer, r, maxBodySize)
+		}
+		var err error
+		if body, err = io.ReadAll(r); err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	sum := sha256.Sum256(body)
+	return c.Request.Method + " " + c.Request.URL.Path + " " + hex.EncodeToString(sum[:]), nil
+}
+
+// 重放保存的响应，请求与第一次请求的method、path或者body不同时返回422
+func replayIdempotentResponse(c *Context, resp *IdempotentResponse, fingerprint string) {
+	if resp.Fingerprint != fingerprint {
+		c.AbortWithStatus(http.StatusUnprocessableEntity)
+		return
+	}
+	header := c.Writer.Header()
+	for key, value := range resp.Header {
+		header[key] = value
+	}
+	header.Set(IdempotencyReplayedHeader, "true")
+	c.Writer.WriteHeader(resp.Status)
+	c.Writer.WriteHeaderNow()
+	_, _ = c.Writer.Write(resp.Body)
+	c.Abort()
+}
+
+// 记录写入的body，用于保存响应
+type idempotencyWriter struct {
+	ResponseWriter
+	body bytes.Buffer
+}
+
+// 重写ResponseWriter Write函数接口
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// 重写ResponseWriter WriteString函数接口
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// MemoryIdempotencyStore 保存在内存中的IdempotencyStore，适用于单实例部署
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+// 内存store中的记录，resp为nil时表示处理中
+type idempotencyEntry struct {
+	resp    *IdempotentResponse
+	expires time.Time
+}
+
+// 接口实现校验
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+
+// 返回内存store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]*idempotencyEntry)}
+}
+
+// 实现IdempotencyStore Get函数接口
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, nil
+	}
+	return e.resp, nil
+}
+
+// 实现IdempotencyStore Lock函数接口
+func (s *MemoryIdempotencyStore) Lock(key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return false, nil
+	}
+	s.entries[key] = &idempotencyEntry{expires: now.Add(ttl)}
+	return true, nil
+}
+
+// 实现IdempotencyStore Set函数接口
+func (s *MemoryIdempotencyStore) Set(key string, resp *IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &idempotencyEntry{resp: resp, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// 实现IdempotencyStore Unlock函数接口
+func (s *MemoryIdempotencyStore) Unlock(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// 每分钟最多清理一次过期的记录
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
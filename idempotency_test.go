@@ -0,0 +1,271 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyReplay(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(Idempotency())
+	router.POST("/orders", func(c *Context) {
+		n := atomic.AddInt32(&calls, 1)
+		c.Header("X-Order", "created")
+		c.JSON(http.StatusCreated, H{"call": n})
+	})
+	router.POST("/other", func(c *Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/orders", header{Key: "Idempotency-Key", Value: "k1"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"call":1}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotencyReplayedHeader))
+
+	w = PerformRequest(router, http.MethodPost, "/orders", header{Key: "Idempotency-Key", Value: "k1"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"call":1}`, w.Body.String())
+	assert.Equal(t, "created", w.Header().Get("X-Order"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get(IdempotencyReplayedHeader))
+
+	// 不同的key和没有key的请求会重新处理
+	w = PerformRequest(router, http.MethodPost, "/orders", header{Key: "Idempotency-Key", Value: "k2"})
+	assert.Equal(t, `{"call":2}`, w.Body.String())
+	w = PerformRequest(router, http.MethodPost, "/orders")
+	assert.Equal(t, `{"call":3}`, w.Body.String())
+
+	// 相同的key用于其他路径
+	w = PerformRequest(router, http.MethodPost, "/other", header{Key: "Idempotency-Key", Value: "k1"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIdempotencyFingerprintAndScope(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(Idempotency())
+	router.POST("/orders", func(c *Context) {
+		body, _ := c.GetRawData()
+		c.SetCookie("session", "s", 0, "/", "", false, true)
+		c.String(http.StatusCreated, "%d %s", atomic.AddInt32(&calls, 1), body)
+	})
+	post := func(body string, headers ...header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "k")
+		for _, h := range headers {
+			req.Header.Set(h.Key, h.Value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	alice := header{Key: "Authorization", Value: "Bearer alice"}
+	w := post(`{"amount":1}`, alice)
+	assert.Equal(t, "1 {\"amount\":1}", w.Body.String())
+	w = post(`{"amount":1}`, alice)
+	assert.Equal(t, "1 {\"amount\":1}", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(IdempotencyReplayedHeader))
+
+	// body不同的重试返回422
+	w = post(`{"amount":2}`, alice)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// 其他用户使用相同的key不会得到第一个用户的响应
+	w = post(`{"amount":1}`, header{Key: "Authorization", Value: "Bearer bob"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "2 {\"amount\":1}", w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotencyReplayedHeader))
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.Equal(t, "k", scopeIdempotencyKey(c, "k"))
+	c.Set(AuthUserKey, "alice")
+	assert.NotEqual(t, "k", scopeIdempotencyKey(c, "k"))
+}
+
+func TestIdempotencyMaxBodySize(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(IdempotencyWithConfig(IdempotencyConfig{MaxBodySize: 8}))
+	router.POST("/orders", func(c *Context) {
+		atomic.AddInt32(&calls, 1)
+		c.Status(http.StatusCreated)
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "k")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"amount":1}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	// 超过限制的请求没有占用key
+	w = post(`{"a":1}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 默认限制为1MB，小于0时不限制
+	router = New()
+	router.Use(Idempotency())
+	router.POST("/orders", func(c *Context) { c.Status(http.StatusCreated) })
+	w = post(strings.Repeat("a", defaultIdempotencyMaxBodySize+1))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	router = New()
+	router.Use(IdempotencyWithConfig(IdempotencyConfig{MaxBodySize: -1}))
+	router.POST("/orders", func(c *Context) { c.Status(http.StatusCreated) })
+	w = post(strings.Repeat("a", defaultIdempotencyMaxBodySize+1))
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestIdempotencySkipsServerErrors(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(Idempotency())
+	router.POST("/pay", func(c *Context) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+		c.String(http.StatusOK, "paid")
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/pay", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = PerformRequest(router, http.MethodPost, "/pay", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "paid", w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotencyReplayedHeader))
+}
+
+func TestIdempotencyPanicUnlocks(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	router := New()
+	router.Use(Recovery(), IdempotencyWithConfig(IdempotencyConfig{Store: store}))
+	router.POST("/panic", func(c *Context) {
+		panic("oops")
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/panic", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	locked, err := store.Lock("k", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router := New()
+	router.Use(Idempotency())
+	router.POST("/slow", func(c *Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w := PerformRequest(router, http.MethodPost, "/slow", header{Key: "Idempotency-Key", Value: "k"})
+		assert.Equal(t, "done", w.Body.String())
+	}()
+	<-started
+
+	w := PerformRequest(router, http.MethodPost, "/slow", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	close(release)
+	wg.Wait()
+
+	w = PerformRequest(router, http.MethodPost, "/slow", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "done", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(IdempotencyReplayedHeader))
+}
+
+func TestIdempotencyWithConfig(t *testing.T) {
+	var calls int32
+	router := New()
+	router.Use(IdempotencyWithConfig(IdempotencyConfig{
+		Header:  "X-Request-Key",
+		Methods: []string{http.MethodPut},
+		KeyFunc: func(c *Context, key string) string {
+			return c.GetHeader("X-User") + ":" + key
+		},
+	}))
+	handler := func(c *Context) {
+		c.String(http.StatusOK, "%d", atomic.AddInt32(&calls, 1))
+	}
+	router.PUT("/items", handler)
+	router.POST("/items", handler)
+
+	w := PerformRequest(router, http.MethodPut, "/items", header{Key: "X-Request-Key", Value: "k"}, header{Key: "X-User", Value: "a"})
+	assert.Equal(t, "1", w.Body.String())
+	w = PerformRequest(router, http.MethodPut, "/items", header{Key: "X-Request-Key", Value: "k"}, header{Key: "X-User", Value: "a"})
+	assert.Equal(t, "1", w.Body.String())
+	w = PerformRequest(router, http.MethodPut, "/items", header{Key: "X-Request-Key", Value: "k"}, header{Key: "X-User", Value: "b"})
+	assert.Equal(t, "2", w.Body.String())
+	w = PerformRequest(router, http.MethodPost, "/items", header{Key: "X-Request-Key", Value: "k"}, header{Key: "X-User", Value: "a"})
+	assert.Equal(t, "3", w.Body.String())
+}
+
+type failingIdempotencyStore struct {
+	MemoryIdempotencyStore
+}
+
+func (s *failingIdempotencyStore) Get(string) (*IdempotentResponse, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestIdempotencyStoreError(t *testing.T) {
+	router := New()
+	router.Use(IdempotencyWithConfig(IdempotencyConfig{Store: &failingIdempotencyStore{}}))
+	router.POST("/", func(c *Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/", header{Key: "Idempotency-Key", Value: "k"})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestMemoryIdempotencyStoreExpires(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	locked, _ := store.Lock("k", time.Minute)
+	assert.True(t, locked)
+	locked, _ = store.Lock("k", time.Minute)
+	assert.False(t, locked)
+
+	resp, _ := store.Get("k")
+	assert.Nil(t, resp)
+
+	_ = store.Set("k", &IdempotentResponse{Status: http.StatusOK}, -time.Second)
+	resp, _ = store.Get("k")
+	assert.Nil(t, resp)
+	locked, _ = store.Lock("k", time.Minute)
+	assert.True(t, locked)
+
+	_ = store.Unlock("k")
+	assert.Empty(t, store.entries)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import "net/http"
+
+// JSONEnvelopeFunc 在编码之前转换JSON响应，返回值代替obj被编码
+type JSONEnvelopeFunc func(c *Context, code int, obj any) any
+
+// Envelope DefaultJSONEnvelope使用的响应结构
+type Envelope struct {
+	Data      any    `json:"data"`
+	Error     any    `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// 将obj包装为Envelope：status code小于400时作为data，否则作为error；
+// request_id依次从c.Keys["request_id"]、请求和响应的X-Request-ID header获取。
+// obj已经是Envelope时不再包装
+func DefaultJSONEnvelope(c *Context, code int, obj any) any {
+	switch obj.(type) {
+	case Envelope, *Envelope:
+		return obj
+	}
+	env := Envelope{RequestID: envelopeRequestID(c)}
+	if code >= http.StatusBadRequest {
+		env.Error = obj
+	} else {
+		env.Data = obj
+	}
+	return env
+}
+
+// 返回请求ID，与Logger的默认配置相同
+func envelopeRequestID(c *Context) string {
+	if id, ok := c.Keys["request_id"].(string); ok && id != "" {
+		return id
+	}
+	if id := c.requestHeader("X-Request-ID"); id != "" {
+		return id
+	}
+	return c.Writer.Header().Get("X-Request-ID")
+}
+
+// 返回为路由或者路由组设置JSON响应转换的middleware，覆盖Engine.JSONEnvelope，f为nil时不转换
+//
+//	api := router.Group("/api", gin.WithJSONEnvelope(gin.DefaultJSONEnvelope))
+//	router.Group("/legacy", gin.WithJSONEnvelope(nil))
+func WithJSONEnvelope(f JSONEnvelopeFunc) HandlerFunc {
+	return func(c *Context) {
+		c.jsonEnvelope = &f
+		c.Next()
+	}
+}
+
+// 根据路由或者Engine的配置转换JSON响应
+func (c *Context) envelopeJSON(code int, obj any) any {
+	var f JSONEnvelopeFunc
+	if c.engine != nil {
+		f = c.engine.JSONEnvelope
+	}
+	if c.jsonEnvelope != nil {
+		f = *c.jsonEnvelope
+	}
+	if f == nil {
+		return obj
+	}
+	return f(c, code, obj)
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONEnvelope(t *testing.T) {
+	router := New()
+	router.JSONEnvelope = DefaultJSONEnvelope
+	router.GET("/user", func(c *Context) {
+		c.JSON(http.StatusOK, H{"name": "gin"})
+	})
+	router.GET("/missing", func(c *Context) {
+		c.Set("request_id", "rid-1")
+		c.AbortWithStatusJSON(http.StatusNotFound, "not found")
+	})
+	router.GET("/wrapped", func(c *Context) {
+		c.PureJSON(http.StatusOK, Envelope{Data: "<b>", RequestID: "manual"})
+	})
+	router.GET("/string", func(c *Context) {
+		c.String(http.StatusOK, "plain")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/user", header{Key: "X-Request-ID", Value: "rid-0"})
+	assert.Equal(t, `{"data":{"name":"gin"},"error":null,"request_id":"rid-0"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"data":null,"error":"not found","request_id":"rid-1"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/wrapped")
+	assert.Equal(t, `{"data":"<b>","error":null,"request_id":"manual"}`+"\n", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/string")
+	assert.Equal(t, "plain", w.Body.String())
+}
+
+func TestJSONEnvelopeGroup(t *testing.T) {
+	router := New()
+	router.JSONEnvelope = DefaultJSONEnvelope
+
+	custom := func(c *Context, code int, obj any) any {
+		return H{"code": code, "result": obj}
+	}
+	v2 := router.Group("/v2", WithJSONEnvelope(custom))
+	v2.GET("/ping", func(c *Context) {
+		c.IndentedJSON(http.StatusOK, "pong")
+	})
+	legacy := router.Group("/legacy", WithJSONEnvelope(nil))
+	legacy.GET("/ping", func(c *Context) {
+		c.JSON(http.StatusOK, "pong")
+	})
+	router.GET("/ping", func(c *Context) {
+		c.SecureJSON(http.StatusOK, []string{"pong"})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/v2/ping")
+	assert.Equal(t, "{\n    \"code\": 200,\n    \"result\": \"pong\"\n}", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/legacy/ping")
+	assert.Equal(t, `"pong"`, w.Body.String())
+
+	// 不是数组的响应不添加SecureJSON前缀
+	w = PerformRequest(router, http.MethodGet, "/ping")
+	assert.Equal(t, `{"data":["pong"],"error":null}`, w.Body.String())
+}
+
+func TestJSONEnvelopeDisabledByDefault(t *testing.T) {
+	router := New()
+	router.GET("/", func(c *Context) {
+		c.JSON(http.StatusOK, H{"ok": true})
+	})
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+}
+
+func TestJSONEnvelopeWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := &Context{}
+	c.writermem.reset(w)
+	c.Writer = &c.writermem
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.JSON(http.StatusOK, H{"ok": true})
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+}
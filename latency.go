@@ -0,0 +1,175 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 直方图bucket的数量和最小上界，上界每个bucket增长2^(1/4)，覆盖10µs到约167s
+const (
+	latencyBucketCount = 97
+	latencyBucketBase  = 10 * time.Microsecond
+)
+
+// 每个bucket的上界
+var latencyBucketBounds = func() []time.Duration {
+	bounds := make([]time.Duration, latencyBucketCount)
+	for i := range bounds {
+		bounds[i] = time.Duration(float64(latencyBucketBase) * math.Pow(2, float64(i)/4))
+	}
+	return bounds
+}()
+
+// LatencyStats 路由的延迟统计，百分位数为直方图的估计值（误差约19%）
+type LatencyStats struct {
+	Count uint64
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// LatencyRecorder 按路由记录延迟直方图，零值可以直接使用，并发安全
+// 路由以"METHOD FullPath"作为key，例如"GET /users/:id"
+type LatencyRecorder struct {
+	mu     sync.Mutex
+	routes map[string]*latencyHistogram
+}
+
+// 单个路由的直方图
+type latencyHistogram struct {
+	buckets  [latencyBucketCount + 1]uint64
+	count    uint64
+	sum      time.Duration
+	min, max time.Duration
+}
+
+// 记录route的一次延迟
+func (r *LatencyRecorder) Observe(route string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.routes == nil {
+		r.routes = make(map[string]*latencyHistogram)
+	}
+	h, ok := r.routes[route]
+	if !ok {
+		h = &latencyHistogram{}
+		r.routes[route] = h
+	}
+	h.observe(latency)
+}
+
+// 返回route的延迟统计
+func (r *LatencyRecorder) Stats(route string) (LatencyStats, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.routes[route]
+	if !ok {
+		return LatencyStats{}, false
+	}
+	return h.stats(), true
+}
+
+// 返回全部路由的延迟统计
+func (r *LatencyRecorder) Snapshot() map[string]LatencyStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make(map[string]LatencyStats, len(r.routes))
+	for route, h := range r.routes {
+		snapshot[route] = h.stats()
+	}
+	return snapshot
+}
+
+// 返回已记录的路由，按字母顺序排序
+func (r *LatencyRecorder) Routes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	routes := make([]string, 0, len(r.routes))
+	for route := range r.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// 清除全部记录
+func (r *LatencyRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = nil
+}
+
+// 记录一次延迟
+func (h *latencyHistogram) observe(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+	idx := sort.Search(latencyBucketCount, func(i int) bool {
+		return latencyBucketBounds[i] >= latency
+	})
+	h.buckets[idx]++
+	if h.count == 0 || latency < h.min {
+		h.min = latency
+	}
+	if latency > h.max {
+		h.max = latency
+	}
+	h.count++
+	h.sum += latency
+}
+
+// 计算统计值
+func (h *latencyHistogram) stats() LatencyStats {
+	if h.count == 0 {
+		return LatencyStats{}
+	}
+	return LatencyStats{
+		Count: h.count,
+		Min:   h.min,
+		Max:   h.max,
+		Mean:  h.sum / time.Duration(h.count),
+		P50:   h.percentile(0.50),
+		P95:   h.percentile(0.95),
+		P99:   h.percentile(0.99),
+	}
+}
+
+// 估计百分位数，返回所在bucket的上界，并限制在[min, max]之间
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	rank := uint64(math.Ceil(p * float64(h.count)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range h.buckets {
+		seen += n
+		if seen < rank {
+			continue
+		}
+		if i >= latencyBucketCount {
+			return h.max
+		}
+		value := latencyBucketBounds[i]
+		if value > h.max {
+			value = h.max
+		}
+		if value < h.min {
+			value = h.min
+		}
+		return value
+	}
+	return h.max
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyRecorder(t *testing.T) {
+	var r LatencyRecorder
+	_, ok := r.Stats("GET /")
+	assert.False(t, ok)
+
+	for i := 1; i <= 100; i++ {
+		r.Observe("GET /", time.Duration(i)*time.Millisecond)
+	}
+	r.Observe("POST /", 5*time.Second)
+
+	stats, ok := r.Stats("GET /")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), stats.Count)
+	assert.Equal(t, time.Millisecond, stats.Min)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 50500*time.Microsecond, stats.Mean)
+	assert.InEpsilon(t, float64(50*time.Millisecond), float64(stats.P50), 0.2)
+	assert.InEpsilon(t, float64(95*time.Millisecond), float64(stats.P95), 0.2)
+	assert.InEpsilon(t, float64(99*time.Millisecond), float64(stats.P99), 0.2)
+	assert.LessOrEqual(t, stats.P99, stats.Max)
+
+	stats, _ = r.Stats("POST /")
+	assert.Equal(t, 5*time.Second, stats.P50)
+	assert.Equal(t, 5*time.Second, stats.P99)
+
+	assert.Equal(t, []string{"GET /", "POST /"}, r.Routes())
+	assert.Len(t, r.Snapshot(), 2)
+
+	// 超出最大bucket
+	r.Observe("GET /slow", time.Hour)
+	stats, _ = r.Stats("GET /slow")
+	assert.Equal(t, time.Hour, stats.P50)
+
+	r.Reset()
+	assert.Empty(t, r.Snapshot())
+}
+
+func TestLoggerLatencyRecorder(t *testing.T) {
+	recorder := &LatencyRecorder{}
+	router := New()
+	router.Use(LoggerWithConfig(LoggerConfig{Output: io.Discard, LatencyRecorder: recorder}))
+	router.GET("/users/:id", func(c *Context) {})
+
+	PerformRequest(router, http.MethodGet, "/users/1")
+	PerformRequest(router, http.MethodGet, "/users/2")
+	PerformRequest(router, http.MethodGet, "/notfound")
+
+	assert.Equal(t, []string{"GET /users/:id"}, recorder.Routes())
+	stats, _ := recorder.Stats("GET /users/:id")
+	assert.Equal(t, uint64(2), stats.Count)
+}
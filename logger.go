@@ -5,10 +5,14 @@
 package gin
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mattn/go-isatty"
@@ -45,6 +49,49 @@ type LoggerConfig struct {
 
 	// SkipPaths路径下的Logger将记录日志
 	SkipPaths []string
+
+	// 请求完成后调用，返回true时不记录日志，可以访问status、Errors和Keys，与SkipPaths同时生效
+	Skipper func(c *Context) bool
+
+	// 记录日志的最小status code，例如400表示只记录错误请求，0表示记录所有请求
+	// Context.Errors不为空的请求总是会记录
+	MinLevelByStatus int
+
+	// 按status类别采样记录日志，key为status code的百位数（2表示2xx），value为记录的比例[0, 1]
+	// 未设置的类别全部记录，Context.Errors不为空的请求总是会记录
+	SampleByStatus map[int]float64
+
+	// 请求ID在Keys中的key，默认为request_id，Keys中不存在时从请求或响应的RequestIDHeader获取
+	RequestIDKey string
+	// 请求ID的header，默认为X-Request-ID
+	RequestIDHeader string
+	// trace ID和span ID在Keys中的key，默认为trace_id和span_id，Keys中不存在时从W3C traceparent header获取
+	TraceIDKey string
+	SpanIDKey  string
+	// 为true时默认的日志格式输出请求ID、trace ID和span ID，自定义Formatter通过LogFormatterParams获取
+	LogCorrelation bool
+
+	// 获取当前时间的Clock，用于时间戳和延迟，默认使用time.Now
+	Clock Clock
+
+	// 不为nil时按路由记录延迟直方图，SkipPaths中的路径也会记录，未匹配到路由的请求不记录
+	LatencyRecorder *LatencyRecorder
+
+	// 大于0时在handler之前读取请求body的前RequestBodySize字节，记录到LogFormatterParams.RequestBody，
+	// JSON body按照Engine.Redaction.JSONFields隐藏字段，handler仍然可以读取完整的body
+	RequestBodySize int
+}
+
+// Clock 提供当前时间，可以在测试中替换为固定的时间
+type Clock interface {
+	Now() time.Time
+}
+
+// 使用time.Now的Clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
 }
 
 // 格式化输出Logger的函数签名
@@ -75,6 +122,16 @@ type LogFormatterParams struct {
 	BodySize int
 	// Context设置的Keys
 	Keys map[string]any
+	// 请求ID
+	RequestID string
+	// trace ID
+	TraceID string
+	// span ID
+	SpanID string
+	// 默认的日志格式是否输出请求ID和trace ID
+	logCorrelation bool
+	// LoggerConfig.RequestBodySize大于0时记录的请求body，已经按照Engine.Redaction隐藏
+	RequestBody string
 }
 
 // 根据请求状态，设置terminal中的ANSI颜色
@@ -139,17 +196,87 @@ var defaultLogFormatter = func(param LogFormatterParams) string {
 	if param.Latency > time.Minute {
 		param.Latency = param.Latency.Truncate(time.Second)
 	}
-	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v%s\n%s",
 		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
 		statusColor, param.StatusCode, resetColor,
 		param.Latency,
 		param.ClientIP,
 		methodColor, param.Method, resetColor,
 		param.Path,
+		param.correlation(),
 		param.ErrorMessage,
 	)
 }
 
+// 返回请求ID、trace ID和span ID组成的日志字段，未开启LogCorrelation或者都为空时返回空字符串
+func (p *LogFormatterParams) correlation() string {
+	if !p.logCorrelation {
+		return ""
+	}
+	var b strings.Builder
+	for _, field := range [...]struct{ name, value string }{
+		{"request_id", p.RequestID},
+		{"trace_id", p.TraceID},
+		{"span_id", p.SpanID},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(" |")
+		}
+		// 值可能来自客户端的header，添加引号避免伪造日志字段
+		b.WriteString(" " + field.name + "=" + strconv.Quote(field.value))
+	}
+	return b.String()
+}
+
+// 获取Keys中key对应的字符串
+func logKeyString(keys map[string]any, key string) string {
+	switch v := keys[key].(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	return ""
+}
+
+// 解析W3C traceparent header，格式为version-traceid-spanid-flags
+func parseTraceparent(header string) (traceID, spanID string) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 || !isLowerHex(parts[1]) || !isLowerHex(parts[2]) {
+		return "", ""
+	}
+	return parts[1], parts[2]
+}
+
+// 判断s是否只包含小写的十六进制字符
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && (s[i] < 'a' || s[i] > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// 根据MinLevelByStatus和SampleByStatus判断是否记录当前请求
+func (conf *LoggerConfig) shouldLog(c *Context) bool {
+	if len(c.Errors) > 0 {
+		return true
+	}
+	status := c.Writer.Status()
+	if status < conf.MinLevelByStatus {
+		return false
+	}
+	rate, ok := conf.SampleByStatus[status/100]
+	if !ok || rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
 // 禁止输出color到console
 func DisableConsoleColor() {
 	consoleColorMode = disableColor
@@ -181,6 +308,16 @@ func Logger() HandlerFunc {
 	return LoggerWithConfig(LoggerConfig{})
 }
 
+// 实例化一个只记录错误请求的Logger middleware，status code小于400且没有Context.Errors的请求不记录
+func LoggerOnlyErrors() HandlerFunc {
+	return LoggerWithConfig(OnlyErrorsLoggerConfig())
+}
+
+// 返回只记录错误请求的LoggerConfig，可以在此基础上修改其他配置
+func OnlyErrorsLoggerConfig() LoggerConfig {
+	return LoggerConfig{MinLevelByStatus: http.StatusBadRequest}
+}
+
 // 通过指定的LogFormatter实例化Logger middleware
 func LoggerWithFormatter(f LogFormatter) HandlerFunc {
 	return LoggerWithConfig(LoggerConfig{
@@ -204,6 +341,30 @@ func LoggerWithConfig(conf LoggerConfig) HandlerFunc {
 		formatter = defaultLogFormatter
 	}
 
+	// 设置请求ID和trace的key
+	requestIDKey := conf.RequestIDKey
+	if requestIDKey == "" {
+		requestIDKey = "request_id"
+	}
+	requestIDHeader := conf.RequestIDHeader
+	if requestIDHeader == "" {
+		requestIDHeader = "X-Request-ID"
+	}
+	traceIDKey := conf.TraceIDKey
+	if traceIDKey == "" {
+		traceIDKey = "trace_id"
+	}
+	spanIDKey := conf.SpanIDKey
+	if spanIDKey == "" {
+		spanIDKey = "span_id"
+	}
+
+	// 设置clock
+	clock := conf.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
 	//　设置output
 	out := conf.Output
 	if out == nil {
@@ -234,13 +395,33 @@ func LoggerWithConfig(conf LoggerConfig) HandlerFunc {
 
 	return func(c *Context) {
 		// 开始时间
-		start := time.Now()
+		start := clock.Now()
 		path := c.Request.URL.Path
 		raw := c.Request.URL.RawQuery
 
+		// 在handler读取之前记录请求body
+		var body []byte
+		var truncated bool
+		if conf.RequestBodySize > 0 {
+			body, truncated = peekRequestBody(c.Request, conf.RequestBodySize)
+		}
+
 		// 进行下一个处理请求
 		c.Next()
 
+		end := clock.Now()
+		if conf.LatencyRecorder != nil && c.FullPath() != "" {
+			conf.LatencyRecorder.Observe(c.Request.Method+" "+c.FullPath(), end.Sub(start))
+		}
+
+		// 按status code过滤和采样
+		if !conf.shouldLog(c) {
+			return
+		}
+		if conf.Skipper != nil && conf.Skipper(c) {
+			return
+		}
+
 		// path不在skip map中，则记录日志
 		if _, ok := skip[path]; !ok {
 			// LogFormatter参数
@@ -248,10 +429,12 @@ func LoggerWithConfig(conf LoggerConfig) HandlerFunc {
 				Request: c.Request,
 				isTerm:  isTerm,
 				Keys:    c.Keys,
+
+				logCorrelation: conf.LogCorrelation,
 			}
 
 			// 记录数据
-			param.TimeStamp = time.Now()
+			param.TimeStamp = end
 
 			param.Latency = param.TimeStamp.Sub(start)
 
@@ -262,14 +445,66 @@ func LoggerWithConfig(conf LoggerConfig) HandlerFunc {
 
 			param.BodySize = c.Writer.Size()
 
+			// 请求ID和trace ID，优先使用Keys中的值
+			param.RequestID = logKeyString(c.Keys, requestIDKey)
+			if param.RequestID == "" {
+				param.RequestID = c.Request.Header.Get(requestIDHeader)
+			}
+			if param.RequestID == "" {
+				param.RequestID = c.Writer.Header().Get(requestIDHeader)
+			}
+			param.TraceID = logKeyString(c.Keys, traceIDKey)
+			param.SpanID = logKeyString(c.Keys, spanIDKey)
+			if param.TraceID == "" {
+				traceID, spanID := parseTraceparent(c.Request.Header.Get("traceparent"))
+				param.TraceID = traceID
+				// 不覆盖Keys中的span ID
+				if param.SpanID == "" {
+					param.SpanID = spanID
+				}
+			}
+
 			if raw != "" {
+				// 隐藏Engine.Redaction中的query参数
+				if c.engine != nil {
+					raw = c.engine.Redaction.RedactQuery(raw)
+				}
 				path = path + "?" + raw
 			}
 
 			param.Path = path
 
+			if len(body) > 0 {
+				// 隐藏Engine.Redaction中的JSON字段
+				var redaction Redaction
+				if c.engine != nil {
+					redaction = c.engine.Redaction
+				}
+				param.RequestBody = redaction.redactBody(c.Request.Header.Get("Content-Type"), body, truncated)
+			}
+
 			// 将formatter的数据写入到out stream中
 			fmt.Fprint(out, formatter(param))
 		}
 	}
 }
+
+// 读取req.Body的前size字节，之后将读取的内容放回body，返回读取的内容以及body是否超过size
+func peekRequestBody(req *http.Request, size int) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false
+	}
+	data, _ := io.ReadAll(io.LimitReader(req.Body, int64(size)+1))
+	// 读取失败时handler再次读取原始body得到错误
+	req.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(data), req.Body), Closer: req.Body}
+	if len(data) > size {
+		return data[:size], true
+	}
+	return data, false
+}
+
+// 放回已经读取内容的请求body
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
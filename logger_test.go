@@ -7,7 +7,9 @@ package gin
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -433,3 +435,234 @@ func TestForceConsoleColor(t *testing.T) {
 	// reset console color mode.
 	consoleColorMode = autoColor
 }
+
+type fixedClock struct {
+	times []time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	now := c.times[0]
+	if len(c.times) > 1 {
+		c.times = c.times[1:]
+	}
+	return now
+}
+
+func TestLoggerWithClock(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := &fixedClock{times: []time.Time{start, start.Add(1500 * time.Millisecond)}}
+
+	var params LogFormatterParams
+	router := New()
+	router.Use(LoggerWithConfig(LoggerConfig{
+		Output: io.Discard,
+		Clock:  clock,
+		Formatter: func(p LogFormatterParams) string {
+			params = p
+			return ""
+		},
+	}))
+	router.GET("/", func(c *Context) {})
+	PerformRequest(router, "GET", "/")
+
+	assert.Equal(t, start.Add(1500*time.Millisecond), params.TimeStamp)
+	assert.Equal(t, 1500*time.Millisecond, params.Latency)
+}
+
+func TestLoggerCorrelationIDs(t *testing.T) {
+	var params LogFormatterParams
+	formatter := func(p LogFormatterParams) string {
+		params = p
+		return ""
+	}
+
+	router := New()
+	router.Use(LoggerWithConfig(LoggerConfig{Output: io.Discard, Formatter: formatter}))
+	router.GET("/header", func(c *Context) {})
+	router.GET("/keys", func(c *Context) {
+		c.Set("request_id", "key-rid")
+		c.Set("trace_id", "key-trace")
+		c.Set("span_id", "key-span")
+	})
+	router.GET("/response", func(c *Context) {
+		c.Header("X-Request-ID", "resp-rid")
+	})
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	PerformRequest(router, "GET", "/header",
+		header{"X-Request-ID", "req-rid"}, header{"traceparent", traceparent})
+	assert.Equal(t, "req-rid", params.RequestID)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", params.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", params.SpanID)
+
+	PerformRequest(router, "GET", "/keys",
+		header{"X-Request-ID", "req-rid"}, header{"traceparent", traceparent})
+	assert.Equal(t, "key-rid", params.RequestID)
+	assert.Equal(t, "key-trace", params.TraceID)
+	assert.Equal(t, "key-span", params.SpanID)
+
+	// 只有span ID在Keys中时不被traceparent覆盖
+	router.GET("/span", func(c *Context) {
+		c.Set("span_id", "key-span")
+	})
+	PerformRequest(router, "GET", "/span", header{"traceparent", traceparent})
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", params.TraceID)
+	assert.Equal(t, "key-span", params.SpanID)
+
+	PerformRequest(router, "GET", "/header", header{"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e47 x-00f067aa0ba902b7-01"})
+	assert.Empty(t, params.TraceID)
+
+	PerformRequest(router, "GET", "/response", header{"traceparent", "invalid"})
+	assert.Equal(t, "resp-rid", params.RequestID)
+	assert.Empty(t, params.TraceID)
+	assert.Empty(t, params.SpanID)
+
+	// 自定义key和header
+	router = New()
+	router.Use(LoggerWithConfig(LoggerConfig{
+		Output:          io.Discard,
+		Formatter:       formatter,
+		RequestIDKey:    "rid",
+		RequestIDHeader: "X-Correlation-ID",
+		TraceIDKey:      "tid",
+		SpanIDKey:       "sid",
+	}))
+	router.GET("/", func(c *Context) {
+		c.Set("tid", "custom-trace")
+		c.Set("sid", "custom-span")
+	})
+	PerformRequest(router, "GET", "/", header{"X-Correlation-ID", "corr"}, header{"X-Request-ID", "ignored"})
+	assert.Equal(t, "corr", params.RequestID)
+	assert.Equal(t, "custom-trace", params.TraceID)
+	assert.Equal(t, "custom-span", params.SpanID)
+}
+
+func TestDefaultLogFormatterCorrelation(t *testing.T) {
+	params := LogFormatterParams{
+		TimeStamp:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		StatusCode: 200,
+		Latency:    time.Second,
+		ClientIP:   "127.0.0.1",
+		Method:     "GET",
+		Path:       "/",
+	}
+	assert.Equal(t, "[GIN] 2026/01/02 - 03:04:05 | 200 |            1s |       127.0.0.1 | GET      \"/\"\n", defaultLogFormatter(params))
+
+	// 未开启LogCorrelation时不改变默认的日志格式
+	params.RequestID = "rid"
+	params.TraceID = "tid"
+	assert.Equal(t, "[GIN] 2026/01/02 - 03:04:05 | 200 |            1s |       127.0.0.1 | GET      \"/\"\n", defaultLogFormatter(params))
+
+	params.logCorrelation = true
+	assert.Equal(t, "[GIN] 2026/01/02 - 03:04:05 | 200 |            1s |       127.0.0.1 | GET      \"/\" | request_id=\"rid\" trace_id=\"tid\"\n", defaultLogFormatter(params))
+
+	// 客户端提供的值不能伪造日志字段
+	params.RequestID = "x trace_id=forged\n[GIN] fake"
+	params.TraceID = ""
+	assert.Equal(t, "[GIN] 2026/01/02 - 03:04:05 | 200 |            1s |       127.0.0.1 | GET      \"/\" | request_id=\"x trace_id=forged\\n[GIN] fake\"\n", defaultLogFormatter(params))
+}
+
+func TestLoggerCorrelationOptIn(t *testing.T) {
+	buffer := new(strings.Builder)
+	router := New()
+	router.Use(LoggerWithWriter(buffer))
+	router.GET("/", func(c *Context) {})
+	PerformRequest(router, "GET", "/", header{"X-Request-ID", "rid"})
+	assert.NotContains(t, buffer.String(), "request_id")
+
+	buffer.Reset()
+	router = New()
+	router.Use(LoggerWithConfig(LoggerConfig{Output: buffer, LogCorrelation: true}))
+	router.GET("/", func(c *Context) {})
+	PerformRequest(router, "GET", "/", header{"X-Request-ID", "rid"})
+	assert.Contains(t, buffer.String(), `| request_id="rid"`)
+}
+
+func TestLoggerMinLevelByStatus(t *testing.T) {
+	buffer := new(strings.Builder)
+	conf := OnlyErrorsLoggerConfig()
+	conf.Output = buffer
+	router := New()
+	router.Use(LoggerWithConfig(conf))
+	router.GET("/ok", func(c *Context) {})
+	router.GET("/bad", func(c *Context) { c.Status(http.StatusBadRequest) })
+	router.GET("/error", func(c *Context) { _ = c.Error(errors.New("oops")) })
+
+	PerformRequest(router, "GET", "/ok")
+	assert.Empty(t, buffer.String())
+
+	PerformRequest(router, "GET", "/bad")
+	assert.Contains(t, buffer.String(), "/bad")
+
+	buffer.Reset()
+	PerformRequest(router, "GET", "/error")
+	assert.Contains(t, buffer.String(), "/error")
+
+	buffer.Reset()
+	PerformRequest(router, "GET", "/missing")
+	assert.Contains(t, buffer.String(), "/missing")
+}
+
+func TestLoggerSampleByStatus(t *testing.T) {
+	buffer := new(strings.Builder)
+	router := New()
+	router.Use(LoggerWithConfig(LoggerConfig{
+		Output:         buffer,
+		SampleByStatus: map[int]float64{2: 0, 3: 1},
+	}))
+	router.GET("/ok", func(c *Context) {})
+	router.GET("/redirect", func(c *Context) { c.Redirect(http.StatusFound, "/ok") })
+	router.GET("/error", func(c *Context) { _ = c.Error(errors.New("oops")) })
+
+	PerformRequest(router, "GET", "/ok")
+	assert.Empty(t, buffer.String())
+
+	PerformRequest(router, "GET", "/error")
+	assert.Contains(t, buffer.String(), "/error")
+
+	buffer.Reset()
+	PerformRequest(router, "GET", "/redirect")
+	assert.Contains(t, buffer.String(), "/redirect")
+
+	buffer.Reset()
+	PerformRequest(router, "GET", "/missing")
+	assert.Contains(t, buffer.String(), "/missing")
+}
+
+func TestLoggerOnlyErrors(t *testing.T) {
+	buffer := new(strings.Builder)
+	DefaultWriter = buffer
+	defer func() { DefaultWriter = os.Stdout }()
+
+	router := New()
+	router.Use(LoggerOnlyErrors())
+	router.GET("/ok", func(c *Context) {})
+
+	PerformRequest(router, "GET", "/ok")
+	assert.Empty(t, buffer.String())
+	PerformRequest(router, "GET", "/missing")
+	assert.Contains(t, buffer.String(), "404")
+}
+
+func TestLoggerWithConfigSkipper(t *testing.T) {
+	buffer := new(strings.Builder)
+	router := New()
+	router.Use(LoggerWithConfig(LoggerConfig{
+		Output: buffer,
+		Skipper: func(c *Context) bool {
+			return c.FullPath() == "/metrics" && c.Writer.Status() == http.StatusOK && c.GetBool("quiet")
+		},
+	}))
+	router.GET("/metrics", func(c *Context) {
+		if c.Query("fail") != "" {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Set("quiet", true)
+	})
+
+	PerformRequest(router, "GET", "/metrics")
+	assert.Empty(t, buffer.String())
+
+	PerformRequest(router, "GET", "/metrics?fail=1")
+	assert.Contains(t, buffer.String(), "500")
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+// Matcher 路由表的不可变快照，可以在处理请求之外匹配路由，例如离线检查权限策略或者生成文档
+// 创建之后注册的路由不会影响Matcher，Matcher可以被多个goroutine并发使用
+type Matcher struct {
+	trees            methodTrees
+	routes           map[string]map[string]RouteInfo
+	optionalRoutes   map[string]map[string]optionalRoute
+	maxParams        uint16
+	maxSections      uint16
+	removeExtraSlash bool
+	strictStatic     bool
+	normalization    PathNormalization
+}
+
+// 返回当前路由表的Matcher
+func (engine *Engine) Matcher() *Matcher {
+	rt := engine.currentRoutes()
+	m := &Matcher{
+		trees:            make(methodTrees, 0, len(rt.trees)),
+		routes:           make(map[string]map[string]RouteInfo, len(rt.trees)),
+		maxParams:        rt.maxParams,
+		maxSections:      rt.maxSections,
+		removeExtraSlash: engine.RemoveExtraSlash,
+		strictStatic:     engine.StrictStaticPriority,
+		normalization:    engine.PathNormalization,
+	}
+	for _, tree := range rt.trees {
+		root := tree.root.clone()
+		m.trees = append(m.trees, methodTree{method: tree.method, root: root})
+		routes := make(map[string]RouteInfo)
+		for _, route := range iterate("", tree.method, nil, root) {
+			routes[route.Path] = route
+		}
+		m.routes[tree.method] = routes
+		if optional := rt.optionalRoutes[tree.method]; optional != nil {
+			if m.optionalRoutes == nil {
+				m.optionalRoutes = make(map[string]map[string]optionalRoute)
+			}
+			m.optionalRoutes[tree.method] = make(map[string]optionalRoute, len(optional))
+			for path, route := range optional {
+				m.optionalRoutes[tree.method][path] = route
+			}
+		}
+	}
+	return m
+}
+
+// 匹配method和path对应的路由，返回路由信息和参数，path为未编码的路径（与URL.Path相同）
+// 没有匹配的路由时返回false，不会进行尾部'/'和大小写的重定向
+func (m *Matcher) Match(method, path string) (RouteInfo, Params, bool) {
+	root := m.trees.get(method)
+	if root == nil {
+		return RouteInfo{}, nil, false
+	}
+	if m.removeExtraSlash {
+		path = cleanPath(path)
+	}
+	if m.normalization != PathNormalizationNone {
+		path = m.normalization.normalize(path)
+	}
+	params := make(Params, 0, m.maxParams)
+	skippedNodes := make([]skippedNode, 0, m.maxSections)
+	value := root.getValueWithOptions(path, &params, &skippedNodes, lookupOptions{strictStatic: m.strictStatic})
+	if value.handlers == nil {
+		return RouteInfo{}, nil, false
+	}
+	if value.params != nil {
+		params = *value.params
+	}
+	route := m.routes[method][value.fullPath]
+	if optional := m.optionalRoutes[method]; optional != nil {
+		route.Path, params = applyOptionalRoute(optional, value.fullPath, params)
+	}
+	return route, params, true
+}
+
+// 深拷贝node，handlers不会被修改，可以共享
+func (n *node) clone() *node {
+	c := *n
+	if len(n.children) > 0 {
+		c.children = make([]*node, len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+	return &c
+}
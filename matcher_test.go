@@ -0,0 +1,92 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineMatcher(t *testing.T) {
+	router := New()
+	router.GET("/users/:id", handlerTest1)
+	router.GET("/users/new", handlerTest2)
+	router.POST("/users", handlerTest2)
+	router.GET("/static/*filepath", handlerTest1)
+
+	m := router.Matcher()
+
+	route, params, ok := m.Match("GET", "/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "GET", route.Method)
+	assert.Equal(t, "/users/:id", route.Path)
+	assert.Regexp(t, "handlerTest1$", route.Handler)
+	assert.Equal(t, Params{{Key: "id", Value: "42"}}, params)
+
+	route, params, ok = m.Match("GET", "/users/new")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/new", route.Path)
+	assert.Empty(t, params)
+
+	route, params, ok = m.Match("GET", "/static/css/app.css")
+	assert.True(t, ok)
+	assert.Equal(t, "/static/*filepath", route.Path)
+	assert.Equal(t, Params{{Key: "filepath", Value: "/css/app.css"}}, params)
+
+	route, _, ok = m.Match("POST", "/users")
+	assert.True(t, ok)
+	assert.Equal(t, "POST", route.Method)
+
+	_, _, ok = m.Match("DELETE", "/users")
+	assert.False(t, ok)
+	_, _, ok = m.Match("GET", "/users/42/")
+	assert.False(t, ok)
+	_, _, ok = m.Match("GET", "/missing")
+	assert.False(t, ok)
+}
+
+func TestEngineMatcherSnapshot(t *testing.T) {
+	router := New()
+	router.RemoveExtraSlash = true
+	router.GET("/a/:id", handlerTest1)
+	m := router.Matcher()
+
+	// Matcher创建之后注册的路由不可见
+	router.GET("/a/b", handlerTest2)
+	router.GET("/c", handlerTest2)
+	_, _, ok := m.Match("GET", "/c")
+	assert.False(t, ok)
+
+	route, params, ok := m.Match("GET", "/a/b")
+	assert.True(t, ok)
+	assert.Equal(t, "/a/:id", route.Path)
+	assert.Equal(t, Params{{Key: "id", Value: "b"}}, params)
+
+	route, _, ok = router.Matcher().Match("GET", "//a//b")
+	assert.True(t, ok)
+	assert.Equal(t, "/a/b", route.Path)
+}
+
+func TestEngineMatcherConcurrent(t *testing.T) {
+	router := New()
+	router.GET("/users/:id/posts/:post", handlerTest1)
+	m := router.Matcher()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, params, ok := m.Match("GET", "/users/1/posts/2")
+				assert.True(t, ok)
+				assert.Equal(t, "2", params.ByName("post"))
+			}
+		}()
+	}
+	wg.Wait()
+}
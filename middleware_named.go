@@ -0,0 +1,116 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ReplaceMiddleware找不到对应名字的middleware时返回的错误
+	ErrMiddlewareNotFound = errors.New("gin: no middleware registered with this name")
+	// release模式下调用ReplaceMiddleware时返回的错误
+	ErrMiddlewareReplaceDisabled = errors.New("gin: middleware can only be replaced in debug or test mode")
+)
+
+// 通过UseNamed注册的middleware，处理请求时调用当前的handler
+type namedMiddleware struct {
+	original HandlerFunc
+	// 当前的handler，为nil时跳过该middleware
+	current atomic.Pointer[HandlerFunc]
+}
+
+// 通过UseNamed注册的所有middleware
+type namedMiddlewares struct {
+	mu      sync.Mutex
+	entries map[string][]*namedMiddleware
+}
+
+// 调用当前的handler，middleware被禁用时直接执行之后的handler
+func (m *namedMiddleware) handle(c *Context) {
+	if h := m.current.Load(); h != nil {
+		(*h)(c)
+		return
+	}
+	c.Next()
+}
+
+// 记录名字为name的middleware，返回注册到路由中的handler
+func (engine *Engine) addNamedMiddleware(name string, middleware HandlerFunc) HandlerFunc {
+	assert1(name != "", "middleware name can not be empty")
+	assert1(middleware != nil, "middleware can not be nil")
+	m := &namedMiddleware{original: middleware}
+	m.current.Store(&middleware)
+
+	engine.named.mu.Lock()
+	defer engine.named.mu.Unlock()
+	if engine.named.entries == nil {
+		engine.named.entries = make(map[string][]*namedMiddleware)
+	}
+	engine.named.entries[name] = append(engine.named.entries[name], m)
+	return m.handle
+}
+
+// 添加一个带名字的middleware，在debug和test模式下可以通过Engine.ReplaceMiddleware替换或者禁用，
+// 相同的名字可以在多个RouterGroup中注册，替换时一起替换
+//
+//	router.UseNamed("auth", AuthMiddleware())
+func (group *RouterGroup) UseNamed(name string, middleware HandlerFunc) IRoutes {
+	return group.Use(group.engine.addNamedMiddleware(name, middleware))
+}
+
+// 添加一个带名字的全局middleware，参考RouterGroup.UseNamed
+func (engine *Engine) UseNamed(name string, middleware HandlerFunc) IRoutes {
+	return engine.Use(engine.addNamedMiddleware(name, middleware))
+}
+
+// 将通过UseNamed注册的名字为name的middleware替换为middleware，middleware为nil时禁用该middleware，
+// 替换对之后的请求立即生效，只能在debug和test模式下使用，用于本地开发和e2e测试：
+//
+//	gin.SetMode(gin.TestMode)
+//	router.ReplaceMiddleware("auth", func(c *gin.Context) {
+//		c.Set("user", "tester")
+//	})
+//	defer router.RestoreMiddleware("auth")
+func (engine *Engine) ReplaceMiddleware(name string, middleware HandlerFunc) error {
+	if Mode() == ReleaseMode {
+		return ErrMiddlewareReplaceDisabled
+	}
+	engine.named.mu.Lock()
+	defer engine.named.mu.Unlock()
+	entries, ok := engine.named.entries[name]
+	if !ok {
+		return ErrMiddlewareNotFound
+	}
+	if middleware == nil {
+		for _, m := range entries {
+			m.current.Store(nil)
+		}
+		debugPrint("[WARNING] Middleware %q disabled", name)
+		return nil
+	}
+	for _, m := range entries {
+		m.current.Store(&middleware)
+	}
+	debugPrint("[WARNING] Middleware %q replaced by %s", name, nameOfFunction(middleware))
+	return nil
+}
+
+// 恢复通过ReplaceMiddleware替换的middleware
+func (engine *Engine) RestoreMiddleware(name string) error {
+	engine.named.mu.Lock()
+	defer engine.named.mu.Unlock()
+	entries, ok := engine.named.entries[name]
+	if !ok {
+		return ErrMiddlewareNotFound
+	}
+	for _, m := range entries {
+		original := m.original
+		m.current.Store(&original)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceMiddleware(t *testing.T) {
+	defer SetMode(Mode())
+	SetMode(TestMode)
+
+	auth := func(c *Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+	router := New()
+	router.UseNamed("auth", auth)
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, c.GetString("user"))
+	})
+	admin := router.Group("/admin")
+	admin.UseNamed("auth", auth)
+	admin.GET("/users", func(c *Context) {
+		c.String(http.StatusOK, "admin "+c.GetString("user"))
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, PerformRequest(router, http.MethodGet, "/").Code)
+
+	assert.NoError(t, router.ReplaceMiddleware("auth", func(c *Context) {
+		c.Set("user", "tester")
+	}))
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "tester", w.Body.String())
+	w = PerformRequest(router, http.MethodGet, "/admin/users")
+	assert.Equal(t, "admin tester", w.Body.String())
+
+	// 禁用middleware
+	assert.NoError(t, router.ReplaceMiddleware("auth", nil))
+	w = PerformRequest(router, http.MethodGet, "/admin/users")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin ", w.Body.String())
+
+	assert.NoError(t, router.RestoreMiddleware("auth"))
+	assert.Equal(t, http.StatusUnauthorized, PerformRequest(router, http.MethodGet, "/admin/users").Code)
+	// 全局middleware同样作用于404
+	assert.Equal(t, http.StatusUnauthorized, PerformRequest(router, http.MethodGet, "/missing").Code)
+}
+
+func TestReplaceMiddlewareErrors(t *testing.T) {
+	defer SetMode(Mode())
+	router := New()
+	router.UseNamed("auth", func(c *Context) {})
+
+	SetMode(DebugMode)
+	assert.ErrorIs(t, router.ReplaceMiddleware("missing", nil), ErrMiddlewareNotFound)
+	assert.ErrorIs(t, router.RestoreMiddleware("missing"), ErrMiddlewareNotFound)
+
+	SetMode(ReleaseMode)
+	assert.ErrorIs(t, router.ReplaceMiddleware("auth", nil), ErrMiddlewareReplaceDisabled)
+
+	assert.Panics(t, func() {
+		router.UseNamed("", func(c *Context) {})
+	})
+	assert.Panics(t, func() {
+		router.UseNamed("nil", nil)
+	})
+}
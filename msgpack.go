@@ -0,0 +1,42 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !nomsgpack
+
+package gin
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/ugorji/go/codec"
+)
+
+// 设置Context.MsgPack和msgpack binding使用的codec.MsgpackHandle，例如配置时间格式、RawToString或者Canonical；
+// 会替换通过SetBinding为msgpack设置的binding，h为nil时恢复默认的handle。h在使用之后不能再修改
+//
+//	h := &codec.MsgpackHandle{}
+//	h.RawToString = true
+//	router.SetMsgPackHandle(h)
+func (engine *Engine) SetMsgPackHandle(h *codec.MsgpackHandle) *Engine {
+	if h == nil {
+		engine.msgpackHandle = nil
+		return engine.SetBinding(mimeMsgPack, nil)
+	}
+	engine.msgpackHandle = h
+	return engine.SetBinding(mimeMsgPack, binding.MsgPackWithHandle(h))
+}
+
+// 生成MsgPack写入response body，设置Content-Type为"application/msgpack"，使用Engine.SetMsgPackHandle设置的handle
+func (c *Context) MsgPack(code int, obj any) {
+	var h *codec.MsgpackHandle
+	if c.engine != nil {
+		h, _ = c.engine.msgpackHandle.(*codec.MsgpackHandle)
+	}
+	c.MsgPackWithHandle(code, obj, h)
+}
+
+// 使用h编码MsgPack写入response body，h为nil时使用默认的handle
+func (c *Context) MsgPackWithHandle(code int, obj any, h *codec.MsgpackHandle) {
+	c.Render(code, render.MsgPack{Data: obj, Handle: h})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !nomsgpack
+
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+func TestEngineSetMsgPackHandle(t *testing.T) {
+	h := &codec.MsgpackHandle{}
+	h.RawToString = true
+	h.Canonical = true
+
+	router := New()
+	router.SetMsgPackHandle(h)
+	router.POST("/echo", func(c *Context) {
+		var obj map[string]any
+		if err := c.ShouldBind(&obj); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.MsgPack(http.StatusOK, obj)
+	})
+
+	var body bytes.Buffer
+	assert.NoError(t, codec.NewEncoder(&body, h).Encode(map[string]any{"b": "2", "a": "1"}))
+	req := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", mimeMsgPack)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
+	// RawToString使字符串解码为string，Canonical使key按顺序编码
+	assert.Equal(t, body.Bytes(), w.Body.Bytes())
+
+	router.SetMsgPackHandle(nil)
+	assert.Nil(t, router.msgpackHandle)
+	assert.Empty(t, router.bindings)
+}
+
+func TestContextMsgPackWithHandle(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.MsgPackWithHandle(http.StatusCreated, "gin", nil)
+
+	var out string
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&out))
+	assert.Equal(t, "gin", out)
+}
+
+func TestContextMsgPackWithoutEngine(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+
+	c.MsgPack(http.StatusOK, H{"foo": "bar"})
+
+	var obj map[string]any
+	assert.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&obj))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// 等待标签结束时默认最多缓存的字节数
+const defaultOutputFilterMaxBuffer = 64 << 10
+
+// 改写一块响应body，返回需要发送的数据；chunk以'>'结尾（响应结束、Flush或者超过缓存限制时除外），
+// 因此没有Flush时一个HTML标签不会被分割到两个chunk中。返回的数据可以与chunk共享内存
+type OutputFilterFunc func(c *Context, chunk []byte) []byte
+
+// OutputFilterConfig 改写响应body的middleware的配置
+type OutputFilterConfig struct {
+	// 需要改写的Content-Type（不包含参数），默认为text/html
+	ContentTypes []string
+
+	// 改写函数，按顺序调用
+	Filters []OutputFilterFunc
+
+	// 等待'>'时最多缓存的字节数，超过时直接改写缓存的数据，默认为64KB
+	MaxBuffer int
+}
+
+// 返回改写text/html响应的middleware，参考OutputFilterWithConfig
+func OutputFilter(filters ...OutputFilterFunc) HandlerFunc {
+	return OutputFilterWithConfig(OutputFilterConfig{Filters: filters})
+}
+
+// 通过配置返回改写响应body的middleware：Content-Type匹配并且没有Content-Encoding的响应，
+// 写入的数据在'>'处分块后依次经过Filters改写再发送，不需要缓存整个响应，流式响应同样适用；
+// 改写之后长度会发生变化，因此会删除Content-Length
+//
+//	if gin.IsDebugging() {
+//		router.Use(gin.OutputFilter(gin.HTMLInjectFilter(toolbar)))
+//	}
+func OutputFilterWithConfig(conf OutputFilterConfig) HandlerFunc {
+	contentTypes := conf.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{MIMEHTML}
+	}
+	types := make(map[string]bool, len(contentTypes))
+	for _, contentType := range contentTypes {
+		types[strings.ToLower(contentType)] = true
+	}
+	maxBuffer := conf.MaxBuffer
+	if maxBuffer <= 0 {
+		maxBuffer = defaultOutputFilterMaxBuffer
+	}
+
+	return func(c *Context) {
+		if len(conf.Filters) == 0 {
+			c.Next()
+			return
+		}
+		w := &outputFilterWriter{
+			ResponseWriter: c.Writer,
+			c:              c,
+			types:          types,
+			filters:        conf.Filters,
+			maxBuffer:      maxBuffer,
+		}
+		c.Writer = w
+		c.Next()
+		if err := w.finish(); err != nil {
+			_ = c.Error(err)
+		}
+		c.Writer = w.ResponseWriter
+	}
+}
+
+// 改写响应body的writer
+type outputFilterWriter struct {
+	ResponseWriter
+	c         *Context
+	types     map[string]bool
+	filters   []OutputFilterFunc
+	maxBuffer int
+	// 是否已经根据header判断过需要改写
+	decided   bool
+	filtering bool
+	// 等待'>'的数据
+	pending []byte
+}
+
+// 第一次写入之前根据header判断是否需要改写
+func (w *outputFilterWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return
+	}
+	if !w.types[strings.ToLower(filterFlags(header.Get("Content-Type")))] {
+		return
+	}
+	header.Del("Content-Length")
+	w.filtering = true
+}
+
+// 重写ResponseWriter WriteHeaderNow函数接口
+func (w *outputFilterWriter) WriteHeaderNow() {
+	w.decide()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// 重写ResponseWriter Write函数接口
+func (w *outputFilterWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if !w.filtering {
+		return w.ResponseWriter.Write(data)
+	}
+	w.pending = append(w.pending, data...)
+	end := bytes.LastIndexByte(w.pending, '>') + 1
+	if len(w.pending) > w.maxBuffer {
+		end = len(w.pending)
+	}
+	if end == 0 {
+		return len(data), nil
+	}
+	// 限制容量，避免改写函数append时覆盖之后的数据
+	err := w.emit(w.pending[:end:end])
+	w.pending = append(w.pending[:0], w.pending[end:]...)
+	return len(data), err
+}
+
+// 重写ResponseWriter WriteString函数接口
+func (w *outputFilterWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// 改写并发送一块数据
+func (w *outputFilterWriter) emit(chunk []byte) error {
+	for _, f := range w.filters {
+		chunk = f(w.c, chunk)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	if len(chunk) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(chunk)
+	return err
+}
+
+// 重写ResponseWriter Flush函数接口，改写并发送缓存的数据之后再Flush，
+// 缓存的数据中未结束的标签会被分割
+func (w *outputFilterWriter) Flush() {
+	w.decide()
+	if err := w.finish(); err != nil {
+		_ = w.c.Error(err)
+	}
+	w.ResponseWriter.Flush()
+}
+
+// 响应结束时改写并发送剩余的数据
+func (w *outputFilterWriter) finish() error {
+	if !w.filtering || len(w.pending) == 0 {
+		return nil
+	}
+	pending := w.pending
+	w.pending = nil
+	return w.emit(pending)
+}
+
+// 返回在</body>之前插入内容的OutputFilterFunc，例如debug模式下的工具栏，content返回空时不插入
+func HTMLInjectFilter(content func(c *Context) string) OutputFilterFunc {
+	return func(c *Context, chunk []byte) []byte {
+		i := indexFold(chunk, "</body>")
+		if i < 0 {
+			return chunk
+		}
+		s := content(c)
+		if s == "" {
+			return chunk
+		}
+		out := make([]byte, 0, len(chunk)+len(s))
+		out = append(out, chunk[:i]...)
+		out = append(out, s...)
+		return append(out, chunk[i:]...)
+	}
+}
+
+// CSPNonceFilter替换的nonce占位属性，需要在模板中显式写出
+const CSPNoncePlaceholder = `nonce="{{csp-nonce}}"`
+
+// 返回将CSPNoncePlaceholder替换为nonce属性的OutputFilterFunc，用于Content-Security-Policy；
+// 只替换模板作者写出的占位属性，不修改其他<script>和<style>标签，避免注入的标签也获得nonce，
+// nonce返回空时不修改
+//
+//	<script nonce="{{csp-nonce}}" src="/app.js"></script>
+//
+//	router.Use(func(c *gin.Context) {
+//		nonce := newNonce()
+//		c.Set("csp-nonce", nonce)
+//		c.Header("Content-Security-Policy", "script-src 'nonce-"+nonce+"'")
+//	}, gin.OutputFilter(gin.CSPNonceFilter(func(c *gin.Context) string {
+//		return c.GetString("csp-nonce")
+//	})))
+func CSPNonceFilter(nonce func(c *Context) string) OutputFilterFunc {
+	placeholder := []byte(CSPNoncePlaceholder)
+	return func(c *Context, chunk []byte) []byte {
+		if !bytes.Contains(chunk, placeholder) {
+			return chunk
+		}
+		value := nonce(c)
+		if value == "" {
+			return chunk
+		}
+		attr := []byte(`nonce="` + html.EscapeString(value) + `"`)
+		return bytes.ReplaceAll(chunk, placeholder, attr)
+	}
+}
+
+// 不区分大小写查找ASCII字符串substr，substr为小写
+func indexFold(s []byte, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		j := 0
+		for ; j < len(substr); j++ {
+			b := s[i+j]
+			if 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			if b != substr[j] {
+				break
+			}
+		}
+		if j == len(substr) {
+			return i
+		}
+	}
+	return -1
+}
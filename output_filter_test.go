@@ -0,0 +1,107 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputFilterHTML(t *testing.T) {
+	router := New()
+	router.Use(OutputFilter(
+		HTMLInjectFilter(func(c *Context) string {
+			return `<div id="toolbar"></div>`
+		}),
+		CSPNonceFilter(func(c *Context) string {
+			return "abc"
+		}),
+	))
+	router.GET("/page", func(c *Context) {
+		c.Header("Content-Length", "100")
+		c.Data(http.StatusOK, MIMEHTML+"; charset=utf-8", []byte(
+			`<html><SCRIPT nonce="{{csp-nonce}}" src="a.js"></SCRIPT><script nonce="x">1</script><style nonce="{{csp-nonce}}">p{}</style><script>injected()</script></BODY></html>`))
+	})
+	router.GET("/json", func(c *Context) {
+		c.PureJSON(http.StatusOK, H{"html": "</body>"})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/page")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Equal(t, `<html><SCRIPT nonce="abc" src="a.js"></SCRIPT><script nonce="x">1</script><style nonce="abc">p{}</style><script>injected()</script><div id="toolbar"></div></BODY></html>`, w.Body.String())
+
+	// 其他Content-Type不改写
+	w = PerformRequest(router, http.MethodGet, "/json")
+	assert.Equal(t, "{\"html\":\"</body>\"}\n", w.Body.String())
+}
+
+func TestOutputFilterChunked(t *testing.T) {
+	var chunks []string
+	router := New()
+	router.Use(OutputFilter(func(c *Context, chunk []byte) []byte {
+		chunks = append(chunks, string(chunk))
+		return append(chunk, '|')
+	}))
+	router.GET("/", func(c *Context) {
+		c.Header("Content-Type", MIMEHTML)
+		// 标签被分割到多次写入中
+		_, _ = c.Writer.WriteString("<p>one</")
+		_, _ = c.Writer.WriteString("p><b")
+		_, _ = c.Writer.WriteString("r>two")
+	})
+	router.GET("/stream", func(c *Context) {
+		c.Header("Content-Type", MIMEHTML)
+		_, _ = c.Writer.WriteString("<p>one")
+		// Flush时发送缓存的数据
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("</p>")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, []string{"<p>", "one</p>", "<br>", "two"}, chunks)
+	assert.Equal(t, "<p>|one</p>|<br>|two|", w.Body.String())
+
+	chunks = nil
+	w = PerformRequest(router, http.MethodGet, "/stream")
+	assert.Equal(t, []string{"<p>", "one", "</p>"}, chunks)
+	assert.Equal(t, "<p>|one|</p>|", w.Body.String())
+	assert.True(t, w.Flushed)
+}
+
+func TestOutputFilterSkip(t *testing.T) {
+	called := false
+	filter := func(c *Context, chunk []byte) []byte {
+		called = true
+		return nil
+	}
+	router := New()
+	router.Use(OutputFilterWithConfig(OutputFilterConfig{
+		ContentTypes: []string{"text/html", "application/xhtml+xml"},
+		Filters:      []OutputFilterFunc{filter},
+		MaxBuffer:    4,
+	}))
+	router.GET("/gzip", func(c *Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, MIMEHTML, []byte("<p>"))
+	})
+	router.GET("/xhtml", func(c *Context) {
+		c.Data(http.StatusOK, "application/XHTML+xml", bytes.Repeat([]byte("a"), 10))
+	})
+
+	// 压缩的响应不改写
+	w := PerformRequest(router, http.MethodGet, "/gzip")
+	assert.Equal(t, "<p>", w.Body.String())
+	assert.False(t, called)
+
+	// 超过缓存限制时直接改写
+	w = PerformRequest(router, http.MethodGet, "/xhtml")
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
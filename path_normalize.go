@@ -0,0 +1,51 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// 查找路由之前对请求路径进行的Unicode规范化，使不同客户端发送的等价路径匹配相同的路由
+type PathNormalization int
+
+const (
+	// 不进行规范化
+	PathNormalizationNone PathNormalization = iota
+	// 转换为NFC形式，例如"é"和"é"匹配相同的路由
+	PathNormalizationNFC
+	// 转换为NFC形式之后进行大小写折叠，路由匹配不区分大小写；参数的值同样来自折叠之后的路径
+	PathNormalizationNFCFold
+)
+
+// 规范化请求路径
+func (p PathNormalization) normalize(path string) string {
+	switch p {
+	case PathNormalizationNFC:
+		return norm.NFC.String(path)
+	case PathNormalizationNFCFold:
+		// cases.Caser不能被多个goroutine共享，每次使用时创建
+		return cases.Fold().String(norm.NFC.String(path))
+	}
+	return path
+}
+
+// 规范化注册的路由，只处理静态部分，参数名保持不变
+func (p PathNormalization) normalizeRoute(path string) string {
+	var b strings.Builder
+	for {
+		wildcard, i, _ := findWildcard(path)
+		if i < 0 {
+			b.WriteString(p.normalize(path))
+			return b.String()
+		}
+		b.WriteString(p.normalize(path[:i]))
+		b.WriteString(wildcard)
+		path = path[i+len(wildcard):]
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	cafeNFC = "/café"  // é为单个码点
+	cafeNFD = "/café" // e + 组合重音符
+)
+
+func performPathRequest(r http.Handler, method, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/", nil)
+	req.URL = &url.URL{Path: path}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPathNormalizationNormalize(t *testing.T) {
+	assert.Equal(t, cafeNFD, PathNormalizationNone.normalize(cafeNFD))
+	assert.Equal(t, cafeNFC, PathNormalizationNFC.normalize(cafeNFD))
+	assert.Equal(t, "/Café", PathNormalizationNFC.normalize("/Café"))
+	assert.Equal(t, cafeNFC, PathNormalizationNFCFold.normalize("/CAFÉ"))
+	assert.Equal(t, "/strasse", PathNormalizationNFCFold.normalize("/STRAßE"))
+
+	assert.Equal(t, "/café/:userID/*filePath", PathNormalizationNFCFold.normalizeRoute("/CAFÉ/:userID/*filePath"))
+	assert.Equal(t, "/users/:ID", PathNormalizationNFC.normalizeRoute("/users/:ID"))
+}
+
+func TestRoutePathNormalizationNFC(t *testing.T) {
+	router := New()
+	router.PathNormalization = PathNormalizationNFC
+	router.GET(cafeNFD+"/:name", func(c *Context) {
+		c.String(http.StatusOK, c.Param("name"))
+	})
+
+	w := performPathRequest(router, http.MethodGet, cafeNFC+"/crème")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "crème", w.Body.String())
+
+	w = performPathRequest(router, http.MethodGet, cafeNFD+"/crème")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "crème", w.Body.String())
+
+	w = performPathRequest(router, http.MethodGet, "/CAFÉ/x")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRoutePathNormalizationFold(t *testing.T) {
+	router := New()
+	router.PathNormalization = PathNormalizationNFCFold
+	router.GET("/Café/:userID", func(c *Context) {
+		c.String(http.StatusOK, c.Param("userID"))
+	})
+
+	w := performPathRequest(router, http.MethodGet, "/CAFÉ/Alice")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "alice", w.Body.String())
+
+	route, params, ok := router.Matcher().Match(http.MethodGet, "/cafÉ/Bob")
+	assert.True(t, ok)
+	assert.Equal(t, "/café/:userID", route.Path)
+	assert.Equal(t, Params{{Key: "userID", Value: "bob"}}, params)
+}
+
+func TestRoutePathNormalizationNone(t *testing.T) {
+	router := New()
+	router.GET(cafeNFC, handlerTest1)
+
+	w := performPathRequest(router, http.MethodGet, cafeNFD)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
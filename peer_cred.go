@@ -0,0 +1,50 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ErrPeerCredentialsUnsupported 当前平台不支持获取unix套接字对端的凭证
+var ErrPeerCredentialsUnsupported = errors.New("gin: peer credentials are not supported on this platform")
+
+// PeerCredentials unix套接字对端进程的凭证（SO_PEERCRED），在建立连接时获取
+type PeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// 保存PeerCredentials的context key
+type peerCredentialsKey struct{}
+
+// 用于http.Server.ConnContext，conn为unix套接字时获取对端的凭证并保存到context中，
+// 之后可以通过Context.PeerCredentials获取；RunUnix已经默认使用
+//
+//	srv := &http.Server{Handler: router, ConnContext: gin.PeerCredentialsConnContext}
+//	srv.Serve(unixListener)
+func PeerCredentialsConnContext(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	cred, err := readPeerCredentials(unixConn)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, peerCredentialsKey{}, cred)
+}
+
+// 返回unix套接字对端进程的UID、GID和PID，请求不是通过RunUnix或者使用PeerCredentialsConnContext的server接收时返回false
+func (c *Context) PeerCredentials() (PeerCredentials, bool) {
+	if c.Request == nil {
+		return PeerCredentials{}, false
+	}
+	cred, ok := c.Request.Context().Value(peerCredentialsKey{}).(PeerCredentials)
+	return cred, ok
+}
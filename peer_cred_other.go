@@ -0,0 +1,14 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package gin
+
+import "net"
+
+// 只支持linux
+func readPeerCredentials(*net.UnixConn) (PeerCredentials, error) {
+	return PeerCredentials{}, ErrPeerCredentialsUnsupported
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerCredentials(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_PEERCRED is only supported on linux")
+	}
+	router := New()
+	router.GET("/whoami", func(c *Context) {
+		cred, ok := c.PeerCredentials()
+		if !ok {
+			c.Status(http.StatusForbidden)
+			return
+		}
+		c.String(http.StatusOK, "%d %d %d", cred.PID, cred.UID, cred.GID)
+	})
+
+	socket := filepath.Join(t.TempDir(), "peer.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	srv := &http.Server{Handler: router, ConnContext: PeerCredentialsConnContext}
+	go srv.Serve(listener) //nolint: errcheck
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://unix/whoami")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("%d %d %d", os.Getpid(), os.Getuid(), os.Getgid()), string(body))
+}
+
+func TestPeerCredentialsNotUnix(t *testing.T) {
+	c, _ := CreateTestContext(nil)
+	_, ok := c.PeerCredentials()
+	assert.False(t, ok)
+
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	_, ok = c.PeerCredentials()
+	assert.False(t, ok)
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	ctx := context.Background()
+	assert.Equal(t, ctx, PeerCredentialsConnContext(ctx, server))
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProxyOptions 反向代理的配置
+type ProxyOptions struct {
+	// 转发使用的http.RoundTripper，默认为http.DefaultTransport
+	Transport http.RoundTripper
+
+	// 不为空时替换转发的请求路径，最终路径为target的路径与该路径拼接
+	Path string
+
+	// 为true时保留请求的Host header，默认使用target的Host
+	PreserveHost bool
+
+	// 为true时不设置X-Forwarded-For、X-Forwarded-Host和X-Forwarded-Proto
+	DisableForwardedHeaders bool
+
+	// 转发之前设置的请求header，值为空时删除该header
+	RequestHeaders map[string]string
+
+	// 返回给客户端之前设置的响应header，值为空时删除该header
+	ResponseHeaders map[string]string
+
+	// 在设置header之后修改转发的请求
+	Rewrite func(pr *httputil.ProxyRequest)
+
+	// Path的编码形式，由RouterGroup.Proxy设置，用于保留路径中编码的'/'（%2F）
+	rawPath string
+
+	// 修改上游的响应，返回error时由ErrorHandler处理
+	ModifyResponse func(resp *http.Response) error
+
+	// 刷新响应的间隔，为负数时每次写入后立即刷新，流式响应（例如SSE）总是立即刷新
+	FlushInterval time.Duration
+
+	// 上游请求失败时调用，默认返回502，超时返回504，客户端断开连接时不写入响应；
+	// error总是会通过c.Error记录
+	ErrorHandler func(c *Context, err error)
+}
+
+// 将请求转发到target，响应写入之后调用Abort，后续的handler不会执行；
+// 转发的路径为target的路径与请求路径（或者ProxyOptions.Path）拼接，query参数会合并
+func (c *Context) Proxy(target *url.URL, opts ...ProxyOptions) {
+	var opt ProxyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	proxy := &httputil.ReverseProxy{
+		Transport:     opt.Transport,
+		FlushInterval: opt.FlushInterval,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if opt.Path != "" {
+				pr.Out.URL.Path = opt.Path
+				pr.Out.URL.RawPath = opt.rawPath
+			}
+			pr.SetURL(target)
+			if opt.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+			if !opt.DisableForwardedHeaders {
+				pr.SetXForwarded()
+			}
+			setProxyHeaders(pr.Out.Header, opt.RequestHeaders)
+			if opt.Rewrite != nil {
+				opt.Rewrite(pr)
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			setProxyHeaders(resp.Header, opt.ResponseHeaders)
+			if opt.ModifyResponse != nil {
+				return opt.ModifyResponse(resp)
+			}
+			return nil
+		},
+		ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) {
+			_ = c.Error(err)
+			if opt.ErrorHandler != nil {
+				opt.ErrorHandler(c, err)
+				return
+			}
+			if errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil {
+				// 客户端已经断开连接，不需要写入响应
+				c.Abort()
+				return
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				c.AbortWithStatus(http.StatusGatewayTimeout)
+				return
+			}
+			c.AbortWithStatus(http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(proxyResponseWriter{c.Writer}, c.Request)
+	c.Abort()
+}
+
+// 将当前请求（包括body）转发到rawURL，并将响应（包括trailer）以流的方式返回给客户端；
+// 转发的路径为rawURL的路径，rawURL包含query时替换请求的query，rewrite不为nil时可以在转发之前修改请求。
+// 上游请求失败时的处理与Proxy相同，rawURL不合法时记录错误并返回500
+func (c *Context) ForwardTo(rawURL string, rewrite func(*http.Request)) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		if err == nil {
+			err = errors.New("gin: invalid forward URL " + strconv.Quote(rawURL))
+		}
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	target := &url.URL{Scheme: u.Scheme, Host: u.Host}
+	c.Proxy(target, ProxyOptions{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = u.Path
+			pr.Out.URL.RawPath = u.RawPath
+			if u.RawQuery != "" {
+				pr.Out.URL.RawQuery = u.RawQuery
+			}
+			if rewrite != nil {
+				rewrite(pr.Out)
+			}
+		},
+	})
+}
+
+// 隐藏CloseNotify，ReverseProxy会在writer实现http.CloseNotifier时调用CloseNotify，
+// 而底层的writer（例如httptest.ResponseRecorder）可能没有实现；Flush和Hijack通过Unwrap使用
+type proxyResponseWriter struct {
+	http.ResponseWriter
+}
+
+// 返回被封装的ResponseWriter，用于http.ResponseController
+func (w proxyResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// 将relativePath及其下的全部请求转发到target，转发时去掉relativePath前缀，
+// 例如group.Proxy("/api", target)将/api/users转发到target的/users；
+// 转发的路径会先经过path.Clean，包含".."的请求不会访问到target路径之外
+func (group *RouterGroup) Proxy(relativePath string, target *url.URL, opts ...ProxyOptions) IRoutes {
+	var opt ProxyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	handler := func(c *Context) {
+		o := opt
+		o.Path, o.rawPath = cleanProxyPath(c.Param("proxyPath"), c.Request.URL.EscapedPath())
+		c.Proxy(target, o)
+	}
+	return group.Any(path.Join(relativePath, "/*proxyPath"), handler)
+}
+
+// 返回清理之后的转发路径p，以及从请求的编码路径escaped中取出的p的编码形式；
+// 清理改变了路径（例如包含"."、".."或者多余的'/'）时不使用编码形式
+func cleanProxyPath(p, escaped string) (cleaned, raw string) {
+	cleaned = path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	if cleaned != p {
+		return cleaned, ""
+	}
+	// 从后往前查找解码之后与p相同的后缀
+	for i := strings.LastIndexByte(escaped, '/'); i >= 0; i = strings.LastIndexByte(escaped[:i], '/') {
+		unescaped, err := url.PathUnescape(escaped[i:])
+		if err != nil {
+			break
+		}
+		if unescaped == p {
+			return cleaned, escaped[i:]
+		}
+		if len(unescaped) > len(p) {
+			break
+		}
+	}
+	return cleaned, ""
+}
+
+// 设置header，值为空时删除该header
+func setProxyHeaders(header http.Header, values map[string]string) {
+	for key, value := range values {
+		if value == "" {
+			header.Del(key)
+			continue
+		}
+		header.Set(key, value)
+	}
+}
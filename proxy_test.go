@@ -0,0 +1,271 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 返回回显请求信息的上游服务
+func newProxyUpstream(t *testing.T) (*httptest.Server, *url.URL) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream", "yes")
+		w.Header().Set("X-Internal", "secret")
+		fmt.Fprintf(w, "%s %s?%s host=%s xff=%s tenant=%s cookie=%s",
+			r.Method, r.URL.Path, r.URL.RawQuery, r.Host,
+			r.Header.Get("X-Forwarded-For"), r.Header.Get("X-Tenant"), r.Header.Get("Cookie"))
+	}))
+	t.Cleanup(upstream.Close)
+	target, err := url.Parse(upstream.URL + "/base")
+	assert.NoError(t, err)
+	return upstream, target
+}
+
+func TestContextProxy(t *testing.T) {
+	_, target := newProxyUpstream(t)
+	router := New()
+	router.GET("/users/:id", func(c *Context) {
+		c.Proxy(target, ProxyOptions{
+			RequestHeaders:  map[string]string{"X-Tenant": "acme", "Cookie": ""},
+			ResponseHeaders: map[string]string{"X-Internal": "", "X-Gateway": "gin"},
+		})
+	}, func(c *Context) {
+		t.Error("handlers after Proxy must not run")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/users/1?fields=name", header{Key: "Cookie", Value: "session=1"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "GET /base/users/1?fields=name host="+target.Host+" xff=192.0.2.1 tenant=acme cookie=", w.Body.String())
+	assert.Equal(t, "yes", w.Header().Get("X-Upstream"))
+	assert.Equal(t, "gin", w.Header().Get("X-Gateway"))
+	assert.Empty(t, w.Header().Get("X-Internal"))
+}
+
+func TestRouterGroupProxy(t *testing.T) {
+	_, target := newProxyUpstream(t)
+	router := New()
+	router.Group("/gateway").Proxy("/api", target, ProxyOptions{
+		PreserveHost:            true,
+		DisableForwardedHeaders: true,
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/gateway/api/orders/7?x=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "POST /base/orders/7?x=1 host=example.com xff= tenant= cookie=", w.Body.String())
+
+	w = PerformRequest(router, http.MethodDelete, "/gateway/api/")
+	assert.Equal(t, "DELETE /base/?", w.Body.String()[:len("DELETE /base/?")])
+}
+
+func TestRouterGroupProxyCleanPath(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.EscapedPath())
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/public")
+	router := New()
+	router.Proxy("/api", target)
+
+	for path, expected := range map[string]string{
+		"/api/../private/secret":      "/public/private/secret",
+		"/api/a/%2E%2E/%2E%2E/secret": "/public/secret",
+		"/api/a//b/./c/":              "/public/a/b/c/",
+		"/api/files/a%2Fb":            "/public/files/a%2Fb",
+		"/api/files/a%20b":            "/public/files/a%20b",
+	} {
+		w := PerformRequest(router, http.MethodGet, path)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Equal(t, expected, w.Body.String(), path)
+	}
+}
+
+func TestCleanProxyPath(t *testing.T) {
+	p, raw := cleanProxyPath("/a/b/c", "/api/a%2Fb/c")
+	assert.Equal(t, "/a/b/c", p)
+	assert.Equal(t, "/a%2Fb/c", raw)
+
+	p, raw = cleanProxyPath("/a/../b", "/api/a%2F..%2Fb")
+	assert.Equal(t, "/b", p)
+	assert.Empty(t, raw)
+
+	p, raw = cleanProxyPath("", "/api")
+	assert.Equal(t, "/", p)
+	assert.Empty(t, raw)
+}
+
+func TestContextProxyError(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	target, _ := url.Parse(upstream.URL)
+	upstream.Close()
+
+	var errs []string
+	router := New()
+	router.Use(func(c *Context) {
+		c.Next()
+		errs = append(errs, c.Errors.Last().Error())
+	})
+	router.GET("/down", func(c *Context) {
+		c.Proxy(target)
+	})
+	router.GET("/custom", func(c *Context) {
+		c.Proxy(target, ProxyOptions{ErrorHandler: func(c *Context, err error) {
+			c.String(http.StatusServiceUnavailable, "upstream unavailable")
+		}})
+	})
+	router.GET("/rejected", func(c *Context) {
+		c.Proxy(target, ProxyOptions{
+			Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+			}),
+			ModifyResponse: func(*http.Response) error { return errors.New("rejected") },
+		})
+	})
+	router.GET("/timeout", func(c *Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Millisecond)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Proxy(target, ProxyOptions{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				<-r.Context().Done()
+				return nil, r.Context().Err()
+			}),
+		})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/down")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/custom")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "upstream unavailable", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/rejected")
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/timeout")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	assert.Len(t, errs, 4)
+	assert.Contains(t, errs[0], "connection refused")
+	assert.Equal(t, "rejected", errs[2])
+	assert.Contains(t, errs[3], "deadline exceeded")
+}
+
+func TestContextProxyStreaming(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+		fmt.Fprint(w, "data: second\n\n")
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	router := New()
+	router.GET("/events", func(c *Context) {
+		c.Proxy(target)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: first\n", line)
+
+	close(release)
+	var rest strings.Builder
+	_, _ = bufio.NewReader(reader).WriteTo(&rest)
+	assert.Equal(t, "\ndata: second\n\n", rest.String())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestContextForwardTo(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Trailer", "X-Checksum")
+		fmt.Fprintf(w, "%s %s?%s sig=%s body=%s", r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Signature"), body)
+		w.Header().Set("X-Checksum", "abc")
+	}))
+	defer upstream.Close()
+
+	router := New()
+	router.POST("/orders", func(c *Context) {
+		c.ForwardTo(upstream.URL+"/v2/orders", func(r *http.Request) {
+			r.Header.Set("X-Signature", "signed")
+		})
+	})
+	router.POST("/search", func(c *Context) {
+		c.ForwardTo(upstream.URL+"/find?q=fixed", nil)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/orders?dry=1", MIMEJSON, strings.NewReader(`{"id":1}`))
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, `POST /v2/orders?dry=1 sig=signed body={"id":1}`, string(body))
+	assert.Equal(t, "abc", resp.Trailer.Get("X-Checksum"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/search?q=user", strings.NewReader("x"))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "POST /find?q=fixed sig= body=x", w.Body.String())
+}
+
+func TestContextForwardToInvalidURL(t *testing.T) {
+	router := New()
+	var errs []*Error
+	router.GET("/", func(c *Context) {
+		c.ForwardTo("/relative", nil)
+		errs = c.Errors
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `gin: invalid forward URL "/relative"`, errs[0].Error())
+}
+
+func TestContextForwardToClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	router := New()
+	router.GET("/", func(c *Context) {
+		c.ForwardTo("http://backend.invalid/", func(r *http.Request) {
+			cancel()
+		})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	router.ServeHTTP(w, req)
+	assert.False(t, w.Flushed)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// push的target不是绝对路径或者绝对URL时返回的错误
+var ErrInvalidPushTarget = errors.New("gin: push target must be an absolute path or URL")
+
+// 使用HTTP/2 server push推送target，opts为nil时使用默认的选项；
+// 连接不支持push（HTTP/1.1、h2c或者客户端禁用了push）时不做任何处理并返回nil。
+// 写入响应之前调用时push被暂存，在写入header时按调用顺序一起推送，推送失败时忽略
+//
+//	c.Push("/static/app.css", nil)
+//	c.Push("/static/app.js", nil)
+//	c.HTML(http.StatusOK, "index.tmpl", data)
+func (c *Context) Push(target string, opts *http.PushOptions) error {
+	if !strings.HasPrefix(target, "/") && !strings.Contains(target, "://") {
+		return ErrInvalidPushTarget
+	}
+	pusher := c.Writer.Pusher()
+	if pusher == nil {
+		return nil
+	}
+	if !c.writermem.Written() {
+		c.writermem.pushes = append(c.writermem.pushes, pendingPush{target: target, opts: opts})
+		return nil
+	}
+	if err := pusher.Push(target, opts); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
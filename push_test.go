@@ -0,0 +1,85 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 记录push和写入header顺序的ResponseWriter
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	events []string
+	err    error
+}
+
+func (r *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	if r.err != nil {
+		return r.err
+	}
+	method := http.MethodGet
+	if opts != nil && opts.Method != "" {
+		method = opts.Method
+	}
+	r.events = append(r.events, "push "+method+" "+target)
+	return nil
+}
+
+func (r *pushRecorder) WriteHeader(code int) {
+	r.events = append(r.events, "header")
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func TestContextPushBatched(t *testing.T) {
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c, _ := CreateTestContext(w)
+
+	assert.NoError(t, c.Push("/app.css", nil))
+	assert.NoError(t, c.Push("/app.js", &http.PushOptions{Method: http.MethodHead}))
+	assert.Empty(t, w.events)
+
+	c.String(http.StatusOK, "index")
+	assert.Equal(t, []string{"push GET /app.css", "push HEAD /app.js", "header"}, w.events)
+
+	// 写入之后直接推送
+	assert.NoError(t, c.Push("https://example.com/late.js", nil))
+	assert.Equal(t, "push GET https://example.com/late.js", w.events[3])
+}
+
+func TestContextPushNotSupported(t *testing.T) {
+	// HTTP/1.1的writer没有实现http.Pusher
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	assert.NoError(t, c.Push("/app.css", nil))
+	c.String(http.StatusOK, "index")
+	assert.Equal(t, "index", w.Body.String())
+
+	// 客户端禁用push
+	pw := &pushRecorder{ResponseRecorder: httptest.NewRecorder(), err: http.ErrNotSupported}
+	c, _ = CreateTestContext(pw)
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	assert.NoError(t, c.Push("/app.css", nil))
+
+	pw.err = errors.New("push failed")
+	assert.EqualError(t, c.Push("/app.css", nil), "push failed")
+}
+
+func TestContextPushInvalidTarget(t *testing.T) {
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c, _ := CreateTestContext(w)
+	assert.ErrorIs(t, c.Push("app.css", nil), ErrInvalidPushTarget)
+
+	// 暂存的push失败时忽略
+	w.err = errors.New("push failed")
+	assert.NoError(t, c.Push("/app.css", nil))
+	c.String(http.StatusOK, "index")
+	assert.Equal(t, []string{"header"}, w.events)
+}
@@ -47,11 +47,37 @@ func RecoveryWithWriter(out io.Writer, recovery ...RecoveryFunc) HandlerFunc {
 	return CustomRecoveryWithWriter(out, defaultHandleRecovery)
 }
 
+// 定义Recovery middleware
+type RecoveryConfig struct {
+	// 日志的writer，为nil时不输出日志
+	Output io.Writer
+
+	// 出现panic时调用的处理函数，默认返回status code：500
+	Handle RecoveryFunc
+
+	// 获取日志时间的Clock，默认使用time.Now
+	Clock Clock
+}
+
 // 返回一个middleware，出现panic时，使用writer进行recovery，调用提供的handle func，并回显status code：500
 func CustomRecoveryWithWriter(out io.Writer, handle RecoveryFunc) HandlerFunc {
+	return RecoveryWithConfig(RecoveryConfig{Output: out, Handle: handle})
+}
+
+// 通过指定的RecoveryConfig实例化Recovery middleware
+func RecoveryWithConfig(conf RecoveryConfig) HandlerFunc {
+	// 设置clock，自定义clock时日志前缀不再使用log包的时间
+	clock, flags := conf.Clock, 0
+	if clock == nil {
+		clock, flags = systemClock{}, log.LstdFlags
+	}
 	var logger *log.Logger
-	if out != nil {
-		logger = log.New(out, "\n\n\x1b[31m", log.LstdFlags)
+	if conf.Output != nil {
+		logger = log.New(conf.Output, "\n\n\x1b[31m", flags)
+	}
+	handle := conf.Handle
+	if handle == nil {
+		handle = defaultHandleRecovery
 	}
 	return func(c *Context) {
 		defer func() {
@@ -72,25 +98,20 @@ func CustomRecoveryWithWriter(out io.Writer, handle RecoveryFunc) HandlerFunc {
 				if logger != nil {
 					stack := stack(3)
 					httpRequest, _ := httputil.DumpRequest(c.Request, false)
-					// 分割http header
-					headers := strings.Split(string(httpRequest), "\r\n")
-					// 校验Authorization header
-					for idx, header := range headers {
-						current := strings.Split(header, ":")
-						if current[0] == "Authorization" {
-							headers[idx] = current[0] + ": *"
-						}
+					// 隐藏Engine.Redaction中的header
+					var redaction Redaction
+					if c.engine != nil {
+						redaction = c.engine.Redaction
 					}
-					// 拼接http header
-					headersToStr := strings.Join(headers, "\r\n")
+					headersToStr := string(redaction.RedactDump(httpRequest))
 					if brokenPipe { // 如果断开连接
 						logger.Printf("%s\n%s%s", err, headersToStr, reset)
 					} else if IsDebugging() { // 如果是debug模式
 						logger.Printf("[Recovery] %s panic recovered:\n%s\n%s\n%s%s",
-							timeFormat(time.Now()), headersToStr, err, stack, reset)
+							timeFormat(clock.Now()), headersToStr, err, stack, reset)
 					} else { // 其他情况
 						logger.Printf("[Recovery] %s panic recovered:\n%s\n%s%s",
-							timeFormat(time.Now()), err, stack, reset)
+							timeFormat(clock.Now()), err, stack, reset)
 					}
 				}
 				if brokenPipe { //　如果连接断开，记录Error，终止后续请求
@@ -105,6 +126,38 @@ func CustomRecoveryWithWriter(out io.Writer, handle RecoveryFunc) HandlerFunc {
 	}
 }
 
+// PanicError 由RecoveryAsError记录到Context中的panic
+type PanicError struct {
+	// recover()返回的值
+	Value any
+}
+
+// 实现了error接口
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// panic的值是error时返回该error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// 返回一个middleware，出现panic时将其作为ErrorTypePrivate的*Error（Err为*PanicError）记录到Context中并终止后续请求，
+// 响应交给外层的错误处理middleware（例如ErrorRenderer）渲染，没有写入响应时返回status code：500
+//
+//	router.Use(gin.ErrorRenderer(gin.ErrorRendererConfig{}), gin.RecoveryAsError())
+func RecoveryAsError() HandlerFunc {
+	return CustomRecovery(recoverAsError)
+}
+
+// 将panic记录为Context的错误
+func recoverAsError(c *Context, err any) {
+	c.Status(http.StatusInternalServerError)
+	c.Abort()
+	_ = c.Error(&Error{Err: &PanicError{Value: err}, Type: ErrorTypePrivate})
+}
+
 // 默认的RecoveryFunc，返回status code：500并终止后续请求
 func defaultHandleRecovery(c *Context, _ any) {
 	c.AbortWithStatus(http.StatusInternalServerError)
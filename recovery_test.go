@@ -12,6 +12,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -246,3 +247,57 @@ func TestRecoveryWithWriterWithCustomRecovery(t *testing.T) {
 
 	SetMode(TestMode)
 }
+
+func TestRecoveryAsError(t *testing.T) {
+	buffer := new(strings.Builder)
+	defaultErrorWriter := DefaultErrorWriter
+	DefaultErrorWriter = buffer
+	defer func() { DefaultErrorWriter = defaultErrorWriter }()
+
+	var recorded *Error
+	router := New()
+	router.Use(func(c *Context) {
+		c.Next()
+		recorded = c.Errors.Last()
+	}, RecoveryAsError())
+	router.GET("/recovery", func(_ *Context) {
+		panic(errPanicTest)
+	})
+
+	w := PerformRequest(router, "GET", "/recovery")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Contains(t, buffer.String(), "panic recovered")
+
+	var panicErr *PanicError
+	assert.True(t, recorded.IsType(ErrorTypePrivate))
+	assert.ErrorAs(t, recorded, &panicErr)
+	assert.ErrorIs(t, recorded, errPanicTest)
+	assert.Equal(t, "panic: oops", recorded.Error())
+
+	// 交给ErrorRenderer渲染
+	router = New()
+	router.Use(ErrorRenderer(ErrorRendererConfig{}), RecoveryAsError())
+	router.GET("/recovery", func(_ *Context) {
+		panic("oops")
+	})
+	w = PerformRequest(router, "GET", "/recovery")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"errors":[{"error":"Internal Server Error"}]}`, w.Body.String())
+}
+
+var errPanicTest = fmt.Errorf("oops")
+
+func TestRecoveryWithClock(t *testing.T) {
+	buffer := new(strings.Builder)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	router := New()
+	router.Use(RecoveryWithConfig(RecoveryConfig{Output: buffer, Clock: &fixedClock{times: []time.Time{now}}}))
+	router.GET("/recovery", func(_ *Context) {
+		panic("oops")
+	})
+
+	w := PerformRequest(router, "GET", "/recovery")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, strings.HasPrefix(buffer.String(), "\n\n\x1b[31m[Recovery] 2026/01/02 - 03:04:05 panic recovered:\n"), buffer.String())
+}
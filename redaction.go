@@ -0,0 +1,156 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin/internal/json"
+)
+
+// Redaction 日志和Recovery输出中需要隐藏的内容，名字不区分大小写
+type Redaction struct {
+	// 其他需要隐藏值的header名字，例如X-Api-Key；Authorization、Proxy-Authorization和Cookie总是被隐藏
+	Headers []string
+	// 需要隐藏值的query参数名字，例如token
+	QueryParams []string
+	// 需要隐藏值的JSON字段路径，使用.分隔，数组中的元素使用相同的路径，例如user.password、items.secret
+	JSONFields []string
+	// 替换后的值，默认为*
+	Mask string
+}
+
+// 总是隐藏的header，不受Redaction.Headers影响
+var alwaysRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// 返回替换后的值
+func (r Redaction) mask() string {
+	if r.Mask == "" {
+		return "*"
+	}
+	return r.Mask
+}
+
+// 判断name是否在names中，不区分大小写
+func redactionMatch(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// 隐藏httputil.DumpRequest输出中的header和请求行中的query参数
+func (r Redaction) RedactDump(dump []byte) []byte {
+	lines := strings.Split(string(dump), "\r\n")
+	for i, line := range lines {
+		// 第一行为请求行
+		if i == 0 {
+			if start := strings.IndexByte(line, '?'); start >= 0 {
+				end := strings.LastIndexByte(line, ' ')
+				if end < start {
+					end = len(line)
+				}
+				lines[i] = line[:start+1] + r.RedactQuery(line[start+1:end]) + line[end:]
+			}
+			continue
+		}
+		name, _, found := strings.Cut(line, ":")
+		if found && (redactionMatch(alwaysRedactedHeaders, name) || redactionMatch(r.Headers, name)) {
+			lines[i] = name + ": " + r.mask()
+		}
+	}
+	return []byte(strings.Join(lines, "\r\n"))
+}
+
+// 隐藏raw query中的参数值，保留其他参数的原始编码和顺序
+func (r Redaction) RedactQuery(rawQuery string) string {
+	if rawQuery == "" || len(r.QueryParams) == 0 {
+		return rawQuery
+	}
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(key); err == nil && redactionMatch(r.QueryParams, name) {
+			parts[i] = key + "=" + url.QueryEscape(r.mask())
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// 隐藏JSON中的字段值，data不是合法的JSON时原样返回
+func (r Redaction) RedactJSON(data []byte) []byte {
+	if redacted, ok := r.redactJSON(data); ok {
+		return redacted
+	}
+	return data
+}
+
+// 隐藏JSON中的字段值，data不是合法的JSON时返回false
+func (r Redaction) redactJSON(data []byte) ([]byte, bool) {
+	if len(r.JSONFields) == 0 {
+		return data, true
+	}
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, false
+	}
+	for _, field := range r.JSONFields {
+		redactJSONPath(v, strings.Split(field, "."), r.mask())
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+// 按照path隐藏v中的字段值
+func redactJSONPath(v any, path []string, mask string) {
+	switch val := v.(type) {
+	case []any:
+		for _, elem := range val {
+			redactJSONPath(elem, path, mask)
+		}
+	case map[string]any:
+		for key, elem := range val {
+			if !strings.EqualFold(key, path[0]) {
+				continue
+			}
+			if len(path) == 1 {
+				val[key] = mask
+				continue
+			}
+			redactJSONPath(elem, path[1:], mask)
+		}
+	}
+}
+
+// 隐藏Logger记录的请求body，Content-Type为JSON时隐藏JSONFields中的字段；
+// 配置了JSONFields时，被截断或者无法解析的JSON body无法隐藏字段，不记录
+func (r Redaction) redactBody(contentType string, body []byte, truncated bool) string {
+	if len(r.JSONFields) == 0 || !isJSONContentType(contentType) {
+		return string(body)
+	}
+	if truncated {
+		return ""
+	}
+	redacted, ok := r.redactJSON(body)
+	if !ok {
+		return ""
+	}
+	return string(redacted)
+}
+
+// 判断Content-Type是否为JSON，包括application/problem+json等+json后缀的类型
+func isJSONContentType(contentType string) bool {
+	contentType = strings.ToLower(filterFlags(contentType))
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
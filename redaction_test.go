@@ -0,0 +1,125 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactionDump(t *testing.T) {
+	r := Redaction{Headers: []string{"x-api-key"}, QueryParams: []string{"token"}}
+	dump := "GET /path?token=secret&a=1 HTTP/1.1\r\nHost: example.com\r\nAuthorization: Bearer secret\r\nCookie: session=abc\r\nX-Api-Key: key\r\n\r\n"
+	assert.Equal(t,
+		"GET /path?token=%2A&a=1 HTTP/1.1\r\nHost: example.com\r\nAuthorization: *\r\nCookie: *\r\nX-Api-Key: *\r\n\r\n",
+		string(r.RedactDump([]byte(dump))))
+
+	// 未配置Headers时仍然隐藏凭证
+	dump = "GET / HTTP/1.1\r\nProxy-Authorization: Basic secret\r\nAuthorization: Bearer secret\r\n\r\n"
+	assert.Equal(t, "GET / HTTP/1.1\r\nProxy-Authorization: *\r\nAuthorization: *\r\n\r\n",
+		string(Redaction{}.RedactDump([]byte(dump))))
+}
+
+func TestRedactionQuery(t *testing.T) {
+	r := Redaction{QueryParams: []string{"token", "api key"}, Mask: "[redacted]"}
+	assert.Equal(t, "a=1&token=%5Bredacted%5D&b=x%20y&api+key=%5Bredacted%5D",
+		r.RedactQuery("a=1&token=secret&b=x%20y&api+key=secret"))
+	assert.Equal(t, "a=1", Redaction{}.RedactQuery("a=1"))
+	assert.Equal(t, "", r.RedactQuery(""))
+}
+
+func TestRedactionJSON(t *testing.T) {
+	r := Redaction{JSONFields: []string{"password", "user.token", "items.secret"}}
+	data := `{"password":"p","user":{"name":"gin","token":"t"},"items":[{"secret":1,"id":12345678901234567890},{"id":2}]}`
+	assert.JSONEq(t,
+		`{"password":"*","user":{"name":"gin","token":"*"},"items":[{"secret":"*","id":12345678901234567890},{"id":2}]}`,
+		string(r.RedactJSON([]byte(data))))
+
+	assert.Equal(t, "not json", string(r.RedactJSON([]byte("not json"))))
+	assert.Equal(t, data, string(Redaction{}.RedactJSON([]byte(data))))
+}
+
+func TestRedactionBody(t *testing.T) {
+	r := Redaction{JSONFields: []string{"password"}, Mask: "[redacted]"}
+	assert.Equal(t, `{"password":"[redacted]"}`, r.redactBody("application/json; charset=utf-8", []byte(`{"password":"p"}`), false))
+	assert.Equal(t, `{"password":"[redacted]"}`, r.redactBody("application/merge-patch+json", []byte(`{"password":"p"}`), false))
+	// 无法隐藏字段的JSON body不记录
+	assert.Equal(t, "", r.redactBody("application/json", []byte(`{"password":"p`), true))
+	assert.Equal(t, "", r.redactBody("application/json", []byte(`{"password":`), false))
+	// 其他Content-Type以及没有配置JSONFields时原样记录
+	assert.Equal(t, "password=p", r.redactBody("application/x-www-form-urlencoded", []byte("password=p"), false))
+	assert.Equal(t, `{"password":"p`, Redaction{}.redactBody("application/json", []byte(`{"password":"p`), true))
+}
+
+func TestRedactionLoggerBody(t *testing.T) {
+	var logged []string
+	router := New()
+	router.Redaction.JSONFields = []string{"password", "cards.number"}
+	router.Use(LoggerWithConfig(LoggerConfig{
+		Output:          io.Discard,
+		RequestBodySize: 128,
+		Formatter: func(param LogFormatterParams) string {
+			logged = append(logged, param.RequestBody)
+			return ""
+		},
+	}))
+	router.POST("/login", func(c *Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	body := `{"user":"gin","password":"secret","cards":[{"number":"4111"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	// handler读取到完整的body
+	assert.Equal(t, body, w.Body.String())
+	assert.JSONEq(t, `{"user":"gin","password":"*","cards":[{"number":"*"}]}`, logged[0])
+
+	// 超过RequestBodySize的JSON body不记录，handler仍然读取到完整的body
+	body = `{"password":"` + strings.Repeat("s", 200) + `"}`
+	req = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, "", logged[1])
+
+	// 其他body截断记录
+	req = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(strings.Repeat("a", 200)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Len(t, w.Body.String(), 200)
+	assert.Equal(t, strings.Repeat("a", 128), logged[2])
+}
+
+func TestRedactionLoggerAndRecovery(t *testing.T) {
+	SetMode(DebugMode)
+	defer SetMode(TestMode)
+
+	querySecret, headerSecret := "query-secret", "header-secret"
+	buffer := new(strings.Builder)
+	router := New()
+	router.Redaction = Redaction{Headers: []string{"X-Api-Key"}, QueryParams: []string{"token"}}
+	router.Use(LoggerWithWriter(buffer), RecoveryWithWriter(buffer))
+	router.GET("/recovery", func(_ *Context) {
+		panic("oops")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/recovery?token="+querySecret+"&page=1",
+		header{Key: "X-Api-Key", Value: headerSecret}, header{Key: "Authorization", Value: "Bearer " + headerSecret})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, buffer.String(), querySecret)
+	assert.NotContains(t, buffer.String(), headerSecret)
+	assert.Contains(t, buffer.String(), "/recovery?token=%2A&page=1")
+	assert.Contains(t, buffer.String(), "X-Api-Key: *")
+	assert.Contains(t, buffer.String(), "Authorization: *")
+}
@@ -7,6 +7,9 @@ package render
 import (
 	"html/template"
 	"net/http"
+	"os"
+	"path/filepath"
+	"text/template/parse"
 )
 
 // 用于HTML模板渲染的左右分隔符
@@ -23,12 +26,22 @@ type HTMLRender interface {
 	Instance(string, any) Render
 }
 
+// HTMLFuncsRender 支持请求级别FuncMap的HTMLRender
+// funcs中的函数需要在解析模板前已在FuncMap中声明（可以是占位函数），渲染时会被覆盖
+type HTMLFuncsRender interface {
+	HTMLRender
+	// 返回使用funcs渲染的instance，不会修改共享的模板
+	InstanceWithFuncs(name string, data any, funcs template.FuncMap) Render
+}
+
 // HTMLProduction包含模板和对应的分割符
 type HTMLProduction struct {
 	// 模板指针
 	Template *template.Template
 	// 分隔符
 	Delims Delims
+	// 未执行过的模板，用于InstanceWithFuncs克隆，为空时克隆Template
+	Source *template.Template
 }
 
 // HTMLDebug包含模板分隔符、模式和文件列表
@@ -58,6 +71,15 @@ func (r HTMLProduction) Instance(name string, data any) Render {
 	}
 }
 
+// InstanceWithFuncs（HTMLProduction）克隆模板并使用funcs渲染
+func (r HTMLProduction) InstanceWithFuncs(name string, data any, funcs template.FuncMap) Render {
+	source := r.Source
+	if source == nil {
+		source = r.Template
+	}
+	return htmlWithFuncs(source, name, data, funcs)
+}
+
 // Instance (HTMLDebug) 返回HTML（实现了Render接口）
 func (r HTMLDebug) Instance(name string, data any) Render {
 	return HTML{
@@ -67,6 +89,15 @@ func (r HTMLDebug) Instance(name string, data any) Render {
 	}
 }
 
+// InstanceWithFuncs（HTMLDebug）重新加载模板并使用funcs渲染
+func (r HTMLDebug) InstanceWithFuncs(name string, data any, funcs template.FuncMap) Render {
+	return HTML{
+		Template: r.loadTemplate().Funcs(funcs),
+		Name:     name,
+		Data:     data,
+	}
+}
+
 // 加载模板
 func (r HTMLDebug) loadTemplate() *template.Template {
 	// FuncMap初始化
@@ -84,6 +115,180 @@ func (r HTMLDebug) loadTemplate() *template.Template {
 	panic("the HTML debug render was created without files or glob pattern")
 }
 
+// HTMLLayoutRender 支持layout的HTMLRender
+type HTMLLayoutRender interface {
+	HTMLRender
+	// 返回将page渲染到layout中的instance
+	InstanceWithLayout(layout, name string, data any) Render
+}
+
+// HTMLLayoutFuncsRender 支持请求级别FuncMap的HTMLLayoutRender
+type HTMLLayoutFuncsRender interface {
+	HTMLLayoutRender
+	// 返回将page渲染到layout中并使用funcs的instance，不会修改共享的模板
+	InstanceWithLayoutFuncs(layout, name string, data any, funcs template.FuncMap) Render
+}
+
+// HTMLLayout 包含layout、partial和page模板文件
+// 每个page与全部layout和partial组成独立的模板集合，page的正文会渲染到layout的Block中
+type HTMLLayout struct {
+	Layouts  []string
+	Partials []string
+	Pages    []string
+	// page正文对应的layout block名字（默认content）
+	Block   string
+	Delims  Delims
+	FuncMap template.FuncMap
+	// 每次渲染时重新加载模板
+	Debug bool
+
+	base  *template.Template
+	pages map[string]*template.Template
+	// 未执行过的模板，用于InstanceWithFuncs克隆
+	baseSource  *template.Template
+	pageSources map[string]*template.Template
+}
+
+// 默认的layout block名字
+const defaultLayoutBlock = "content"
+
+// 加载并解析全部模板
+func (r *HTMLLayout) Load() error {
+	base, pages, err := r.parse()
+	if err != nil {
+		return err
+	}
+	baseSource, pageSources, err := r.parse()
+	if err != nil {
+		return err
+	}
+	r.base, r.pages = base, pages
+	r.baseSource, r.pageSources = baseSource, pageSources
+	return nil
+}
+
+// 解析layout和partial，再为每个page生成独立的模板集合
+func (r *HTMLLayout) parse() (*template.Template, map[string]*template.Template, error) {
+	block := r.Block
+	if block == "" {
+		block = defaultLayoutBlock
+	}
+	base := template.New("").Delims(r.Delims.Left, r.Delims.Right).Funcs(r.FuncMap)
+	if files := append(append([]string{}, r.Layouts...), r.Partials...); len(files) > 0 {
+		if _, err := base.ParseFiles(files...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pages := make(map[string]*template.Template, len(r.Pages))
+	for _, file := range r.Pages {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		tmpl, err := base.Clone()
+		if err != nil {
+			return nil, nil, err
+		}
+		name := filepath.Base(file)
+		page, err := tmpl.New(name).Parse(string(content))
+		if err != nil {
+			return nil, nil, err
+		}
+		// page只包含define时由其自行覆盖block，否则将正文作为block
+		if page.Tree != nil && !parse.IsEmptyTree(page.Tree.Root) {
+			if _, err = tmpl.AddParseTree(block, page.Tree); err != nil {
+				return nil, nil, err
+			}
+		}
+		pages[name] = tmpl
+	}
+	return base, pages, nil
+}
+
+// 获取page对应的模板集合，不存在时返回base
+func (r *HTMLLayout) template(name string) *template.Template {
+	base, pages := r.base, r.pages
+	if r.Debug || pages == nil {
+		var err error
+		// 解析失败时与HTMLDebug一致直接panic
+		if base, pages, err = r.parse(); err != nil {
+			panic(err)
+		}
+	}
+	if tmpl, ok := pages[name]; ok {
+		return tmpl
+	}
+	return base
+}
+
+// 获取page对应的未执行过的模板集合
+func (r *HTMLLayout) source(name string) *template.Template {
+	if r.Debug || r.pageSources == nil {
+		return r.template(name)
+	}
+	if tmpl, ok := r.pageSources[name]; ok {
+		return tmpl
+	}
+	return r.baseSource
+}
+
+// Instance（HTMLLayout）单独渲染page或layout/partial
+func (r *HTMLLayout) Instance(name string, data any) Render {
+	return HTML{
+		Template: r.template(name),
+		Name:     name,
+		Data:     data,
+	}
+}
+
+// InstanceWithLayout（HTMLLayout）将page渲染到layout中
+func (r *HTMLLayout) InstanceWithLayout(layout, name string, data any) Render {
+	return HTML{
+		Template: r.template(name),
+		Name:     layout,
+		Data:     data,
+	}
+}
+
+// InstanceWithFuncs（HTMLLayout）单独渲染page并使用funcs
+func (r *HTMLLayout) InstanceWithFuncs(name string, data any, funcs template.FuncMap) Render {
+	return htmlWithFuncs(r.source(name), name, data, funcs)
+}
+
+// InstanceWithLayoutFuncs（HTMLLayout）将page渲染到layout中并使用funcs
+func (r *HTMLLayout) InstanceWithLayoutFuncs(layout, name string, data any, funcs template.FuncMap) Render {
+	return htmlWithFuncs(r.source(name), layout, data, funcs)
+}
+
+// 克隆未执行过的模板并设置funcs，克隆失败时返回的Render会返回该错误
+func htmlWithFuncs(source *template.Template, name string, data any, funcs template.FuncMap) Render {
+	tmpl, err := source.Clone()
+	if err != nil {
+		return htmlError{err: err}
+	}
+	return HTML{
+		Template: tmpl.Funcs(funcs),
+		Name:     name,
+		Data:     data,
+	}
+}
+
+// htmlError 在Render时返回err
+type htmlError struct {
+	err error
+}
+
+// Render 返回err
+func (r htmlError) Render(http.ResponseWriter) error {
+	return r.err
+}
+
+// WriteContentType设置HTML的Content-Type
+func (r htmlError) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, htmlContentType)
+}
+
 // Render echo HTML数据
 func (r HTML) Render(w http.ResponseWriter) error {
 	// 写入HTML的Content-Type头
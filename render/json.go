@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin/internal/bytesconv"
 	"github.com/gin-gonic/gin/internal/json"
@@ -48,6 +49,11 @@ type PureJSON struct {
 	Data any
 }
 
+// JSONInHTML（可嵌入HTML <script>标签的JSON）结构体
+type JSONInHTML struct {
+	Data any
+}
+
 var (
 	jsonContentType      = []string{"application/json; charset=utf-8"}
 	jsonpContentType     = []string{"application/javascript; charset=utf-8"}
@@ -213,3 +219,38 @@ func (r PureJSON) Render(w http.ResponseWriter) error {
 func (r PureJSON) WriteContentType(w http.ResponseWriter) {
 	writeContentType(w, jsonContentType)
 }
+
+// 转义JSON中在<script>标签内不安全的字符
+var jsonInHTMLEscaper = strings.NewReplacer(
+	"<", `\u003c`,
+	">", `\u003e`,
+	"&", `\u0026`,
+	"\u2028", `\u2028`,
+	"\u2029", `\u2029`,
+)
+
+// 将obj序列化为可嵌入<script>标签的JSON，可以注册到FuncMap中在模板内使用
+func MarshalJSONInHTML(obj any) (template.JS, error) {
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	// 不依赖json实现的默认行为，统一转义<, >, &, U+2028和U+2029
+	return template.JS(jsonInHTMLEscaper.Replace(bytesconv.BytesToString(jsonBytes))), nil
+}
+
+// Render JSONInHTML数据
+func (r JSONInHTML) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	js, err := MarshalJSONInHTML(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytesconv.StringToBytes(string(js)))
+	return err
+}
+
+// 将jsonContentType写入header的ContentType
+func (r JSONInHTML) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
@@ -20,11 +20,16 @@ var (
 // MsgPack 结构体
 type MsgPack struct {
 	Data any
+	// 编码使用的handle，为nil时使用默认的handle
+	Handle *codec.MsgpackHandle
 }
 
 // msgpack的ContentType
 var msgpackContentType = []string{"application/msgpack; charset=utf-8"}
 
+// 没有指定handle时使用的MsgpackHandle，配置完成的handle可以并发使用
+var defaultMsgPackHandle = new(codec.MsgpackHandle)
+
 // 将msgpackContentType写入header的ContentType
 func (r MsgPack) WriteContentType(w http.ResponseWriter) {
 	writeContentType(w, msgpackContentType)
@@ -32,14 +37,21 @@ func (r MsgPack) WriteContentType(w http.ResponseWriter) {
 
 // Render MsgPack数据
 func (r MsgPack) Render(w http.ResponseWriter) error {
-	return WriteMsgPack(w, r.Data)
+	return WriteMsgPackWithHandle(w, r.Data, r.Handle)
 }
 
 // 写入ContentType和MsgPack数据
 func WriteMsgPack(w http.ResponseWriter, obj any) error {
+	return WriteMsgPackWithHandle(w, obj, nil)
+}
+
+// 写入ContentType和使用h编码的MsgPack数据，h为nil时使用默认的handle；h在使用之后不能再修改
+func WriteMsgPackWithHandle(w http.ResponseWriter, obj any, h *codec.MsgpackHandle) error {
 	// 先将msgpackContentType写入header的ContentType
 	writeContentType(w, msgpackContentType)
-	var mh codec.MsgpackHandle
+	if h == nil {
+		h = defaultMsgPackHandle
+	}
 	// echo obj数据，Encode包含了w.Writer操作
-	return codec.NewEncoder(w, &mh).Encode(obj)
+	return codec.NewEncoder(w, h).Encode(obj)
 }
@@ -5,8 +5,10 @@
 package render
 
 import (
+	"errors"
 	"net/http"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -15,6 +17,14 @@ type ProtoBuf struct {
 	Data any
 }
 
+// ProtoJSON 结构体，使用protobuf规范的JSON映射渲染proto.Message，例如gRPC transcoding风格的接口
+type ProtoJSON struct {
+	Data any
+
+	// 序列化选项，零值使用protojson的默认行为
+	Options protojson.MarshalOptions
+}
+
 // protobuf的ContentType
 var protobufContentType = []string{"application/x-protobuf"}
 
@@ -38,3 +48,24 @@ func (r ProtoBuf) Render(w http.ResponseWriter) error {
 func (r ProtoBuf) WriteContentType(w http.ResponseWriter) {
 	writeContentType(w, protobufContentType)
 }
+
+// Render ProtoJSON数据
+func (r ProtoJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	msg, ok := r.Data.(proto.Message)
+	if !ok {
+		return errors.New("data is not ProtoMessage")
+	}
+	bytes, err := r.Options.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+// 将jsonContentType写入header的Content-Type
+func (r ProtoJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
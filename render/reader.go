@@ -8,37 +8,62 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // Reader 结构体
 type Reader struct {
 	// ContentType类型
 	ContentType string
-	// IO reader的长度
+	// IO reader的长度, 小于0表示长度未知, 使用chunked传输
 	ContentLength int64
 	// IO reader
 	Reader io.Reader
 	// 其他的headers
 	Headers map[string]string
+	// 刷新间隔, 0表示不主动刷新, 负数表示每次写入后立即刷新
+	FlushInterval time.Duration
+	// 写入完成后是否关闭Reader(需实现io.Closer)
+	CloseReader bool
 }
 
 // Render echo数据以及对应的Headers
 func (r Reader) Render(w http.ResponseWriter) (err error) {
+	if r.CloseReader {
+		if closer, ok := r.Reader.(io.Closer); ok {
+			defer func() {
+				if cerr := closer.Close(); err == nil {
+					err = cerr
+				}
+			}()
+		}
+	}
 	// 写入header的ContentType
 	r.WriteContentType(w)
-	// echo数据不为空
+	headers := make(map[string]string, len(r.Headers)+1)
+	for k, v := range r.Headers {
+		headers[k] = v
+	}
 	if r.ContentLength >= 0 {
-		// 设置默认的r.Headers
-		if r.Headers == nil {
-			r.Headers = map[string]string{}
-		}
 		// 写入header的ContentLength
-		r.Headers["Content-Length"] = strconv.FormatInt(r.ContentLength, 10)
+		headers["Content-Length"] = strconv.FormatInt(r.ContentLength, 10)
+	} else {
+		// 长度未知, 去掉Content-Length并使用chunked传输
+		delete(headers, "Content-Length")
+		w.Header().Del("Content-Length")
+		w.Header().Set("Transfer-Encoding", "chunked")
 	}
 	// 写入其他的header
-	r.writeHeaders(w, r.Headers)
+	r.writeHeaders(w, headers)
 	// 将reader数据流写入到writer数据流
-	_, err = io.Copy(w, r.Reader)
+	dst := io.Writer(w)
+	if flusher, ok := w.(http.Flusher); ok && r.FlushInterval != 0 {
+		dst = &flushWriter{w: w, flusher: flusher, interval: r.FlushInterval, last: time.Now()}
+	}
+	_, err = io.Copy(dst, r.Reader)
+	if f, ok := dst.(*flushWriter); ok && err == nil {
+		f.flusher.Flush()
+	}
 	return
 }
 
@@ -57,3 +82,24 @@ func (r Reader) writeHeaders(w http.ResponseWriter, headers map[string]string) {
 		}
 	}
 }
+
+// flushWriter 按间隔刷新的writer
+type flushWriter struct {
+	w        io.Writer
+	flusher  http.Flusher
+	interval time.Duration
+	last     time.Time
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	// 间隔为负数或者已超过间隔则刷新
+	if f.interval < 0 || time.Since(f.last) >= f.interval {
+		f.flusher.Flush()
+		f.last = time.Now()
+	}
+	return n, nil
+}
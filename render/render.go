@@ -26,11 +26,21 @@ var (
 	_ Render     = HTML{}
 	_ HTMLRender = HTMLDebug{}
 	_ HTMLRender = HTMLProduction{}
+	_ HTMLRender = HTMLCache{}
 	_ Render     = YAML{}
 	_ Render     = Reader{}
 	_ Render     = AsciiJSON{}
 	_ Render     = ProtoBuf{}
+	_ Render     = ProtoJSON{}
 	_ Render     = TOML{}
+	_ Render     = JSONInHTML{}
+
+	_ HTMLLayoutRender      = (*HTMLLayout)(nil)
+	_ HTMLLayoutFuncsRender = (*HTMLLayout)(nil)
+	_ HTMLFuncsRender       = HTMLProduction{}
+	_ HTMLFuncsRender       = HTMLDebug{}
+	_ HTMLFuncsRender       = (*HTMLLayout)(nil)
+	_ TemplateCache         = (*FileTemplateCache)(nil)
 )
 
 // 将value写入header的Content-Type字段中
@@ -24,10 +24,10 @@ func TestRenderMsgPack(t *testing.T) {
 		"foo": "bar",
 	}
 
-	(MsgPack{data}).WriteContentType(w)
+	(MsgPack{Data: data}).WriteContentType(w)
 	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
 
-	err := (MsgPack{data}).Render(w)
+	err := (MsgPack{Data: data}).Render(w)
 
 	assert.NoError(t, err)
 
@@ -41,3 +41,26 @@ func TestRenderMsgPack(t *testing.T) {
 	assert.Equal(t, w.Body.String(), buf.String())
 	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
 }
+
+func TestRenderMsgPackWithHandle(t *testing.T) {
+	data := map[string]any{"b": 2, "a": 1, "c": 3}
+	h := &codec.MsgpackHandle{}
+	h.Canonical = true
+
+	w := httptest.NewRecorder()
+	err := (MsgPack{Data: data, Handle: h}).Render(w)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = codec.NewEncoder(&buf, h).Encode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), w.Body.Bytes())
+	// Canonical编码时key按顺序排列
+	assert.Less(t, bytes.Index(w.Body.Bytes(), []byte("a")), bytes.Index(w.Body.Bytes(), []byte("b")))
+
+	w = httptest.NewRecorder()
+	err = WriteMsgPackWithHandle(w, data, h)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), w.Body.Bytes())
+	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
+}
@@ -8,16 +8,20 @@ import (
 	"encoding/xml"
 	"errors"
 	"html/template"
+	"io"
+	"io/fs"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/gin-gonic/gin/internal/json"
 	testdata "github.com/gin-gonic/gin/testdata/protoexample"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -247,6 +251,25 @@ func TestRenderPureJSON(t *testing.T) {
 	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestRenderJSONInHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	data := map[string]any{
+		"html": "</script><b>&",
+		"sep":  "a\u2028b\u2029c",
+	}
+	err := (JSONInHTML{data}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"html":"\u003c/script\u003e\u003cb\u003e\u0026","sep":"a\u2028b\u2029c"}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	js, err := MarshalJSONInHTML("<b>")
+	assert.NoError(t, err)
+	assert.Equal(t, template.JS(`"\u003cb\u003e"`), js)
+
+	_, err = MarshalJSONInHTML(make(chan int))
+	assert.Error(t, err)
+}
+
 type xmlmap map[string]any
 
 // Allows type H to be used with xml.Marshal
@@ -279,10 +302,10 @@ b:
 	c: 2
 	d: [3, 4]
 	`
-	(YAML{data}).WriteContentType(w)
+	(YAML{Data: data}).WriteContentType(w)
 	assert.Equal(t, "application/x-yaml; charset=utf-8", w.Header().Get("Content-Type"))
 
-	err := (YAML{data}).Render(w)
+	err := (YAML{Data: data}).Render(w)
 	assert.NoError(t, err)
 	assert.Equal(t, "|4-\n    a : Easy!\n    b:\n    \tc: 2\n    \td: [3, 4]\n    \t\n", w.Body.String())
 	assert.Equal(t, "application/x-yaml; charset=utf-8", w.Header().Get("Content-Type"))
@@ -297,7 +320,47 @@ func (ft *fail) MarshalYAML() (any, error) {
 
 func TestRenderYAMLFail(t *testing.T) {
 	w := httptest.NewRecorder()
-	err := (YAML{&fail{}}).Render(w)
+	err := (YAML{Data: &fail{}}).Render(w)
+	assert.Error(t, err)
+}
+
+func TestRenderYAMLOptions(t *testing.T) {
+	data := map[string]any{"a": map[string]int{"b": 1}}
+
+	w := httptest.NewRecorder()
+	err := (YAML{Data: data, Options: YAMLOptions{Indent: 2, ExplicitStart: true, ExplicitEnd: true}}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, "---\na:\n  b: 1\n...\n", w.Body.String())
+
+	// 不设置MultiDocument时slice编码为一个文档
+	w = httptest.NewRecorder()
+	err = (YAML{Data: []string{"x", "y"}}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, "- x\n- \"y\"\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	err = (YAML{Data: []any{map[string]int{"x": 1}, "y"}, Options: YAMLOptions{MultiDocument: true}}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, "x: 1\n---\n\"y\"\n", w.Body.String())
+}
+
+func TestRenderYAMLChannel(t *testing.T) {
+	docs := make(chan int)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			docs <- i
+		}
+		close(docs)
+	}()
+
+	w := httptest.NewRecorder()
+	err := (YAML{Data: docs, Options: YAMLOptions{MultiDocument: true}}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n---\n2\n---\n3\n", w.Body.String())
+	assert.True(t, w.Flushed)
+
+	w = httptest.NewRecorder()
+	err = (YAML{Data: (chan<- int)(make(chan int)), Options: YAMLOptions{MultiDocument: true}}).Render(w)
 	assert.Error(t, err)
 }
 
@@ -351,16 +414,44 @@ func TestRenderProtoBufFail(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestRenderProtoJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	label := "test"
+	data := &testdata.Test{
+		Label: &label,
+		Reps:  []int64{int64(1), int64(2)},
+	}
+
+	err := (ProtoJSON{Data: data}).Render(w)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"label":"test","reps":["1","2"]}`, w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	err = (ProtoJSON{Data: data, Options: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}}).Render(w)
+	assert.NoError(t, err)
+	assert.Contains(t, w.Body.String(), `"type"`)
+}
+
+func TestRenderProtoJSONFail(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := (ProtoJSON{Data: map[string]string{}}).Render(w)
+	assert.Error(t, err)
+
+	err = (ProtoJSON{Data: &testdata.Test{}}).Render(w)
+	assert.Error(t, err)
+}
+
 func TestRenderXML(t *testing.T) {
 	w := httptest.NewRecorder()
 	data := xmlmap{
 		"foo": "bar",
 	}
 
-	(XML{data}).WriteContentType(w)
+	(XML{Data: data}).WriteContentType(w)
 	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
 
-	err := (XML{data}).Render(w)
+	err := (XML{Data: data}).Render(w)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "<map><foo>bar</foo></map>", w.Body.String())
@@ -575,10 +666,50 @@ func TestRenderReaderNoContentLength(t *testing.T) {
 	assert.Equal(t, body, w.Body.String())
 	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
 	assert.NotContains(t, "Content-Length", w.Header())
+	assert.Equal(t, "chunked", w.Header().Get("Transfer-Encoding"))
 	assert.Equal(t, headers["Content-Disposition"], w.Header().Get("Content-Disposition"))
 	assert.Equal(t, headers["x-request-id"], w.Header().Get("x-request-id"))
 }
 
+type closeReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestRenderReaderStreaming(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Length", "100")
+
+	body := "streamed data"
+	reader := &closeReader{Reader: strings.NewReader(body)}
+	err := (Reader{
+		ContentLength: -1,
+		ContentType:   "text/plain",
+		Reader:        reader,
+		Headers:       map[string]string{"Content-Length": "5"},
+		FlushInterval: -1,
+		CloseReader:   true,
+	}).Render(w)
+
+	assert.NoError(t, err)
+	assert.Equal(t, body, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.Equal(t, "chunked", w.Header().Get("Transfer-Encoding"))
+	assert.True(t, w.Flushed)
+	assert.True(t, reader.closed)
+
+	// 未设置CloseReader时不关闭
+	reader = &closeReader{Reader: strings.NewReader(body)}
+	err = (Reader{ContentLength: int64(len(body)), Reader: reader}).Render(httptest.NewRecorder())
+	assert.NoError(t, err)
+	assert.False(t, reader.closed)
+}
+
 func TestRenderWriteError(t *testing.T) {
 	data := []interface{}{"value1", "value2"}
 	prefix := "my-prefix:"
@@ -591,3 +722,93 @@ func TestRenderWriteError(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, `write "my-prefix:" error`, err.Error())
 }
+
+func TestRenderHTMLCache(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pages/index.tmpl": {Data: []byte(`<h1>{{.}}</h1>{{template "footer.tmpl"}}`)},
+		"pages/about.tmpl": {Data: []byte(`<p>about</p>`)},
+		"footer.tmpl":      {Data: []byte(`<footer></footer>`)},
+	}
+	cache := &FileTemplateCache{FS: fsys, Partials: []string{"footer.tmpl"}, MaxEntries: 1}
+	htmlRender := HTMLCache{Cache: cache}
+
+	_, ok := cache.Get("pages/index.tmpl")
+	assert.False(t, ok)
+
+	w := httptest.NewRecorder()
+	err := htmlRender.Instance("pages/index.tmpl", "gin").Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, "<h1>gin</h1><footer></footer>", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	_, ok = cache.Get("pages/index.tmpl")
+	assert.True(t, ok)
+
+	// 超过MaxEntries时淘汰最久未使用的模板
+	w = httptest.NewRecorder()
+	assert.NoError(t, htmlRender.Instance("pages/about.tmpl", nil).Render(w))
+	assert.Equal(t, "<p>about</p>", w.Body.String())
+	assert.Equal(t, 1, cache.Len())
+	_, ok = cache.Get("pages/index.tmpl")
+	assert.False(t, ok)
+
+	// 失效后重新编译修改过的文件
+	fsys["pages/about.tmpl"] = &fstest.MapFile{Data: []byte(`<p>changed</p>`)}
+	cache.Invalidate("pages/about.tmpl")
+	assert.Equal(t, 0, cache.Len())
+	w = httptest.NewRecorder()
+	assert.NoError(t, htmlRender.Instance("pages/about.tmpl", nil).Render(w))
+	assert.Equal(t, "<p>changed</p>", w.Body.String())
+
+	err = htmlRender.Instance("pages/missing.tmpl", nil).Render(httptest.NewRecorder())
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestRenderXMLOptions(t *testing.T) {
+	type user struct {
+		XMLName xml.Name `xml:"urn:test user"`
+		Name    string   `xml:"name"`
+	}
+	type plain struct {
+		Name string `xml:"name"`
+	}
+
+	tests := []struct {
+		data any
+		opts XMLOptions
+		want string
+	}{
+		{
+			data: xmlmap{"b": "2", "a": "1"},
+			opts: XMLOptions{RootName: "response"},
+			want: "<response><a>1</a><b>2</b></response>",
+		},
+		{
+			data: map[string]any{"a": 1},
+			opts: XMLOptions{Header: true, Indent: "  ", Namespaces: map[string]string{"": "urn:default", "x": "urn:x"}},
+			want: xml.Header + "<map xmlns=\"urn:default\" xmlns:x=\"urn:x\">\n  <a>1</a>\n</map>",
+		},
+		{
+			data: &user{Name: "gin"},
+			opts: XMLOptions{Namespaces: map[string]string{"x": "urn:x"}},
+			want: `<user xmlns="urn:test" xmlns:x="urn:x"><name>gin</name></user>`,
+		},
+		{
+			data: plain{Name: "gin"},
+			opts: XMLOptions{RootName: "person", Indent: "\t"},
+			want: "<person>\n\t<name>gin</name>\n</person>",
+		},
+		{
+			data: plain{Name: "gin"},
+			opts: XMLOptions{Header: true},
+			want: xml.Header + "<plain><name>gin</name></plain>",
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		err := (XML{Data: tt.data, Options: tt.opts}).Render(w)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, w.Body.String())
+		assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"container/list"
+	"html/template"
+	"io/fs"
+	"sync"
+)
+
+// TemplateCache 编译后模板的存储，可以按需编译、限制内存并单独失效
+type TemplateCache interface {
+	// 获取已缓存的模板
+	Get(name string) (*template.Template, bool)
+	// 编译name对应的模板并缓存
+	Parse(name string) (*template.Template, error)
+	// 使name对应的模板失效，下次使用时重新编译
+	Invalidate(name string)
+}
+
+// HTMLCache 从TemplateCache获取模板的HTMLRender
+type HTMLCache struct {
+	Cache TemplateCache
+}
+
+// Instance（HTMLCache）获取或编译name对应的模板
+func (r HTMLCache) Instance(name string, data any) Render {
+	tmpl, ok := r.Cache.Get(name)
+	if !ok {
+		var err error
+		if tmpl, err = r.Cache.Parse(name); err != nil {
+			return htmlError{err: err}
+		}
+	}
+	return HTML{
+		Template: tmpl,
+		Name:     name,
+		Data:     data,
+	}
+}
+
+// FileTemplateCache 从fs.FS中按需编译模板的TemplateCache
+// 每个模板以文件路径命名，并与Partials一起编译；MaxEntries大于0时按LRU淘汰
+type FileTemplateCache struct {
+	FS fs.FS
+	// 每个模板都会包含的partial文件（fs.Glob模式）
+	Partials   []string
+	Delims     Delims
+	FuncMap    template.FuncMap
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// 缓存项
+type templateEntry struct {
+	name string
+	tmpl *template.Template
+}
+
+// Get 获取已缓存的模板
+func (c *FileTemplateCache) Get(name string) (*template.Template, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[name]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*templateEntry).tmpl, true
+}
+
+// Parse 编译name对应的模板并缓存
+func (c *FileTemplateCache) Parse(name string) (*template.Template, error) {
+	content, err := fs.ReadFile(c.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Delims(c.Delims.Left, c.Delims.Right).Funcs(c.FuncMap).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Partials) > 0 {
+		if tmpl, err = tmpl.ParseFS(c.FS, c.Partials...); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.lru = list.New()
+	}
+	if elem, ok := c.entries[name]; ok {
+		elem.Value.(*templateEntry).tmpl = tmpl
+		c.lru.MoveToFront(elem)
+		return tmpl, nil
+	}
+	c.entries[name] = c.lru.PushFront(&templateEntry{name: name, tmpl: tmpl})
+	// 超过MaxEntries时淘汰最久未使用的模板
+	for c.MaxEntries > 0 && c.lru.Len() > c.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*templateEntry).name)
+	}
+	return tmpl, nil
+}
+
+// Invalidate 使name对应的模板失效
+func (c *FileTemplateCache) Invalidate(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, name)
+	}
+}
+
+// Len 返回已缓存的模板数量
+func (c *FileTemplateCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -6,23 +6,137 @@ package render
 
 import (
 	"encoding/xml"
+	"io"
 	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // XML 结构体
 type XML struct {
 	Data any
+	// 编码选项，零值时与encoding/xml的默认行为相同
+	Options XMLOptions
+}
+
+// XMLOptions XML的编码选项
+type XMLOptions struct {
+	// 缩进的前缀和缩进字符串，都为空时不缩进
+	Prefix string
+	Indent string
+
+	// 根元素的名字，为空时使用encoding/xml的规则（XMLName或者类型名，map为"map"）
+	RootName string
+
+	// 为true时在开头写入xml.Header
+	Header bool
+
+	// 根元素声明的namespace，key为前缀，空字符串表示默认namespace（xmlns）
+	Namespaces map[string]string
 }
 
 // xml的ContentType
 var xmlContentType = []string{"application/xml; charset=utf-8"}
 
+// xml.Name的类型
+var xmlNameType = reflect.TypeOf(xml.Name{})
+
 // Render XML数据
 func (r XML) Render(w http.ResponseWriter) error {
 	// 先将protobufContentType写入header的ContentType
 	r.WriteContentType(w)
+	opts := r.Options
+	if opts.Header {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+	}
 	// 新建一个xml的encoder，encode过程中会调用w.Write进行echo数据
-	return xml.NewEncoder(w).Encode(r.Data)
+	enc := xml.NewEncoder(w)
+	if opts.Prefix != "" || opts.Indent != "" {
+		enc.Indent(opts.Prefix, opts.Indent)
+	}
+	if opts.RootName == "" && len(opts.Namespaces) == 0 {
+		return enc.Encode(r.Data)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: opts.RootName}}
+	if start.Name.Local == "" {
+		start.Name = xmlRootName(r.Data)
+	}
+	prefixes := make([]string, 0, len(opts.Namespaces))
+	for prefix := range opts.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		name := "xmlns"
+		if prefix != "" {
+			name += ":" + prefix
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: opts.Namespaces[prefix]})
+	}
+
+	// map（例如gin.H）的MarshalXML固定使用"map"作为根元素，按key的顺序单独编码
+	if v := reflect.ValueOf(r.Data); v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		return encodeXMLMap(enc, v, start)
+	}
+	return enc.EncodeElement(r.Data, start)
+}
+
+// 将key为string的map编码为start元素，每个key为一个子元素
+func encodeXMLMap(enc *xml.Encoder, v reflect.Value, start xml.StartElement) error {
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+		if err := enc.EncodeElement(value.Interface(), xml.StartElement{Name: xml.Name{Local: key}}); err != nil {
+			return err
+		}
+	}
+	if err := enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// 按照encoding/xml的规则返回根元素的名字：XMLName字段的tag或者值，map为"map"，否则为类型名
+func xmlRootName(data any) xml.Name {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return xml.Name{}
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return xml.Name{}
+	case reflect.Map:
+		return xml.Name{Local: "map"}
+	case reflect.Struct:
+		if f, ok := v.Type().FieldByName("XMLName"); ok && f.Type == xmlNameType {
+			tag, _, _ := strings.Cut(f.Tag.Get("xml"), ",")
+			if i := strings.LastIndexByte(tag, ' '); i >= 0 {
+				return xml.Name{Space: tag[:i], Local: tag[i+1:]}
+			}
+			if tag != "" {
+				return xml.Name{Local: tag}
+			}
+			if name := v.FieldByIndex(f.Index).Interface().(xml.Name); name.Local != "" {
+				return name
+			}
+		}
+	}
+	return xml.Name{Local: v.Type().Name()}
 }
 
 // 将protobufContentType写入header的ContentType
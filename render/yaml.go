@@ -5,7 +5,10 @@
 package render
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"reflect"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,6 +16,24 @@ import (
 // YAML 结构体
 type YAML struct {
 	Data any
+	// 编码选项，零值时与yaml.Marshal的输出相同
+	Options YAMLOptions
+}
+
+// YAMLOptions YAML的编码选项
+type YAMLOptions struct {
+	// 缩进的空格数，为0时使用yaml的默认值4
+	Indent int
+
+	// 为true时，Data为slice、array或者channel时每个元素编码为一个文档，文档之间使用"---"分隔；
+	// channel在关闭之前持续编码，每个文档编码之后刷新响应
+	MultiDocument bool
+
+	// 为true时在第一个文档之前写入"---"
+	ExplicitStart bool
+
+	// 为true时在最后一个文档之后写入"..."
+	ExplicitEnd bool
 }
 
 // yaml的ContentType
@@ -23,15 +44,65 @@ func (r YAML) Render(w http.ResponseWriter) error {
 	// 先将yamlContentType写入header的Content-Type
 	r.WriteContentType(w)
 
-	// r.Data进行yml.Marshal转义
-	bytes, err := yaml.Marshal(r.Data)
-	if err != nil {
+	opts := r.Options
+	if opts.ExplicitStart {
+		if _, err := io.WriteString(w, "---\n"); err != nil {
+			return err
+		}
+	}
+
+	// 直接编码到w，多个文档之间由encoder写入"---"
+	enc := yaml.NewEncoder(w)
+	if opts.Indent > 0 {
+		enc.SetIndent(opts.Indent)
+	}
+	if err := r.encode(enc, w); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	if opts.ExplicitEnd {
+		_, err := io.WriteString(w, "...\n")
 		return err
 	}
+	return nil
+}
 
-	// 写入bytes数据
-	_, err = w.Write(bytes)
-	return err
+// 编码Data，MultiDocument为true时按元素编码为多个文档
+func (r YAML) encode(enc *yaml.Encoder, w http.ResponseWriter) error {
+	v := reflect.ValueOf(r.Data)
+	if !r.Options.MultiDocument || !v.IsValid() {
+		return enc.Encode(r.Data)
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.Encode(v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 {
+			return errors.New("render: cannot receive YAML documents from send-only channel")
+		}
+		flusher, _ := w.(http.Flusher)
+		for {
+			doc, ok := v.Recv()
+			if !ok {
+				return nil
+			}
+			if err := enc.Encode(doc.Interface()); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	return enc.Encode(r.Data)
 }
 
 // 将yamlContentType写入header的Content-Type
@@ -0,0 +1,150 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认读取的超时header
+var defaultRequestDeadlineHeaders = []string{"X-Request-Timeout", "Grpc-Timeout"}
+
+// RequestDeadlineConfig 根据调用方传递的超时header设置请求deadline的配置
+type RequestDeadlineConfig struct {
+	// 读取超时的header，使用第一个存在并且合法的值，默认为X-Request-Timeout和Grpc-Timeout；
+	// Grpc-Timeout的值为grpc-timeout的格式（"100m"表示100毫秒），
+	// 其他header的值为time.ParseDuration的格式（"1.5s"、"300ms"）或者秒数（"2.5"）
+	Headers []string
+
+	// 大于0时限制header中的超时不超过该值
+	Max time.Duration
+
+	// 大于0时，没有超时header或者调用方不受信任的请求使用该超时
+	Default time.Duration
+
+	// 返回是否信任调用方传递的超时，默认RemoteIP为Engine的受信任代理时信任；
+	// Engine信任全部代理时（默认配置）不信任任何调用方，需要调用SetTrustedProxies或者设置Trusted
+	Trusted func(c *Context) bool
+}
+
+// 返回使用默认配置的请求deadline middleware
+func RequestDeadline() HandlerFunc {
+	return RequestDeadlineWithConfig(RequestDeadlineConfig{})
+}
+
+// 通过配置返回请求deadline middleware：根据受信任的调用方传递的超时为c.Request设置deadline，
+// handler通过c.Request.Context()感知超时并向下游传递剩余时间；
+// handler返回时已经超时并且还没有写入响应时返回504，handler不会被中断
+func RequestDeadlineWithConfig(conf RequestDeadlineConfig) HandlerFunc {
+	headers := conf.Headers
+	if len(headers) == 0 {
+		headers = defaultRequestDeadlineHeaders
+	}
+	trusted := conf.Trusted
+	if trusted == nil {
+		trusted = func(c *Context) bool {
+			// 没有Engine时无法判断受信任的代理，不信任调用方
+			if c.engine == nil {
+				return false
+			}
+			ip := net.ParseIP(c.RemoteIP())
+			return ip != nil && c.engine.isTrustedProxy(ip) && !c.engine.isUnsafeTrustedProxies()
+		}
+	}
+
+	return func(c *Context) {
+		timeout, ok := conf.Default, conf.Default > 0
+		if trusted(c) {
+			for _, header := range headers {
+				if d, valid := parseRequestTimeout(header, c.GetHeader(header)); valid {
+					timeout, ok = d, true
+					break
+				}
+			}
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+		if conf.Max > 0 && timeout > conf.Max {
+			timeout = conf.Max
+		}
+		if timeout <= 0 {
+			c.AbortWithStatus(http.StatusGatewayTimeout)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			c.AbortWithStatus(http.StatusGatewayTimeout)
+		}
+	}
+}
+
+// 解析超时header的值，Grpc-Timeout使用grpc-timeout的格式，其他header使用time.ParseDuration的格式或者秒数
+func parseRequestTimeout(header, value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if strings.EqualFold(header, "Grpc-Timeout") {
+		return parseGRPCTimeout(value)
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return 0, false
+		}
+		return d, true
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0, false
+	}
+	if seconds > float64(math.MaxInt64)/float64(time.Second) {
+		return math.MaxInt64, true
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// 解析grpc-timeout格式的超时：最多8位数字和一个单位（H、M、S、m、u、n）
+func parseGRPCTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch value[len(value)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(value[:len(value)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if n > uint64(math.MaxInt64/unit) {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRequestTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		header, value string
+		want          time.Duration
+		ok            bool
+	}{
+		{"X-Request-Timeout", "1.5s", 1500 * time.Millisecond, true},
+		{"X-Request-Timeout", "300ms", 300 * time.Millisecond, true},
+		{"X-Request-Timeout", "1m", time.Minute, true},
+		{"X-Request-Timeout", "2.5", 2500 * time.Millisecond, true},
+		{"X-Request-Timeout", "0", 0, true},
+		{"X-Request-Timeout", "1e300", math.MaxInt64, true},
+		{"X-Request-Timeout", "2S", 0, false},
+		{"X-Request-Timeout", "", 0, false},
+		{"X-Request-Timeout", "-1s", 0, false},
+		{"X-Request-Timeout", "-1", 0, false},
+		{"X-Request-Timeout", "NaN", 0, false},
+		{"X-Request-Timeout", "soon", 0, false},
+		{"Grpc-Timeout", "100m", 100 * time.Millisecond, true},
+		{"grpc-timeout", "2S", 2 * time.Second, true},
+		{"Grpc-Timeout", "1H", time.Hour, true},
+		{"Grpc-Timeout", "5M", 5 * time.Minute, true},
+		{"Grpc-Timeout", "10u", 10 * time.Microsecond, true},
+		{"Grpc-Timeout", "10n", 10, true},
+		{"Grpc-Timeout", "99999999H", math.MaxInt64, true},
+		{"Grpc-Timeout", "1.5s", 0, false},
+		{"Grpc-Timeout", "2.5", 0, false},
+	} {
+		got, ok := parseRequestTimeout(tc.header, tc.value)
+		assert.Equal(t, tc.ok, ok, tc.header+": "+tc.value)
+		assert.Equal(t, tc.want, got, tc.header+": "+tc.value)
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	router := New()
+	// PerformRequest的RemoteAddr为192.0.2.1
+	assert.NoError(t, router.SetTrustedProxies([]string{"192.0.2.1"}))
+	router.Use(RequestDeadline())
+	router.GET("/", func(c *Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if !ok {
+			c.String(http.StatusOK, "none")
+			return
+		}
+		c.String(http.StatusOK, "%v", time.Until(deadline) <= time.Second)
+	})
+	router.GET("/slow", func(c *Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/written", func(c *Context) {
+		<-c.Request.Context().Done()
+		c.String(http.StatusServiceUnavailable, "gave up")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, "none", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "1s"})
+	assert.Equal(t, "true", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "Grpc-Timeout", Value: "500m"})
+	assert.Equal(t, "true", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/slow", header{Key: "X-Request-Timeout", Value: "5ms"})
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/written", header{Key: "X-Request-Timeout", Value: "5ms"})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "gave up", w.Body.String())
+
+	// 调用方的预算已经用完
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "0"})
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
+
+func TestRequestDeadlineTrustAllProxies(t *testing.T) {
+	// 默认信任全部代理时不使用调用方的超时
+	router := New()
+	router.Use(RequestDeadline())
+	router.GET("/", func(c *Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%v", ok)
+	})
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "1s"})
+	assert.Equal(t, "false", w.Body.String())
+}
+
+func TestRequestDeadlineWithoutEngine(t *testing.T) {
+	// 没有Engine时不信任调用方的超时
+	w := httptest.NewRecorder()
+	c := createContextWithoutEngine(w)
+	c.Request.Header.Set("X-Request-Timeout", "0")
+	RequestDeadline()(c)
+	assert.False(t, c.IsAborted())
+	_, ok := c.Request.Context().Deadline()
+	assert.False(t, ok)
+}
+
+func TestRequestDeadlineUntrusted(t *testing.T) {
+	router := New()
+	assert.NoError(t, router.SetTrustedProxies([]string{"10.0.0.1"}))
+	router.Use(RequestDeadlineWithConfig(RequestDeadlineConfig{Default: time.Hour}))
+	router.GET("/", func(c *Context) {
+		deadline, _ := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%v", time.Until(deadline) > time.Minute)
+	})
+
+	// PerformRequest的RemoteAddr为192.0.2.1，不受信任时使用Default
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "1s"})
+	assert.Equal(t, "true", w.Body.String())
+}
+
+func TestRequestDeadlineWithConfig(t *testing.T) {
+	router := New()
+	router.Use(RequestDeadlineWithConfig(RequestDeadlineConfig{
+		Headers: []string{"X-Budget"},
+		Max:     time.Second,
+		Trusted: func(c *Context) bool { return true },
+	}))
+	router.GET("/", func(c *Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%v %v", ok, ok && time.Until(deadline) <= time.Second)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Budget", Value: "1h"})
+	assert.Equal(t, "true true", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "1s"})
+	assert.Equal(t, "false false", w.Body.String())
+}
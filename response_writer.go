@@ -42,6 +42,12 @@ type ResponseWriter interface {
 
 	// 返回http.Pusher
 	Pusher() http.Pusher
+
+	// 注册在第一次写入body之前（header已经写入）调用的函数，按注册顺序调用，例如记录首字节时间
+	OnFirstWrite(func())
+
+	// 注册每次写入body之后调用的函数，n为写入的字节数，按注册顺序调用，例如统计进度或者限速
+	OnWrite(func(n int))
 }
 
 // 封装的responseWriter结构体
@@ -52,6 +58,21 @@ type responseWriter struct {
 	size int
 	// 返回的status code
 	status int
+	// 写入header时添加的默认header，由Engine.DefaultHeaders设置
+	defaultHeaders http.Header
+	// 通过OnFirstWrite和OnWrite注册的函数
+	onFirstWrite []func()
+	onWrite      []func(n int)
+	// 是否已经写入过body
+	bodyWritten bool
+	// 写入header之前通过Context.Push暂存的push
+	pushes []pendingPush
+}
+
+// 暂存的HTTP/2 push
+type pendingPush struct {
+	target string
+	opts   *http.PushOptions
 }
 
 // 接口实现校验
@@ -67,6 +88,10 @@ func (w *responseWriter) reset(writer http.ResponseWriter) {
 	w.ResponseWriter = writer
 	w.size = noWritten
 	w.status = defaultStatus
+	w.onFirstWrite = nil
+	w.onWrite = nil
+	w.bodyWritten = false
+	w.pushes = nil
 }
 
 // 写入http header，code发生改变会重写header中的status code
@@ -89,17 +114,53 @@ func (w *responseWriter) WriteHeaderNow() {
 	// TODO：只有Written未完成时需要强制重写
 	if !w.Written() {
 		w.size = 0
+		w.flushPushes()
+		w.writeDefaultHeaders()
 		w.ResponseWriter.WriteHeader(w.status)
 	}
 }
 
+// 在写入header之前发送暂存的push，push失败时忽略
+func (w *responseWriter) flushPushes() {
+	if len(w.pushes) == 0 {
+		return
+	}
+	pushes := w.pushes
+	w.pushes = nil
+	pusher := w.Pusher()
+	if pusher == nil {
+		return
+	}
+	for _, p := range pushes {
+		if err := pusher.Push(p.target, p.opts); err != nil {
+			debugPrint("[WARNING] Failed to push %s: %v", p.target, err)
+		}
+	}
+}
+
+// 添加默认header，已经设置的header不会被覆盖
+func (w *responseWriter) writeDefaultHeaders() {
+	if len(w.defaultHeaders) == 0 {
+		return
+	}
+	header := w.ResponseWriter.Header()
+	for key, value := range w.defaultHeaders {
+		if _, ok := header[key]; !ok {
+			// 复制slice，避免响应修改header时影响其他响应
+			header[key] = append([]string(nil), value...)
+		}
+	}
+}
+
 // 重写http.ResponseWriter
 func (w *responseWriter) Write(data []byte) (n int, err error) {
 	// 写入header
 	w.WriteHeaderNow()
+	w.beforeWrite(len(data))
 	// 写入[]byte数据，并记录写入数据量
 	n, err = w.ResponseWriter.Write(data)
 	w.size += n
+	w.afterWrite(n)
 	return
 }
 
@@ -107,12 +168,80 @@ func (w *responseWriter) Write(data []byte) (n int, err error) {
 func (w *responseWriter) WriteString(s string) (n int, err error) {
 	// 写入header
 	w.WriteHeaderNow()
+	w.beforeWrite(len(s))
 	// 写入string数据，并记录写入数据量
 	n, err = io.WriteString(w.ResponseWriter, s)
 	w.size += n
+	w.afterWrite(n)
 	return
 }
 
+// 实现io.ReaderFrom接口，底层的writer实现io.ReaderFrom时委托给底层的writer，
+// 例如net/http可以使用sendfile直接发送文件；注册了OnWrite时逐块写入以便每次写入都能被观察
+func (w *responseWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	w.WriteHeaderNow()
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if !ok || len(w.onWrite) > 0 {
+		// 隐藏ReadFrom，避免io.Copy递归调用
+		return io.Copy(writerOnly{w}, r)
+	}
+	if !w.bodyWritten && len(w.onFirstWrite) > 0 {
+		// 读取到数据之后、写入之前调用OnFirstWrite注册的函数，r为空时不调用
+		r = &firstReadHook{Reader: r, w: w}
+	}
+	n, err = rf.ReadFrom(r)
+	w.size += int(n)
+	return
+}
+
+// 第一次读取到数据时调用responseWriter.beforeWrite的reader
+type firstReadHook struct {
+	io.Reader
+	w *responseWriter
+}
+
+func (r *firstReadHook) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.w.beforeWrite(n)
+	return n, err
+}
+
+// 只实现io.Writer的writer
+type writerOnly struct {
+	io.Writer
+}
+
+// 实现ResponseWriter OnFirstWrite函数接口
+func (w *responseWriter) OnFirstWrite(f func()) {
+	w.onFirstWrite = append(w.onFirstWrite, f)
+}
+
+// 实现ResponseWriter OnWrite函数接口
+func (w *responseWriter) OnWrite(f func(n int)) {
+	w.onWrite = append(w.onWrite, f)
+}
+
+// 第一次写入非空的body之前调用OnFirstWrite注册的函数
+func (w *responseWriter) beforeWrite(size int) {
+	if w.bodyWritten || size == 0 {
+		return
+	}
+	w.bodyWritten = true
+	for _, f := range w.onFirstWrite {
+		f()
+	}
+}
+
+// 写入body之后调用OnWrite注册的函数
+func (w *responseWriter) afterWrite(n int) {
+	if n == 0 {
+		return
+	}
+	for _, f := range w.onWrite {
+		f(n)
+	}
+}
+
 // 实现ResponseWriter Status函数接口
 func (w *responseWriter) Status() int {
 	return w.status
@@ -5,8 +5,11 @@
 package gin
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -65,6 +68,138 @@ func TestResponseWriterWriteHeader(t *testing.T) {
 	assert.Equal(t, http.StatusMultipleChoices, w.Status())
 }
 
+func TestResponseWriterDefaultHeaders(t *testing.T) {
+	testWriter := httptest.NewRecorder()
+	writer := &responseWriter{}
+	writer.reset(testWriter)
+	writer.defaultHeaders = http.Header{"Server": {"gin"}, "X-Content-Type-Options": {"nosniff"}}
+	w := ResponseWriter(writer)
+
+	w.Header().Set("Server", "custom")
+	w.WriteHeader(http.StatusNoContent)
+	assert.Empty(t, testWriter.Header().Get("X-Content-Type-Options"))
+
+	w.WriteHeaderNow()
+	assert.Equal(t, "custom", testWriter.Header().Get("Server"))
+	assert.Equal(t, "nosniff", testWriter.Header().Get("X-Content-Type-Options"))
+
+	// 修改响应的header不影响默认header
+	testWriter.Header()["X-Content-Type-Options"][0] = "changed"
+	assert.Equal(t, "nosniff", writer.defaultHeaders.Get("X-Content-Type-Options"))
+}
+
+func TestResponseWriterWriteHooks(t *testing.T) {
+	testWriter := httptest.NewRecorder()
+	writer := &responseWriter{}
+	writer.reset(testWriter)
+	w := ResponseWriter(writer)
+
+	var events []string
+	w.OnFirstWrite(func() {
+		events = append(events, fmt.Sprintf("first:%d:%d", testWriter.Body.Len(), testWriter.Code))
+	})
+	w.OnWrite(func(n int) {
+		events = append(events, fmt.Sprintf("write:%d", n))
+	})
+	w.OnWrite(func(n int) {
+		events = append(events, fmt.Sprintf("size:%d", w.Size()))
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+	w.WriteHeaderNow()
+	w.Flush()
+	_, _ = w.Write(nil)
+	assert.Empty(t, events)
+
+	_, _ = w.Write([]byte("hola"))
+	_, _ = w.WriteString("gin")
+	assert.Equal(t, []string{"first:0:202", "write:4", "size:4", "write:3", "size:7"}, events)
+
+	// reset之后清除注册的函数
+	writer.reset(httptest.NewRecorder())
+	events = nil
+	_, _ = w.WriteString("again")
+	assert.Empty(t, events)
+}
+
+func TestContextWriterWriteHooks(t *testing.T) {
+	var ttfb, written int
+	router := New()
+	router.Use(func(c *Context) {
+		c.Writer.OnFirstWrite(func() { ttfb++ })
+		c.Writer.OnWrite(func(n int) { written += n })
+		c.Next()
+	})
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "hello %s", "world")
+		c.Writer.Flush()
+		_, _ = c.Writer.Write([]byte("!"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, "hello world!", w.Body.String())
+	assert.Equal(t, 1, ttfb)
+	assert.Equal(t, 12, written)
+}
+
+// 实现io.ReaderFrom的ResponseWriter
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom int
+}
+
+func (r *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFrom++
+	return io.Copy(r.ResponseRecorder, src)
+}
+
+func TestResponseWriterReadFrom(t *testing.T) {
+	testWriter := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	writer := &responseWriter{}
+	writer.reset(testWriter)
+	w := ResponseWriter(writer)
+	first := 0
+	w.OnFirstWrite(func() { first++ })
+
+	// 空的body不调用OnFirstWrite
+	n, err := io.Copy(w, io.LimitReader(strings.NewReader(""), 100))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 0, w.Size())
+
+	n, err = io.Copy(w, io.LimitReader(strings.NewReader("hello"), 100))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, 2, testWriter.readFrom)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 5, w.Size())
+	assert.Equal(t, "hello", testWriter.Body.String())
+
+	// 注册了OnWrite时不委托给底层的ReadFrom
+	var chunks []int
+	w.OnWrite(func(n int) { chunks = append(chunks, n) })
+	n, err = writer.ReadFrom(io.MultiReader(strings.NewReader("ab"), strings.NewReader("cde")))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, 2, testWriter.readFrom)
+	assert.Equal(t, []int{2, 3}, chunks)
+	assert.Equal(t, 10, w.Size())
+	assert.Equal(t, 1, first)
+}
+
+func TestResponseWriterReadFromFallback(t *testing.T) {
+	testWriter := httptest.NewRecorder()
+	writer := &responseWriter{}
+	writer.reset(testWriter)
+
+	n, err := writer.ReadFrom(strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, 5, writer.Size())
+	assert.Equal(t, "hello", testWriter.Body.String())
+}
+
 func TestResponseWriterWriteHeadersNow(t *testing.T) {
 	testWriter := httptest.NewRecorder()
 	writer := &responseWriter{}
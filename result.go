@@ -0,0 +1,176 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// StatusCoder 可以指定status code的错误，WrapResult和HandlerOfResponse使用StatusCode()作为响应的status code
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// PublicError 可以返回给客户端的错误，WrapResult和HandlerOfResponse渲染错误时使用PublicMessage()，
+// 没有实现该接口的错误只返回status code对应的文本，避免泄露内部错误的内容
+type PublicError interface {
+	PublicMessage() string
+}
+
+// 通过MapErrorStatus注册的错误和status code
+type errorStatus struct {
+	err  error
+	code int
+}
+
+// 注册错误对应的status code，WrapResult和HandlerOfResponse的handler返回的错误满足errors.Is(returned, err)时使用code，
+// 按注册顺序匹配
+//
+//	router.MapErrorStatus(sql.ErrNoRows, http.StatusNotFound)
+func (engine *Engine) MapErrorStatus(err error, code int) *Engine {
+	engine.errorStatuses = append(engine.errorStatuses, errorStatus{err: err, code: code})
+	return engine
+}
+
+// 通过RespondOnError注册的错误响应
+type errorResponder struct {
+	match   func(error) bool
+	respond func(*Context, error)
+}
+
+// 注册错误的响应函数，WrapResult、HandlerOfResponse以及ErrorRenderer处理错误时按注册顺序调用matcher，
+// 第一个返回true的responder负责写入响应，没有匹配时使用默认的status code映射和渲染；
+// matcher可以使用ErrorIs和ErrorAs
+//
+//	router.RespondOnError(gin.ErrorIs(sql.ErrNoRows), func(c *gin.Context, err error) {
+//		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+//	})
+//	router.RespondOnError(gin.ErrorAs[validator.ValidationErrors](), respondValidation)
+func (engine *Engine) RespondOnError(matcher func(error) bool, responder func(*Context, error)) *Engine {
+	assert1(matcher != nil && responder != nil, "matcher and responder can not be nil")
+	engine.errorResponders = append(engine.errorResponders, errorResponder{match: matcher, respond: responder})
+	return engine
+}
+
+// 返回errors.Is(err, target)的matcher
+func ErrorIs(target error) func(error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// 返回errors.As(err, *T)的matcher
+func ErrorAs[T error]() func(error) bool {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// 返回通过RespondOnError注册的第一个匹配err的函数，没有匹配时返回nil
+func (c *Context) errorResponder(err error) func(*Context, error) {
+	if c.engine == nil {
+		return nil
+	}
+	for _, r := range c.engine.errorResponders {
+		if r.match(err) {
+			return r.respond
+		}
+	}
+	return nil
+}
+
+// 返回调用f的HandlerFunc，f返回的值根据Accept header渲染，f返回的错误根据错误映射为status code：
+// 优先使用Engine.RespondOnError注册的响应，其次依次使用实现了StatusCoder的错误、通过Engine.MapErrorStatus注册的错误、
+// 绑定错误（与HandlerOf相同），其他错误返回500。
+// 返回nil时没有写入响应则返回204，f已经写入响应时不再渲染
+//
+//	router.GET("/users/:id", gin.WrapResult(func(c *gin.Context) (any, error) {
+//		return findUser(c.Param("id"))
+//	}))
+func WrapResult(f func(*Context) (any, error)) HandlerFunc {
+	return func(c *Context) {
+		v, err := f(c)
+		if err != nil {
+			c.abortWithResultError(err)
+			return
+		}
+		if c.Writer.Written() {
+			return
+		}
+		if v == nil {
+			if c.Writer.Status() == http.StatusOK {
+				c.Status(http.StatusNoContent)
+			}
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		c.RenderNegotiated(c.Writer.Status(), v)
+	}
+}
+
+// 返回错误对应的status code
+func (c *Context) errorStatus(err error) int {
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		if code := coder.StatusCode(); code > 0 {
+			return code
+		}
+	}
+	if c.engine != nil {
+		for _, s := range c.engine.errorStatuses {
+			if errors.Is(err, s.err) {
+				return s.code
+			}
+		}
+	}
+	var bindErr *binding.Error
+	if errors.As(err, &bindErr) {
+		return c.handlerBindErrorStatus(err)
+	}
+	return http.StatusInternalServerError
+}
+
+// 记录handler返回的错误并调用Abort，使用与ErrorRenderer默认配置相同的格式渲染错误，
+// 已经写入响应时只记录错误；handler设置了4xx/5xx的status时使用该status，否则根据错误映射status code。
+// 绑定错误与HandlerOf相同使用renderBindError渲染；其他错误实现了PublicError时返回PublicMessage()，
+// 否则只返回status code对应的文本
+func (c *Context) abortWithResultError(err error) {
+	typ := ErrorTypePrivate
+	var bindErr *binding.Error
+	if errors.As(err, &bindErr) {
+		typ = ErrorTypeBind
+	}
+	if c.Writer.Written() {
+		_ = c.Error(err).SetType(typ)
+		c.Abort()
+		return
+	}
+	if respond := c.errorResponder(err); respond != nil {
+		_ = c.Error(err).SetType(typ)
+		c.Abort()
+		respond(c, err)
+		return
+	}
+
+	code := c.Writer.Status()
+	if code < http.StatusBadRequest {
+		code = c.errorStatus(err)
+	}
+	c.AbortWithError(code, err).SetType(typ) //nolint: errcheck
+	if bindErr != nil {
+		c.renderBindError(code, err, defaultErrorOffered)
+		return
+	}
+	msg := http.StatusText(code)
+	var public PublicError
+	if errors.As(err, &public) {
+		msg = public.PublicMessage()
+	}
+	c.Negotiate(code, Negotiate{Offered: defaultErrorOffered, Data: H{"errors": []any{H{"error": msg}}}})
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+var errResultNotFound = errors.New("user not found")
+
+type resultConflictError struct{}
+
+func (resultConflictError) Error() string   { return "version conflict" }
+func (resultConflictError) StatusCode() int { return http.StatusConflict }
+
+type resultPublicError struct{}
+
+func (resultPublicError) Error() string         { return "quota exceeded for tenant 42" }
+func (resultPublicError) PublicMessage() string { return "quota exceeded" }
+
+func TestWrapResult(t *testing.T) {
+	router := New()
+	router.MapErrorStatus(errResultNotFound, http.StatusNotFound)
+	router.GET("/users/:id", WrapResult(func(c *Context) (any, error) {
+		switch c.Param("id") {
+		case "1":
+			return H{"id": 1}, nil
+		case "created":
+			c.Status(http.StatusCreated)
+			return H{"id": 2}, nil
+		case "empty":
+			return nil, nil
+		case "written":
+			c.String(http.StatusAccepted, "done")
+			return H{"ignored": true}, nil
+		case "conflict":
+			return nil, fmt.Errorf("update: %w", resultConflictError{})
+		case "public":
+			c.Status(http.StatusTooManyRequests)
+			return nil, fmt.Errorf("check: %w", resultPublicError{})
+		case "bind":
+			var req struct {
+				Name string `form:"name" binding:"required"`
+			}
+			return nil, c.ShouldBindQuery(&req)
+		case "internal":
+			return nil, errors.New("database is down")
+		}
+		return nil, fmt.Errorf("find %s: %w", c.Param("id"), errResultNotFound)
+	}))
+
+	w := PerformRequest(router, http.MethodGet, "/users/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+
+	// 根据Accept header渲染
+	w = PerformRequest(router, http.MethodGet, "/users/created", header{Key: "Accept", Value: MIMEYAML})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "id: 2\n", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/users/empty")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/users/written")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "done", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/users/2")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	// 不返回内部错误的内容
+	assert.Equal(t, `{"errors":[{"error":"Not Found"}]}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/users/conflict")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"Conflict"}]}`, w.Body.String())
+
+	// 实现了PublicError的错误返回PublicMessage
+	w = PerformRequest(router, http.MethodGet, "/users/public")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"quota exceeded"}]}`, w.Body.String())
+
+	// 绑定错误与HandlerOf相同，返回错误的内容
+	w = PerformRequest(router, http.MethodGet, "/users/bind")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `{"errors":[{"error":"Key: '`)
+	assert.Contains(t, w.Body.String(), `'required' tag`)
+
+	// 5xx不返回错误的内容
+	w = PerformRequest(router, http.MethodGet, "/users/internal")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"errors":[{"error":"Internal Server Error"}]}`, w.Body.String())
+}
+
+func TestErrorStatus(t *testing.T) {
+	c, engine := CreateTestContext(nil)
+	engine.BindErrors.ValidationStatus = http.StatusUnprocessableEntity
+	engine.MapErrorStatus(errResultNotFound, http.StatusNotFound).
+		MapErrorStatus(errResultNotFound, http.StatusGone)
+
+	assert.Equal(t, http.StatusNotFound, c.errorStatus(errResultNotFound))
+	assert.Equal(t, http.StatusConflict, c.errorStatus(resultConflictError{}))
+	assert.Equal(t, http.StatusUnprocessableEntity, c.errorStatus(&binding.Error{Kind: binding.ErrValidation, Err: errors.New("invalid")}))
+	assert.Equal(t, http.StatusBadRequest, c.errorStatus(&binding.Error{Kind: binding.ErrDecode, Err: errors.New("invalid")}))
+	assert.Equal(t, http.StatusUnsupportedMediaType, c.errorStatus(&binding.Error{Kind: binding.ErrUnknownContentType, Err: errors.New("invalid")}))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, c.errorStatus(&binding.Error{Kind: binding.ErrBodyTooLarge, Err: errors.New("invalid")}))
+	assert.Equal(t, http.StatusInternalServerError, c.errorStatus(errors.New("other")))
+}
+
+func TestRespondOnError(t *testing.T) {
+	router := New()
+	var order []string
+	router.RespondOnError(ErrorIs(errResultNotFound), func(c *Context, err error) {
+		order = append(order, "not found")
+		c.JSON(http.StatusNotFound, H{"message": "no such user"})
+	}).RespondOnError(ErrorAs[resultConflictError](), func(c *Context, err error) {
+		order = append(order, "conflict")
+		c.String(http.StatusConflict, "retry later")
+	}).RespondOnError(func(err error) bool { return true }, func(c *Context, err error) {
+		order = append(order, "fallback")
+		c.Status(http.StatusTeapot)
+	})
+	var recorded []*Error
+	router.Use(func(c *Context) {
+		c.Next()
+		recorded = c.Errors
+	})
+	router.GET("/:id", WrapResult(func(c *Context) (any, error) {
+		switch c.Param("id") {
+		case "conflict":
+			return nil, fmt.Errorf("save: %w", resultConflictError{})
+		case "other":
+			return nil, errors.New("other")
+		}
+		return nil, fmt.Errorf("find: %w", errResultNotFound)
+	}))
+
+	w := PerformRequest(router, http.MethodGet, "/1")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"message":"no such user"}`, w.Body.String())
+	assert.Len(t, recorded, 1)
+
+	w = PerformRequest(router, http.MethodGet, "/conflict")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "retry later", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/other")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, []string{"not found", "conflict", "fallback"}, order)
+
+	assert.Panics(t, func() {
+		router.RespondOnError(nil, nil)
+	})
+}
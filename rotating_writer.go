@@ -0,0 +1,220 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 备份文件名中的时间格式
+const rotatingBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFileWriter 按文件大小或者时间间隔切分的日志文件writer，并发安全，
+// 可以用作LoggerConfig.Output或者DefaultErrorWriter
+type RotatingFileWriter struct {
+	// 日志文件路径，切分后的备份文件为同目录下的"名字-时间.扩展名"
+	Path string
+	// 文件大小超过MaxSize字节时切分，为0时不按大小切分
+	MaxSize int64
+	// 保留的备份文件数量，为0时不限制
+	MaxBackups int
+	// 备份文件的最长保留时间，为0时不限制
+	MaxAge time.Duration
+	// 按时间间隔切分，为0时不按时间切分
+	Interval time.Duration
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+var _ io.WriteCloser = (*RotatingFileWriter)(nil)
+
+// 返回按大小切分的日志文件writer，maxBackups和maxAge为0时不清理备份文件
+func RotatingWriter(path string, maxSize int64, maxBackups int, maxAge time.Duration) *RotatingFileWriter {
+	return &RotatingFileWriter{
+		Path:       path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+	}
+}
+
+// 写入日志，需要时先切分文件
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// 立即切分文件
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// 关闭当前文件
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// 是否需要切分，空文件不切分
+func (w *RotatingFileWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.MaxSize > 0 && w.size+n > w.MaxSize {
+		return true
+	}
+	return w.Interval > 0 && time.Since(w.openedAt) >= w.Interval
+}
+
+// 打开或者创建日志文件
+func (w *RotatingFileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.Path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file, w.size, w.openedAt = file, info.Size(), time.Now()
+	return nil
+}
+
+// 将当前文件重命名为备份文件，打开新的文件并清理过期的备份
+func (w *RotatingFileWriter) rotate() error {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+	}
+	if _, err := os.Stat(w.Path); err == nil {
+		if err := os.Rename(w.Path, w.backupName(time.Now())); err != nil {
+			return err
+		}
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.cleanup()
+}
+
+// 返回备份文件名，同一时间已存在时添加序号
+func (w *RotatingFileWriter) backupName(t time.Time) string {
+	ext := filepath.Ext(w.Path)
+	prefix := strings.TrimSuffix(w.Path, ext) + "-" + t.Format(rotatingBackupTimeFormat)
+	name := prefix + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s.%d%s", prefix, i, ext)
+	}
+}
+
+// 是否为backupName生成的备份文件名："名字-时间.扩展名"或者"名字-时间.序号.扩展名"
+func (w *RotatingFileWriter) isBackupName(name string) bool {
+	ext := filepath.Ext(w.Path)
+	prefix := strings.TrimSuffix(w.Path, ext) + "-"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) || len(name) < len(prefix)+len(ext) {
+		return false
+	}
+	stamp := name[len(prefix) : len(name)-len(ext)]
+	if len(stamp) < len(rotatingBackupTimeFormat) {
+		return false
+	}
+	if _, err := time.ParseInLocation(rotatingBackupTimeFormat, stamp[:len(rotatingBackupTimeFormat)], time.Local); err != nil {
+		return false
+	}
+	seq := stamp[len(rotatingBackupTimeFormat):]
+	if seq == "" {
+		return true
+	}
+	if len(seq) < 2 || seq[0] != '.' {
+		return false
+	}
+	_, err := strconv.ParseUint(seq[1:], 10, 64)
+	return err == nil
+}
+
+// 删除超过MaxBackups数量或者MaxAge时间的备份文件
+func (w *RotatingFileWriter) cleanup() error {
+	if w.MaxBackups <= 0 && w.MaxAge <= 0 {
+		return nil
+	}
+	ext := filepath.Ext(w.Path)
+	backups, err := filepath.Glob(strings.TrimSuffix(w.Path, ext) + "-*" + ext)
+	if err != nil {
+		return err
+	}
+	type backup struct {
+		name    string
+		modTime time.Time
+	}
+	files := make([]backup, 0, len(backups))
+	for _, name := range backups {
+		// 只处理backupName生成的文件，例如不删除app.log所在目录中的app-error.log
+		if !w.isBackupName(name) {
+			continue
+		}
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			files = append(files, backup{name: name, modTime: info.ModTime()})
+		}
+	}
+	// 按时间从新到旧排序
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].modTime.Equal(files[j].modTime) {
+			return files[i].name > files[j].name
+		}
+		return files[i].modTime.After(files[j].modTime)
+	})
+	for i, f := range files {
+		expired := w.MaxAge > 0 && time.Since(f.modTime) > w.MaxAge
+		if (w.MaxBackups > 0 && i >= w.MaxBackups) || expired {
+			if err := os.Remove(f.name); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingWriterSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "gin.log")
+	w := RotatingWriter(path, 10, 2, 0)
+	defer w.Close()
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		n, err := w.Write([]byte(line))
+		assert.NoError(t, err)
+		assert.Equal(t, len(line), n)
+	}
+
+	current, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "dddddddd\n", string(current))
+
+	// 只保留2个备份
+	backups, _ := filepath.Glob(filepath.Join(dir, "logs", "gin-*.log"))
+	assert.Len(t, backups, 2)
+	var contents []string
+	for _, name := range backups {
+		data, _ := os.ReadFile(name)
+		contents = append(contents, string(data))
+	}
+	assert.ElementsMatch(t, []string{"bbbbbbbb\n", "cccccccc\n"}, contents)
+}
+
+func TestRotatingWriterAppendAndMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gin.log")
+	assert.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o644))
+
+	old := filepath.Join(dir, "gin-2000-01-01T00-00-00.000.log")
+	assert.NoError(t, os.WriteFile(old, []byte("old\n"), 0o644))
+	past := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(old, past, past))
+
+	w := RotatingWriter(path, 0, 0, 24*time.Hour)
+	_, err := w.Write([]byte("appended\n"))
+	assert.NoError(t, err)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "existing\nappended\n", string(data))
+
+	assert.NoError(t, w.Rotate())
+	_, err = os.Stat(old)
+	assert.True(t, os.IsNotExist(err))
+	backups, _ := filepath.Glob(filepath.Join(dir, "gin-*.log"))
+	assert.Len(t, backups, 1)
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+}
+
+func TestRotatingWriterInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gin.log")
+	w := &RotatingFileWriter{Path: path, Interval: time.Hour}
+	defer w.Close()
+
+	_, err := w.Write([]byte("first\n"))
+	assert.NoError(t, err)
+	w.openedAt = w.openedAt.Add(-2 * time.Hour)
+	_, err = w.Write([]byte("second\n"))
+	assert.NoError(t, err)
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, "second\n", string(data))
+	backups, _ := filepath.Glob(filepath.Join(dir, "gin-*.log"))
+	assert.Len(t, backups, 1)
+}
+
+func TestRotatingWriterCleanupOnlyBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	past := time.Now().Add(-48 * time.Hour)
+	others := []string{"app-error.log", "app-2000-01-01.log", "app-2000-01-01T00-00-00.000.x.log"}
+	for _, name := range append(others, "app-2000-01-01T00-00-00.000.log", "app-2000-01-01T00-00-00.000.1.log") {
+		name = filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(name, []byte("old\n"), 0o644))
+		assert.NoError(t, os.Chtimes(name, past, past))
+	}
+
+	w := RotatingWriter(path, 0, 1, 24*time.Hour)
+	defer w.Close()
+	_, err := w.Write([]byte("line\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Rotate())
+
+	// 过期的备份被删除，其他文件不受影响
+	for _, name := range others {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+	_, err = os.Stat(filepath.Join(dir, "app-2000-01-01T00-00-00.000.log"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "app-2000-01-01T00-00-00.000.1.log"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+// 可选参数路由展开后的信息，key不为空时表示请求中没有可选参数，需要补充空值
+type optionalRoute struct {
+	fullPath string
+	key      string
+}
+
+// 解析以可选参数结尾的路由，例如/posts/:id/:slug?返回/posts/:id/:slug、/posts/:id和slug；
+// 可选参数只能是路由的最后一个路径段
+func splitOptionalParam(path string) (full, short, key string, ok bool) {
+	rest, offset := path, 0
+	for {
+		wildcard, i, _ := findWildcard(rest)
+		if i < 0 {
+			return "", "", "", false
+		}
+		start := offset + i
+		end := start + len(wildcard)
+		offset = end
+		rest = path[end:]
+		if wildcard[len(wildcard)-1] != '?' {
+			continue
+		}
+		if wildcard[0] != ':' || len(wildcard) < 3 {
+			panic("invalid optional wildcard '" + wildcard + "' in path '" + path +
+				"': only named parameters can be optional")
+		}
+		if end != len(path) || path[start-1] != '/' {
+			panic("optional parameter '" + wildcard + "' is only allowed as the last path segment in path '" + path + "'")
+		}
+		full = path[:end-1]
+		short = path[:start-1]
+		if short == "" {
+			short = "/"
+		}
+		return full, short, wildcard[1 : len(wildcard)-1], true
+	}
+}
+
+// 记录可选参数路由展开后的两个路由
+func (engine *Engine) addOptionalRoute(method, path, full, short, key string) {
+	if engine.optionalRoutes == nil {
+		engine.optionalRoutes = make(map[string]map[string]optionalRoute)
+	}
+	if engine.optionalRoutes[method] == nil {
+		engine.optionalRoutes[method] = make(map[string]optionalRoute)
+	}
+	engine.optionalRoutes[method][full] = optionalRoute{fullPath: path}
+	engine.optionalRoutes[method][short] = optionalRoute{fullPath: path, key: key}
+	engine.publishRoutes()
+}
+
+// 匹配到可选参数路由时返回注册的路径，请求中没有可选参数时补充空值
+func applyOptionalRoute(routes map[string]optionalRoute, fullPath string, params Params) (string, Params) {
+	route, ok := routes[fullPath]
+	if !ok {
+		return fullPath, params
+	}
+	if route.key != "" {
+		params = append(params, Param{Key: route.key})
+	}
+	return route.fullPath, params
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitOptionalParam(t *testing.T) {
+	full, short, key, ok := splitOptionalParam("/posts/:id/:slug?")
+	assert.True(t, ok)
+	assert.Equal(t, "/posts/:id/:slug", full)
+	assert.Equal(t, "/posts/:id", short)
+	assert.Equal(t, "slug", key)
+
+	full, short, key, ok = splitOptionalParam("/:lang?")
+	assert.True(t, ok)
+	assert.Equal(t, "/:lang", full)
+	assert.Equal(t, "/", short)
+	assert.Equal(t, "lang", key)
+
+	_, _, _, ok = splitOptionalParam("/posts/:id/:slug")
+	assert.False(t, ok)
+	_, _, _, ok = splitOptionalParam("/posts/:id/*path")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { splitOptionalParam("/posts/:id?/comments") })
+	assert.Panics(t, func() { splitOptionalParam("/posts/:slug?/") })
+	assert.Panics(t, func() { splitOptionalParam("/posts/v:slug?") })
+	assert.Panics(t, func() { splitOptionalParam("/files/*path?") })
+	assert.Panics(t, func() { splitOptionalParam("/posts/:?") })
+}
+
+func TestRouteOptionalParam(t *testing.T) {
+	router := New()
+	router.GET("/posts/:id/:slug?", func(c *Context) {
+		slug, ok := c.Params.Get("slug")
+		c.String(http.StatusOK, "%s %s %v %s", c.Param("id"), slug, ok, c.FullPath())
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/posts/1/hello-world")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1 hello-world true /posts/:id/:slug?", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/posts/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1  true /posts/:id/:slug?", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/posts/1/")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/posts/1", w.Header().Get("Location"))
+
+	w = PerformRequest(router, http.MethodGet, "/posts/1/hello/extra")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	assert.Panics(t, func() { router.GET("/posts/:id", handlerTest1) })
+	assert.Empty(t, router.Validate())
+}
+
+func TestRouteOptionalParamRoot(t *testing.T) {
+	router := New()
+	router.GET("/:lang?", func(c *Context) {
+		c.String(http.StatusOK, "[%s]", c.Param("lang"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, "[]", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/en")
+	assert.Equal(t, "[en]", w.Body.String())
+}
+
+func TestMatcherOptionalParam(t *testing.T) {
+	router := New()
+	router.GET("/posts/:id/:slug?", handlerTest1)
+	m := router.Matcher()
+
+	route, params, ok := m.Match(http.MethodGet, "/posts/1")
+	assert.True(t, ok)
+	assert.Equal(t, "/posts/:id/:slug?", route.Path)
+	assert.Equal(t, Params{{Key: "id", Value: "1"}, {Key: "slug", Value: ""}}, params)
+
+	route, params, ok = m.Match(http.MethodGet, "/posts/1/title")
+	assert.True(t, ok)
+	assert.Equal(t, "/posts/:id/:slug?", route.Path)
+	assert.Equal(t, Params{{Key: "id", Value: "1"}, {Key: "slug", Value: "title"}}, params)
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+// 处理请求使用的路由表，发布之后不再修改，通过ReplaceRoutes整体替换
+type routeTable struct {
+	trees          methodTrees
+	maxParams      uint16
+	maxSections    uint16
+	rawPathRoutes  map[string]map[string]rawPathOption
+	optionalRoutes map[string]map[string]optionalRoute
+}
+
+// 返回当前发布的路由表
+func (engine *Engine) currentRoutes() *routeTable {
+	if t := engine.table.Load(); t != nil {
+		return t
+	}
+	return &routeTable{}
+}
+
+// 发布当前注册的路由，ReplaceRoutes构建新的路由表时不发布
+func (engine *Engine) publishRoutes() {
+	if engine.replacing {
+		return
+	}
+	engine.table.Store(&routeTable{
+		trees:          engine.trees,
+		maxParams:      engine.maxParams,
+		maxSections:    engine.maxSections,
+		rawPathRoutes:  engine.rawPathRoutes,
+		optionalRoutes: engine.optionalRoutes,
+	})
+}
+
+// 在新的路由表中注册build中的路由，完成之后原子地替换当前的路由表，用于根据配置重新加载路由：
+//
+//	engine.ReplaceRoutes(func(r *gin.RouterGroup) {
+//		for _, route := range conf.Routes {
+//			r.GET(route.Path, proxyTo(route.Upstream))
+//		}
+//	})
+//
+// 替换之前已经开始处理的请求继续使用原来的路由表，原来的路由表不会被修改；传入的RouterGroup包含通过Engine.Use
+// 添加的middleware，原来注册的路由不会保留。build panic时（例如路由冲突）保留原来的路由表并重新panic。
+// 开始处理请求之后只能通过ReplaceRoutes修改路由，多次调用会依次执行
+func (engine *Engine) ReplaceRoutes(build func(*RouterGroup)) {
+	engine.replaceMu.Lock()
+	defer engine.replaceMu.Unlock()
+
+	old := engine.currentRoutes()
+	engine.replacing = true
+	engine.trees = make(methodTrees, 0, len(old.trees))
+	engine.maxParams = 0
+	engine.maxSections = 0
+	engine.rawPathRoutes = nil
+	engine.optionalRoutes = nil
+
+	built := false
+	defer func() {
+		engine.replacing = false
+		if !built {
+			// 恢复原来的路由表，已经发布的路由表不受影响
+			engine.trees = old.trees
+			engine.maxParams = old.maxParams
+			engine.maxSections = old.maxSections
+			engine.rawPathRoutes = old.rawPathRoutes
+			engine.optionalRoutes = old.optionalRoutes
+		}
+	}()
+
+	build(&RouterGroup{
+		Handlers: engine.combineHandlers(nil),
+		basePath: "/",
+		engine:   engine,
+	})
+	built = true
+	engine.replacing = false
+	engine.publishRoutes()
+}
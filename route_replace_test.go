@@ -0,0 +1,143 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceRoutes(t *testing.T) {
+	router := New()
+	router.Use(func(c *Context) {
+		c.Header("X-Middleware", "yes")
+	})
+	router.GET("/old", func(c *Context) {
+		c.String(http.StatusOK, "old")
+	})
+
+	router.ReplaceRoutes(func(r *RouterGroup) {
+		r.GET("/users/:group/:id/:tab", func(c *Context) {
+			c.String(http.StatusOK, c.Param("group")+c.Param("id")+c.Param("tab"))
+		})
+		r.Group("/v1").WithRawPath(true).GET("/files/:name", func(c *Context) {
+			c.String(http.StatusOK, c.Param("name"))
+		})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/old")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 新的路由表需要更多的参数
+	w = PerformRequest(router, http.MethodGet, "/users/a/1/posts")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a1posts", w.Body.String())
+	assert.Equal(t, "yes", w.Header().Get("X-Middleware"))
+
+	w = PerformRequest(router, http.MethodGet, "/v1/files/a%2Fb")
+	assert.Equal(t, "a/b", w.Body.String())
+
+	routes := router.Routes()
+	assert.Len(t, routes, 2)
+	_, params, ok := router.Matcher().Match(http.MethodGet, "/users/a/1/posts")
+	assert.True(t, ok)
+	assert.Len(t, params, 3)
+}
+
+func TestReplaceRoutesInFlight(t *testing.T) {
+	router := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	router.GET("/slow", func(c *Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "old")
+	})
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+		router.ServeHTTP(w, req)
+	}()
+	<-started
+
+	router.ReplaceRoutes(func(r *RouterGroup) {
+		r.GET("/slow", func(c *Context) {
+			c.String(http.StatusOK, "new")
+		})
+	})
+	close(release)
+	<-done
+
+	// 已经开始处理的请求使用原来的handler
+	assert.Equal(t, "old", w.Body.String())
+	assert.Equal(t, "new", PerformRequest(router, http.MethodGet, "/slow").Body.String())
+}
+
+func TestReplaceRoutesPanic(t *testing.T) {
+	router := New()
+	router.GET("/keep", func(c *Context) {
+		c.String(http.StatusOK, "keep")
+	})
+
+	assert.Panics(t, func() {
+		router.ReplaceRoutes(func(r *RouterGroup) {
+			r.GET("/conflict/:id", func(c *Context) {})
+			r.GET("/conflict/:name", func(c *Context) {})
+		})
+	})
+
+	// 构建失败时保留原来的路由表
+	assert.Equal(t, "keep", PerformRequest(router, http.MethodGet, "/keep").Body.String())
+	assert.Equal(t, http.StatusNotFound, PerformRequest(router, http.MethodGet, "/conflict/1").Code)
+	assert.Len(t, router.Routes(), 1)
+
+	// 之后仍然可以注册路由
+	router.GET("/after", func(c *Context) {})
+	assert.Equal(t, http.StatusOK, PerformRequest(router, http.MethodGet, "/after").Code)
+}
+
+func TestReplaceRoutesConcurrent(t *testing.T) {
+	router := New()
+	router.GET("/ping", func(c *Context) {
+		c.String(http.StatusOK, "v0")
+	})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				w := PerformRequest(router, http.MethodGet, "/ping")
+				assert.Equal(t, http.StatusOK, w.Code)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		body := "v" + string(rune('a'+i))
+		router.ReplaceRoutes(func(r *RouterGroup) {
+			r.GET("/ping", func(c *Context) {
+				c.String(http.StatusOK, body)
+			})
+			r.GET("/p/:a/:b/:c/:d", func(c *Context) {})
+		})
+	}
+	close(stop)
+	wg.Wait()
+	assert.Equal(t, "vt", PerformRequest(router, http.MethodGet, "/ping").Body.String())
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 路由检查发现的问题类型
+type RouteWarningKind string
+
+const (
+	// 相同路径在不同method中使用了不同的参数名或者参数类型，例如GET /users/:id和DELETE /users/:name
+	RouteWarningWildcardMismatch RouteWarningKind = "wildcard-mismatch"
+	// 路由永远不会被匹配，请求会被其他路由处理，或者路径被RemoveExtraSlash清理后无法匹配
+	RouteWarningShadowed RouteWarningKind = "shadowed"
+	// catch-all路由永远不会被匹配
+	RouteWarningUnreachableCatchAll RouteWarningKind = "unreachable-catch-all"
+)
+
+// Engine.Validate发现的可疑路由
+type RouteWarning struct {
+	Kind   RouteWarningKind
+	Method string
+	Path   string
+	// 相关的路由，格式为"METHOD PATH"，没有时为空
+	Other string
+}
+
+func (w RouteWarning) String() string {
+	s := fmt.Sprintf("%s: %s %s", w.Kind, w.Method, w.Path)
+	if w.Other != "" {
+		s += " (" + w.Other + ")"
+	}
+	return s
+}
+
+// 检查已注册的路由，返回可疑的注册，通常在启动时调用并打印或者在测试中断言为空：
+//   - 相同路径在不同method中使用了不同的参数名或者参数类型
+//   - 被其他路由遮蔽而无法匹配的路由
+//   - 无法匹配的catch-all路由
+func (engine *Engine) Validate() []RouteWarning {
+	var warnings []RouteWarning
+	routes := engine.RoutesSorted()
+
+	// 按照去掉参数名之后的路径分组，检查不同method之间的参数是否一致
+	shapes := make(map[string]RouteInfo)
+	for _, route := range routes {
+		shape := routeShape(route.Path)
+		first, ok := shapes[shape]
+		if !ok {
+			shapes[shape] = route
+			continue
+		}
+		if first.Path != route.Path {
+			warnings = append(warnings, RouteWarning{
+				Kind:   RouteWarningWildcardMismatch,
+				Method: route.Method,
+				Path:   route.Path,
+				Other:  first.Method + " " + first.Path,
+			})
+		}
+	}
+
+	// 将路由的参数替换为具体的值后匹配，没有匹配到自身的路由无法访问
+	m := engine.Matcher()
+	for _, route := range routes {
+		path, _ := concreteRoutePath(route.Path)
+		matched, _, ok := m.Match(route.Method, path)
+		expected := route.Path
+		if optional, found := engine.currentRoutes().optionalRoutes[route.Method][route.Path]; found {
+			expected = optional.fullPath
+		}
+		if ok && matched.Path == expected {
+			continue
+		}
+		w := RouteWarning{Kind: RouteWarningShadowed, Method: route.Method, Path: route.Path}
+		if strings.Contains(route.Path, "*") {
+			w.Kind = RouteWarningUnreachableCatchAll
+		}
+		if ok {
+			w.Other = matched.Method + " " + matched.Path
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// 去掉参数名之后的路径，参数和catch-all都替换为':'，例如/users/:id/*path返回/users/:/:
+func routeShape(path string) string {
+	var b strings.Builder
+	for {
+		wildcard, i, valid := findWildcard(path)
+		if i < 0 || !valid {
+			b.WriteString(path)
+			return b.String()
+		}
+		b.WriteString(path[:i])
+		b.WriteByte(':')
+		path = path[i+len(wildcard):]
+	}
+}
+
+// 将路由中的参数替换为以'\x00'开头的值，避免与静态路径冲突，返回请求路径和期望的参数
+func concreteRoutePath(route string) (string, Params) {
+	var b strings.Builder
+	var params Params
+	for {
+		wildcard, i, valid := findWildcard(route)
+		if i < 0 || !valid {
+			b.WriteString(route)
+			break
+		}
+		b.WriteString(route[:i])
+		value := fmt.Sprintf("\x00%d", len(params))
+		if wildcard[0] == '*' {
+			// catch-all的值包含前面的'/'，只使用一个路径段，避免被catch-all之后的路由匹配
+			value = "/" + value
+			b.WriteString(value[1:])
+		} else {
+			b.WriteString(value)
+		}
+		params = append(params, Param{Key: wildcard[1:], Value: value})
+		route = route[i+len(wildcard):]
+	}
+	return b.String(), params
+}
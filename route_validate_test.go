@@ -0,0 +1,57 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineValidate(t *testing.T) {
+	router := New()
+	router.GET("/users/:id", handlerTest1)
+	router.PUT("/users/:id", handlerTest1)
+	router.DELETE("/users/:name", handlerTest1)
+	router.GET("/files/*path", handlerTest1)
+	router.POST("/files/:name", handlerTest1)
+	router.GET("/users/:id/posts", handlerTest1)
+
+	assert.Equal(t, []RouteWarning{
+		{Kind: RouteWarningWildcardMismatch, Method: "POST", Path: "/files/:name", Other: "GET /files/*path"},
+		{Kind: RouteWarningWildcardMismatch, Method: "DELETE", Path: "/users/:name", Other: "GET /users/:id"},
+	}, router.Validate())
+}
+
+func TestEngineValidateUnreachable(t *testing.T) {
+	// RouterGroup会清理注册的路径，这里直接添加未清理的路径，模拟无法匹配的路由
+	router := New()
+	router.RemoveExtraSlash = true
+	router.addRoute("GET", "/a//b", HandlersChain{handlerTest1})
+	router.addRoute("GET", "/a/b/../c", HandlersChain{handlerTest1})
+	router.GET("/a/c", handlerTest2)
+	router.addRoute("GET", "/static//*filepath", HandlersChain{handlerTest1})
+
+	warnings := router.Validate()
+	assert.Equal(t, []RouteWarning{
+		{Kind: RouteWarningShadowed, Method: "GET", Path: "/a//b"},
+		{Kind: RouteWarningShadowed, Method: "GET", Path: "/a/b/../c", Other: "GET /a/c"},
+		{Kind: RouteWarningUnreachableCatchAll, Method: "GET", Path: "/static//*filepath"},
+	}, warnings)
+	assert.Equal(t, "shadowed: GET /a/b/../c (GET /a/c)", warnings[1].String())
+	assert.Equal(t, "shadowed: GET /a//b", warnings[0].String())
+}
+
+func TestEngineValidateClean(t *testing.T) {
+	router := New()
+	router.GET("/", handlerTest1)
+	router.GET("/users/new", handlerTest1)
+	router.GET("/users/:id", handlerTest1)
+	router.POST("/users/:id", handlerTest1)
+	router.GET("/static/*filepath", handlerTest1)
+	router.GET("/~0", handlerTest1)
+	router.GET("/:name", handlerTest1)
+	assert.Empty(t, router.Validate())
+}
@@ -56,6 +56,13 @@ type RouterGroup struct {
 	basePath string
 	engine   *Engine
 	root     bool
+	// 不为nil时通过该RouterGroup注册的路由使用URL.RawPath匹配
+	rawPath *rawPathOption
+}
+
+// 路由级别的RawPath配置
+type rawPathOption struct {
+	unescape bool
 }
 
 // 接口实现校验
@@ -73,6 +80,21 @@ func (group *RouterGroup) Group(relativePath string, handlers ...HandlerFunc) *R
 		Handlers: group.combineHandlers(handlers),
 		basePath: group.calculateAbsolutePath(relativePath),
 		engine:   group.engine,
+		rawPath:  group.rawPath,
+	}
+}
+
+// 返回使用URL.RawPath匹配路由的RouterGroup，通过该RouterGroup注册的路由的参数可以包含编码的'/'（%2F），
+// unescape为true时解码参数值。只有请求的URL.RawPath不为空并且匹配到这些路由时才使用RawPath，其他路由不受影响。
+// Engine.UseRawPath为true时所有路由都使用RawPath，该设置不生效
+//
+//	router.WithRawPath(true).GET("/files/:name", handler) // /files/a%2Fb的name为a/b
+func (group *RouterGroup) WithRawPath(unescape bool) *RouterGroup {
+	return &RouterGroup{
+		Handlers: group.combineHandlers(nil),
+		basePath: group.basePath,
+		engine:   group.engine,
+		rawPath:  &rawPathOption{unescape: unescape},
 	}
 }
 
@@ -90,6 +112,9 @@ func (group *RouterGroup) handle(httpMethod, relativePath string, handlers Handl
 	handlers = group.combineHandlers(handlers)
 	// 将http method、绝对路由路径、handlers添加到engine中
 	group.engine.addRoute(httpMethod, absolutePath, handlers)
+	if group.rawPath != nil {
+		group.engine.addRawPathRoute(httpMethod, absolutePath, *group.rawPath)
+	}
 	return group.returnObj()
 }
 
@@ -194,11 +219,21 @@ func (group *RouterGroup) Static(relativePath, root string) IRoutes {
 
 // StaticFS与Static相似，但是http.FileSystem可以被替换，默认使用gin.Dir
 func (group *RouterGroup) StaticFS(relativePath string, fs http.FileSystem) IRoutes {
+	return group.StaticFSWithOptions(relativePath, fs, StaticOptions{})
+}
+
+// 与Static相同，通过opts设置缓存相关的header
+func (group *RouterGroup) StaticWithOptions(relativePath, root string, opts StaticOptions) IRoutes {
+	return group.StaticFSWithOptions(relativePath, Dir(root, false), opts)
+}
+
+// 与StaticFS相同，通过opts设置缓存相关的header
+func (group *RouterGroup) StaticFSWithOptions(relativePath string, fs http.FileSystem, opts StaticOptions) IRoutes {
 	if strings.Contains(relativePath, ":") || strings.Contains(relativePath, "*") {
 		panic("URL parameters can not be used when serving a static folder")
 	}
 	// 创建一个static的handler
-	handler := group.createStaticHandler(relativePath, fs)
+	handler := group.createStaticHandler(relativePath, fs, opts)
 	// path拼接
 	urlPattern := path.Join(relativePath, "/*filepath")
 
@@ -208,7 +243,7 @@ func (group *RouterGroup) StaticFS(relativePath string, fs http.FileSystem) IRou
 	return group.returnObj()
 }
 
-func (group *RouterGroup) createStaticHandler(relativePath string, fs http.FileSystem) HandlerFunc {
+func (group *RouterGroup) createStaticHandler(relativePath string, fs http.FileSystem, opts StaticOptions) HandlerFunc {
 	// 计算绝对路径
 	absolutePath := group.calculateAbsolutePath(relativePath)
 	// 创建http的路由handler
@@ -227,10 +262,30 @@ func (group *RouterGroup) createStaticHandler(relativePath string, fs http.FileS
 		// 报错返回404
 		if err != nil {
 			c.Writer.WriteHeader(http.StatusNotFound)
+			if opts.NotFound != nil {
+				opts.NotFound(c)
+				return
+			}
 			c.handlers = group.engine.noRoute
 			c.index = -1
 			return
 		}
+		if opts.enabled() {
+			if stat, err := f.Stat(); err == nil {
+				if stat.IsDir() {
+					if len(opts.IndexFiles) > 0 && strings.HasSuffix(c.Request.URL.Path, "/") && opts.serveIndex(c, fs, file) {
+						f.Close()
+						return
+					}
+				} else {
+					if opts.Precompressed && opts.servePrecompressed(c, fs, file) {
+						f.Close()
+						return
+					}
+					opts.setHeaders(c.Writer.Header(), file, stat)
+				}
+			}
+		}
 		f.Close()
 
 		// 开启file的http server
@@ -10,7 +10,9 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -664,6 +666,215 @@ func TestRouteRawPathNoUnescape(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestRouteWithRawPath(t *testing.T) {
+	router := New()
+	handler := func(c *Context) {
+		c.String(http.StatusOK, "%s %s", c.FullPath(), c.Param("name"))
+	}
+	router.WithRawPath(true).GET("/files/:name", handler)
+	router.Group("/raw").WithRawPath(false).GET("/:name", handler)
+	router.GET("/users/:name", handler)
+	router.GET("/users/:name/:rest", handler)
+
+	w := PerformRequest(router, http.MethodGet, "/files/a%2Fb.txt")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/files/:name a/b.txt", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/raw/a%2Fb")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/raw/:name a%2Fb", w.Body.String())
+
+	// 其他路由仍然使用URL.Path
+	w = PerformRequest(router, http.MethodGet, "/users/a%2Fb")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/users/:name/:rest a", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/files/plain")
+	assert.Equal(t, "/files/:name plain", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/files/a%2Fb/c")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouteWithRawPathGroup(t *testing.T) {
+	router := New()
+	router.Use(func(c *Context) {
+		c.Header("X-Middleware", "1")
+	})
+	files := router.Group("/v1").WithRawPath(true).Group("/files")
+	files.GET("/:name", func(c *Context) {
+		c.String(http.StatusOK, c.Param("name"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/v1/files/a%2Fb")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a/b", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Middleware"))
+}
+
+func TestRouteEncodedSlashPolicy(t *testing.T) {
+	tests := []struct {
+		policy   EncodedSlashPolicy
+		unescape bool
+		path     string
+		code     int
+		body     string
+	}{
+		{EncodedSlashDecode, true, "/files/a%2Fb%20c/x%2fy", 200, "a/b c x/y"},
+		{EncodedSlashKeep, true, "/files/a%2Fb%20c/x%2fy", 200, "a%2Fb c x%2Fy"},
+		{EncodedSlashKeep, true, "/files/a%252Fb%2F/c%25", 200, "a%252Fb%2F c%25"},
+		{EncodedSlashReject, true, "/files/a%2Fb/c", 400, "400 bad request"},
+		{EncodedSlashReject, true, "/files/a/c%2f", 400, "400 bad request"},
+		{EncodedSlashReject, true, "/files/a%252F%41/d", 200, "a%2FA d"},
+		{EncodedSlashReject, false, "/files/a%2Fb/c", 400, "400 bad request"},
+		{EncodedSlashReject, false, "/files/a%20b/c", 200, "a b c"},
+		{EncodedSlashReject, true, "/files/a/b", 200, "a b"},
+	}
+
+	for _, tt := range tests {
+		router := New()
+		router.UseRawPath = true
+		router.UnescapePathValues = tt.unescape
+		router.EncodedSlashPolicy = tt.policy
+		router.GET("/files/:name/*rest", func(c *Context) {
+			c.String(http.StatusOK, "%s %s", c.Param("name"), strings.TrimPrefix(c.Param("rest"), "/"))
+		})
+
+		w := PerformRequest(router, http.MethodGet, tt.path)
+		assert.Equal(t, tt.code, w.Code, "policy=%d path=%s", tt.policy, tt.path)
+		assert.Equal(t, tt.body, w.Body.String(), "policy=%d path=%s", tt.policy, tt.path)
+	}
+}
+
+func TestRouteEncodedSlashPolicyWithRawPath(t *testing.T) {
+	router := New()
+	router.EncodedSlashPolicy = EncodedSlashReject
+	router.WithRawPath(true).GET("/proxy/:target", func(c *Context) {
+		c.String(http.StatusOK, c.Param("target"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/proxy/a%2Fb")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/proxy/a%3Fb")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a?b", w.Body.String())
+
+	// NoRoute handler不会替换400
+	router.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "custom 404")
+	})
+	w = PerformRequest(router, http.MethodGet, "/proxy/a%2Fb")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "400 bad request", w.Body.String())
+}
+
+func TestRouteDefaultHeaders(t *testing.T) {
+	router := New()
+	router.HandleMethodNotAllowed = true
+	router.DefaultHeaders(map[string]string{
+		"server":                 "gin",
+		"X-Content-Type-Options": "nosniff",
+	})
+	router.GET("/ok", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/custom", func(c *Context) {
+		c.Header("Server", "custom")
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/panic", Recovery(), func(c *Context) {
+		panic("oops")
+	})
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		code   int
+		server string
+	}{
+		{http.MethodGet, "/ok", http.StatusOK, "gin"},
+		{http.MethodGet, "/custom", http.StatusNoContent, "custom"},
+		{http.MethodGet, "/missing", http.StatusNotFound, "gin"},
+		{http.MethodPost, "/ok", http.StatusMethodNotAllowed, "gin"},
+		{http.MethodGet, "/ok/", http.StatusMovedPermanently, "gin"},
+		{http.MethodGet, "/panic", http.StatusInternalServerError, "gin"},
+	} {
+		w := PerformRequest(router, tc.method, tc.path)
+		assert.Equal(t, tc.code, w.Code, tc.path)
+		assert.Equal(t, tc.server, w.Header().Get("Server"), tc.path)
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"), tc.path)
+	}
+
+	router.DefaultHeaders(nil)
+	w := PerformRequest(router, http.MethodGet, "/missing")
+	assert.Empty(t, w.Header().Get("Server"))
+}
+
+func TestRouteMidPathCatchAll(t *testing.T) {
+	router := New()
+	router.GET("/repos/*path/commits/:sha", func(c *Context) {
+		c.String(http.StatusOK, "commit %s %s", c.Param("path"), c.Param("sha"))
+	})
+	router.GET("/repos/*path", func(c *Context) {
+		c.String(http.StatusOK, "repo %s", c.Param("path"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/repos/org/team/project/commits/abc123")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "commit /org/team/project abc123", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/repos/org/project/commits")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "repo /org/project/commits", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/repos/org/project/commits/abc/extra")
+	assert.Equal(t, "repo /org/project/commits/abc/extra", w.Body.String())
+
+	route, params, ok := router.Matcher().Match(http.MethodGet, "/repos/a/commits/b")
+	assert.True(t, ok)
+	assert.Equal(t, "/repos/*path/commits/:sha", route.Path)
+	assert.Equal(t, Params{{Key: "path", Value: "/a"}, {Key: "sha", Value: "b"}}, params)
+}
+
+func TestRouteMidPathCatchAllLongPath(t *testing.T) {
+	router := New()
+	router.UseRawPath = true
+	router.GET("/r/*a/x/:id/y", func(c *Context) {
+		c.String(http.StatusOK, "%d %s", len(c.Param("a")), c.Param("id"))
+	})
+	router.GET("/r/*a/x/new", func(c *Context) {})
+
+	// 匹配时间与路径长度成线性关系
+	long := "/r/%41" + strings.Repeat("/x/new", 8192)
+	start := time.Now()
+	w := PerformRequest(router, http.MethodGet, long+"/z")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = PerformRequest(router, http.MethodGet, long+"/x/a%20b/y")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, fmt.Sprintf("%d a b", len(long)-4), w.Body.String())
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRouteMidPathCatchAllOnly(t *testing.T) {
+	router := New()
+	router.GET("/artifacts", func(c *Context) {})
+	router.GET("/artifacts/*path/download", func(c *Context) {
+		c.String(http.StatusOK, c.Param("path"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/artifacts/a/b.zip/download")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/a/b.zip", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/artifacts/a/b.zip")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/artifacts/")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/artifacts", w.Header().Get("Location"))
+}
+
 func TestRouteServeErrorWithWriteHeader(t *testing.T) {
 	route := New()
 	route.Use(func(c *Context) {
@@ -737,3 +948,74 @@ func TestEngineHandleMethodNotAllowedCornerCase(t *testing.T) {
 	w := PerformRequest(r, "GET", "/base/v1/user/groups")
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestRouteStrictStaticPriority(t *testing.T) {
+	routes := []string{
+		"/users/new",
+		"/users/:id/edit",
+		"/posts/recent/list",
+		"/posts/:id",
+	}
+	tests := []struct {
+		path          string
+		defaultRoute  string
+		strictRoute   string
+		defaultParams string
+	}{
+		{"/users/new", "/users/new", "/users/new", ""},
+		{"/users/new/edit", "/users/:id/edit", "", "new"},
+		{"/users/newer/edit", "/users/:id/edit", "/users/:id/edit", "newer"},
+		{"/users/ne/edit", "/users/:id/edit", "/users/:id/edit", "ne"},
+		{"/posts/recent", "/posts/:id", "", "recent"},
+		{"/posts/rec", "/posts/:id", "/posts/:id", "rec"},
+	}
+
+	for _, strict := range []bool{false, true} {
+		router := New()
+		router.StrictStaticPriority = strict
+		for _, route := range routes {
+			route := route
+			router.GET(route, func(c *Context) {
+				value := ""
+				if len(c.Params) > 0 {
+					value = c.Params[0].Value
+				}
+				c.String(http.StatusOK, "%s %s", route, value)
+			})
+		}
+		m := router.Matcher()
+
+		for _, tt := range tests {
+			want := tt.defaultRoute
+			if strict {
+				want = tt.strictRoute
+			}
+			w := PerformRequest(router, http.MethodGet, tt.path)
+			route, _, ok := m.Match(http.MethodGet, tt.path)
+			if want == "" {
+				assert.Equal(t, http.StatusNotFound, w.Code, "strict=%v path=%s", strict, tt.path)
+				assert.False(t, ok, "strict=%v path=%s", strict, tt.path)
+				continue
+			}
+			assert.Equal(t, http.StatusOK, w.Code, "strict=%v path=%s", strict, tt.path)
+			assert.Equal(t, want+" "+tt.defaultParams, w.Body.String(), "strict=%v path=%s", strict, tt.path)
+			assert.True(t, ok)
+			assert.Equal(t, want, route.Path)
+		}
+	}
+}
+
+func TestRouteLookupZeroAllocs(t *testing.T) {
+	router := New()
+	router.GET("/ping", func(_ *Context) {})
+	router.GET("/users/:id/posts/:post", func(_ *Context) {})
+	router.GET("/static/:name/", func(_ *Context) {})
+	router.GET("/static/a/b", func(_ *Context) {})
+
+	w := newMockWriter()
+	for _, path := range []string{"/ping", "/users/1/posts/2", "/static/x/", "/missing"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		allocs := testing.AllocsPerRun(100, func() { router.ServeHTTP(w, req) })
+		assert.Zero(t, allocs, path)
+	}
+}
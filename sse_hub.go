@@ -0,0 +1,257 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+)
+
+// 每个客户端默认缓存的事件数
+const defaultSSEHubBufferSize = 16
+
+var (
+	// 客户端的缓存已满，被hub断开连接
+	ErrSSEClientEvicted = errors.New("gin: sse client evicted because it is too slow")
+	// hub已经关闭
+	ErrSSEHubClosed = errors.New("gin: sse hub closed")
+)
+
+// SSEHubConfig SSEHub的配置
+type SSEHubConfig struct {
+	// 每个客户端缓存的事件数，缓存已满时断开该客户端，默认为16
+	BufferSize int
+
+	// 大于0时，每隔该时间向客户端发送一个注释行保持连接
+	KeepAlive time.Duration
+
+	// 大于0时限制写入单个事件的时间，超时后断开该客户端
+	WriteTimeout time.Duration
+}
+
+// SSEHubStats SSEHub的统计
+type SSEHubStats struct {
+	// 当前连接的客户端数
+	Clients int
+	// 每个topic的订阅数
+	Topics map[string]int
+	// 累计发布的事件数和投递的次数
+	Published uint64
+	Delivered uint64
+	// 累计因为缓存已满被断开的客户端数
+	Evicted uint64
+}
+
+// SSEHub 按topic向多个SSE客户端广播事件，并发安全；
+// 每个客户端有独立的缓存，Publish不会被慢客户端阻塞
+type SSEHub struct {
+	conf      SSEHubConfig
+	mu        sync.Mutex
+	topics    map[string]map[*sseClient]struct{}
+	clients   map[*sseClient]struct{}
+	done      chan struct{}
+	closed    bool
+	published uint64
+	delivered uint64
+	evicted   uint64
+}
+
+// 一个订阅的客户端
+type sseClient struct {
+	topics  []string
+	events  chan []byte
+	evicted chan struct{}
+}
+
+// 返回SSEHub，未设置的配置使用默认值
+func NewSSEHub(conf SSEHubConfig) *SSEHub {
+	if conf.BufferSize <= 0 {
+		conf.BufferSize = defaultSSEHubBufferSize
+	}
+	return &SSEHub{
+		conf:    conf,
+		topics:  make(map[string]map[*sseClient]struct{}),
+		clients: make(map[*sseClient]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// 向topic的全部订阅者发布事件，返回投递的客户端数；缓存已满的客户端会被断开。
+// 事件只编码一次，hub关闭后返回ErrSSEHubClosed
+func (h *SSEHub) Publish(topic string, event sse.Event) (int, error) {
+	var buf bytes.Buffer
+	if err := sse.Encode(&buf, event); err != nil {
+		return 0, err
+	}
+	data := buf.Bytes()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return 0, ErrSSEHubClosed
+	}
+	h.published++
+	n := 0
+	for client := range h.topics[topic] {
+		select {
+		case client.events <- data:
+			n++
+		default:
+			h.evict(client)
+		}
+	}
+	h.delivered += uint64(n)
+	return n, nil
+}
+
+// 关闭hub，已经订阅的客户端发送完缓存中的事件后结束，之后的Publish和订阅都会返回ErrSSEHubClosed
+func (h *SSEHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+}
+
+// 返回hub当前的统计
+func (h *SSEHub) Snapshot() SSEHubStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	topics := make(map[string]int, len(h.topics))
+	for topic, clients := range h.topics {
+		topics[topic] = len(clients)
+	}
+	return SSEHubStats{
+		Clients:   len(h.clients),
+		Topics:    topics,
+		Published: h.published,
+		Delivered: h.delivered,
+		Evicted:   h.evicted,
+	}
+}
+
+// 添加订阅topics的客户端
+func (h *SSEHub) subscribe(topics []string) (*sseClient, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrSSEHubClosed
+	}
+	client := &sseClient{
+		topics:  topics,
+		events:  make(chan []byte, h.conf.BufferSize),
+		evicted: make(chan struct{}),
+	}
+	h.clients[client] = struct{}{}
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*sseClient]struct{})
+		}
+		h.topics[topic][client] = struct{}{}
+	}
+	return client, nil
+}
+
+// 移除客户端
+func (h *SSEHub) unsubscribe(client *sseClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(client)
+}
+
+// 断开缓存已满的客户端，调用时持有锁
+func (h *SSEHub) evict(client *sseClient) {
+	if h.remove(client) {
+		h.evicted++
+		close(client.evicted)
+	}
+}
+
+// 从hub中移除客户端，客户端不存在时返回false，调用时持有锁
+func (h *SSEHub) remove(client *sseClient) bool {
+	if _, ok := h.clients[client]; !ok {
+		return false
+	}
+	delete(h.clients, client)
+	for _, topic := range client.topics {
+		delete(h.topics[topic], client)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	return true
+}
+
+// 订阅hub的topics，并将发布的事件以SSE的格式写入响应，直到客户端断开连接、被hub断开或者hub关闭；
+// 返回结束的原因：客户端断开时为ctx的错误，写入失败时为写入的错误，
+// 被断开时为ErrSSEClientEvicted，hub关闭时为ErrSSEHubClosed
+func (c *Context) SSESubscribe(hub *SSEHub, topics ...string) error {
+	client, err := hub.subscribe(topics)
+	if err != nil {
+		return err
+	}
+	defer hub.unsubscribe(client)
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	var keepAlive <-chan time.Time
+	if hub.conf.KeepAlive > 0 {
+		ticker := time.NewTicker(hub.conf.KeepAlive)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	var reason error
+	c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+		select {
+		case data := <-client.events:
+			_, reason = w.Write(data)
+			return reason == nil
+		case <-keepAlive:
+			_, reason = io.WriteString(w, ":keepalive\n\n")
+			return reason == nil
+		case <-client.evicted:
+			reason = ErrSSEClientEvicted
+			return false
+		case <-hub.done:
+			// 发送缓存中剩余的事件
+			for {
+				select {
+				case data := <-client.events:
+					if _, reason = w.Write(data); reason != nil {
+						return false
+					}
+				default:
+					reason = ErrSSEHubClosed
+					return false
+				}
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}, StreamOptions{WriteTimeout: hub.conf.WriteTimeout})
+
+	if reason != nil {
+		return reason
+	}
+	return c.Request.Context().Err()
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/stretchr/testify/assert"
+)
+
+// 等待hub中的订阅数达到n
+func waitSSEClients(t *testing.T, hub *SSEHub, n int) {
+	assert.Eventually(t, func() bool {
+		return hub.Snapshot().Clients == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestSSEHubPublishSubscribe(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{})
+	errs := make(chan error, 1)
+	router := New()
+	router.GET("/events", func(c *Context) {
+		errs <- c.SSESubscribe(hub, "news", "sport")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, sse.ContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	waitSSEClients(t, hub, 1)
+
+	n, err := hub.Publish("news", sse.Event{Event: "headline", Data: "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = hub.Publish("weather", sse.Event{Data: "sunny"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	_, err = hub.Publish("sport", sse.Event{Id: "2", Data: "goal"})
+	assert.NoError(t, err)
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 6 {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return
+		}
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"event:headline\n", "data:hello\n", "\n", "id:2\n", "data:goal\n", "\n"}, lines)
+
+	stats := hub.Snapshot()
+	assert.Equal(t, map[string]int{"news": 1, "sport": 1}, stats.Topics)
+	assert.Equal(t, uint64(3), stats.Published)
+	assert.Equal(t, uint64(2), stats.Delivered)
+
+	resp.Body.Close()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	waitSSEClients(t, hub, 0)
+	assert.Empty(t, hub.Snapshot().Topics)
+}
+
+func TestSSEHubIdleLongerThanWriteTimeout(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{WriteTimeout: 50 * time.Millisecond})
+	router := New()
+	router.GET("/events", func(c *Context) {
+		_ = c.SSESubscribe(hub, "news")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	waitSSEClients(t, hub, 1)
+
+	// 客户端空闲的时间超过WriteTimeout之后仍然可以收到事件
+	time.Sleep(200 * time.Millisecond)
+	n, err := hub.Publish("news", sse.Event{Data: "late"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data:late\n", line)
+	assert.Equal(t, 1, hub.Snapshot().Clients)
+}
+
+func TestSSEHubEvictSlowClient(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{BufferSize: 1})
+	client, err := hub.subscribe([]string{"news"})
+	assert.NoError(t, err)
+
+	n, _ := hub.Publish("news", sse.Event{Data: "1"})
+	assert.Equal(t, 1, n)
+	n, _ = hub.Publish("news", sse.Event{Data: "2"})
+	assert.Equal(t, 0, n)
+
+	select {
+	case <-client.evicted:
+	default:
+		t.Fatal("client should be evicted")
+	}
+	stats := hub.Snapshot()
+	assert.Equal(t, 0, stats.Clients)
+	assert.Equal(t, uint64(1), stats.Evicted)
+
+	// 被断开的客户端不再接收事件
+	n, _ = hub.Publish("news", sse.Event{Data: "3"})
+	assert.Equal(t, 0, n)
+	hub.unsubscribe(client)
+}
+
+func TestSSEHubSubscribeEvicted(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{BufferSize: 1})
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.SSESubscribe(hub, "news")
+	}()
+	waitSSEClients(t, hub, 1)
+	hub.mu.Lock()
+	for client := range hub.clients {
+		hub.evict(client)
+	}
+	hub.mu.Unlock()
+	assert.ErrorIs(t, <-errs, ErrSSEClientEvicted)
+}
+
+func TestSSEHubClose(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{})
+	client, err := hub.subscribe([]string{"news"})
+	assert.NoError(t, err)
+	_, err = hub.Publish("news", sse.Event{Data: "last"})
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.SSESubscribe(hub, "news")
+	}()
+	waitSSEClients(t, hub, 2)
+	_, err = hub.Publish("news", sse.Event{Data: "bye"})
+	assert.NoError(t, err)
+	hub.Close()
+	hub.Close()
+
+	// 关闭之前发布的事件仍然会被发送
+	assert.ErrorIs(t, <-errs, ErrSSEHubClosed)
+	assert.Equal(t, "data:bye\n\n", w.Body.String())
+	assert.Len(t, client.events, 2)
+
+	_, err = hub.Publish("news", sse.Event{Data: "late"})
+	assert.ErrorIs(t, err, ErrSSEHubClosed)
+	assert.ErrorIs(t, c.SSESubscribe(hub, "news"), ErrSSEHubClosed)
+}
+
+func TestSSEHubKeepAlive(t *testing.T) {
+	hub := NewSSEHub(SSEHubConfig{KeepAlive: 5 * time.Millisecond})
+	router := New()
+	router.GET("/events", func(c *Context) {
+		_ = c.SSESubscribe(hub, "news")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+	defer hub.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ":keepalive\n", line)
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 不可变资源的缓存时间，一年
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// StaticOptions 静态文件响应的缓存配置，零值时只使用http.ServeContent的默认行为（Last-Modified）
+type StaticOptions struct {
+	// 大于0时设置Cache-Control: public, max-age=N和对应的Expires
+	MaxAge time.Duration
+
+	// 不为空时替换根据MaxAge生成的Cache-Control，Expires仍然根据MaxAge设置
+	CacheControl string
+
+	// 返回文件是否为带有指纹的不可变资源，例如app.3f2a9c1b.js，匹配时缓存一年并添加immutable，
+	// 可以使用FingerprintedAsset
+	Immutable func(name string) bool
+
+	// 为true时根据文件大小和修改时间生成弱ETag，请求携带匹配的If-None-Match时返回304
+	ETag bool
+
+	// 为true时，如果Accept-Encoding允许并且存在同名的.br或.gz文件，返回预先压缩的文件，
+	// 并设置Content-Encoding和Vary: Accept-Encoding
+	Precompressed bool
+
+	// 请求目录时依次查找的index文件，为空时使用http.FileServer的默认行为（index.html）
+	IndexFiles []string
+
+	// 文件不存在时调用，响应状态码已经设置为404；为nil时执行NoRoute的handler
+	NotFound HandlerFunc
+}
+
+// 预先压缩的文件的扩展名，按优先级排序
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// 是否需要设置header
+func (opts StaticOptions) enabled() bool {
+	return opts.MaxAge > 0 || opts.CacheControl != "" || opts.Immutable != nil || opts.ETag || opts.Precompressed || len(opts.IndexFiles) > 0
+}
+
+// 返回目录dir中第一个存在的index文件，没有index文件时返回false，由http.FileServer处理
+func (opts StaticOptions) serveIndex(c *Context, fs http.FileSystem, dir string) bool {
+	for _, index := range opts.IndexFiles {
+		name := path.Join(dir, index)
+		f, err := fs.Open(name)
+		if err != nil {
+			continue
+		}
+		stat, err := f.Stat()
+		if err != nil || stat.IsDir() {
+			f.Close()
+			continue
+		}
+		if opts.Precompressed && opts.servePrecompressed(c, fs, name) {
+			f.Close()
+			return true
+		}
+		opts.setHeaders(c.Writer.Header(), name, stat)
+		c.ServeContent(name, stat.ModTime(), f)
+		f.Close()
+		return true
+	}
+	return false
+}
+
+// 返回预先压缩的文件，没有可以使用的压缩文件时返回false，由http.FileServer返回原文件
+func (opts StaticOptions) servePrecompressed(c *Context, fs http.FileSystem, name string) bool {
+	// 压缩文件无法探测Content-Type，只处理扩展名已知的文件
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		return false
+	}
+	header := c.Writer.Header()
+	header.Add("Vary", "Accept-Encoding")
+	acceptEncoding := c.requestHeader("Accept-Encoding")
+	for _, enc := range precompressedEncodings {
+		if !acceptsEncoding(acceptEncoding, enc.encoding) {
+			continue
+		}
+		f, err := fs.Open(name + enc.ext)
+		if err != nil {
+			continue
+		}
+		stat, err := f.Stat()
+		if err != nil || stat.IsDir() {
+			f.Close()
+			continue
+		}
+		opts.setHeaders(header, name, stat)
+		header.Set("Content-Type", ctype)
+		header.Set("Content-Encoding", enc.encoding)
+		c.ServeContent(name, stat.ModTime(), f)
+		f.Close()
+		return true
+	}
+	return false
+}
+
+// 返回Accept-Encoding是否允许encoding，q=0表示不允许
+func acceptsEncoding(acceptEncoding, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		if !strings.EqualFold(name, encoding) && name != "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "q") {
+				if v, err := strconv.ParseFloat(value, 64); err == nil {
+					q = v
+				}
+			}
+		}
+		// 明确指定的encoding优先于*
+		if strings.EqualFold(name, encoding) {
+			return q > 0
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
+
+// 根据配置为文件设置缓存相关的header
+func (opts StaticOptions) setHeaders(header http.Header, name string, stat os.FileInfo) {
+	switch {
+	case opts.Immutable != nil && opts.Immutable(name):
+		header.Set("Cache-Control", "public, max-age="+strconv.FormatInt(int64(immutableMaxAge/time.Second), 10)+", immutable")
+		header.Set("Expires", time.Now().Add(immutableMaxAge).UTC().Format(http.TimeFormat))
+	case opts.CacheControl != "" || opts.MaxAge > 0:
+		cacheControl := opts.CacheControl
+		if cacheControl == "" {
+			cacheControl = "public, max-age=" + strconv.FormatInt(int64(opts.MaxAge/time.Second), 10)
+		}
+		header.Set("Cache-Control", cacheControl)
+		if opts.MaxAge > 0 {
+			header.Set("Expires", time.Now().Add(opts.MaxAge).UTC().Format(http.TimeFormat))
+		}
+	}
+	if opts.ETag && header.Get("ETag") == "" {
+		header.Set("ETag", `W/"`+strconv.FormatInt(stat.Size(), 16)+"-"+strconv.FormatInt(stat.ModTime().UnixNano(), 16)+`"`)
+	}
+}
+
+// 返回文件名中是否包含至少8位十六进制的指纹，例如app.3f2a9c1b.js、app-3f2a9c1b.css
+func FingerprintedAsset(name string) bool {
+	base := path.Base(name)
+	if ext := path.Ext(base); ext != "" {
+		base = strings.TrimSuffix(base, ext)
+	}
+	parts := strings.FieldsFunc(base, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	})
+	// 第一段是文件名本身
+	for i := 1; i < len(parts); i++ {
+		if isHexFingerprint(parts[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// 至少8位的十六进制字符串
+func isHexFingerprint(s string) bool {
+	if len(s) < 8 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
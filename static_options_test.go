@@ -0,0 +1,187 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprintedAsset(t *testing.T) {
+	for name, want := range map[string]bool{
+		"/js/app.3f2a9c1b.js":        true,
+		"app-3F2A9C1B4D.css":         true,
+		"chunk_0123abcd.min.js":      true,
+		"/vendor.deadbeef99.min.js":  true,
+		"app.js":                     false,
+		"deadbeef.js":                false,
+		"app.3f2a9c.js":              false,
+		"my-component.js":            false,
+		"/img/logo.3f2a9c1b/file.js": false,
+	} {
+		assert.Equal(t, want, FingerprintedAsset(name), name)
+	}
+}
+
+func TestRouteStaticWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "page.html"), []byte("<html></html>"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.3f2a9c1b.js"), []byte("console.log(1)"), 0o600))
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "page.html"), modTime, modTime))
+
+	router := New()
+	router.StaticWithOptions("/assets", dir, StaticOptions{
+		MaxAge:    time.Hour,
+		Immutable: FingerprintedAsset,
+		ETag:      true,
+	})
+	router.StaticFSWithOptions("/nocache", Dir(dir, false), StaticOptions{CacheControl: "no-cache"})
+	router.Static("/plain", dir)
+
+	w := PerformRequest(router, http.MethodGet, "/assets/page.html")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+	expires, err := http.ParseTime(w.Header().Get("Expires"))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expires, 5*time.Second)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, `W/"d-`+formatHex(modTime.UnixNano())+`"`, etag)
+
+	w = PerformRequest(router, http.MethodGet, "/assets/page.html", header{Key: "If-None-Match", Value: etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/assets/app.3f2a9c1b.js")
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = PerformRequest(router, http.MethodHead, "/nocache/page.html")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("Expires"))
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	w = PerformRequest(router, http.MethodGet, "/plain/page.html")
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+	// 目录和不存在的文件不设置缓存header
+	w = PerformRequest(router, http.MethodGet, "/assets/")
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	w = PerformRequest(router, http.MethodGet, "/assets/missing.js")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func formatHex(n int64) string {
+	return strconv.FormatInt(n, 16)
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	assert.True(t, acceptsEncoding("gzip, deflate, br", "br"))
+	assert.True(t, acceptsEncoding("GZIP;q=0.5", "gzip"))
+	assert.True(t, acceptsEncoding("*", "br"))
+	assert.False(t, acceptsEncoding("", "gzip"))
+	assert.False(t, acceptsEncoding("gzip;q=0", "gzip"))
+	assert.False(t, acceptsEncoding("*, br;q=0", "br"))
+	assert.True(t, acceptsEncoding("*;q=0, br", "br"))
+	assert.False(t, acceptsEncoding("identity", "gzip"))
+}
+
+func TestRouteStaticPrecompressed(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("console.log(1)"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js.br"), []byte("brotli"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js.gz"), []byte("gzipped"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "style.css"), []byte("body{}"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "style.css.gz"), []byte("gzipped css"), 0o600))
+
+	router := New()
+	router.StaticWithOptions("/assets", dir, StaticOptions{Precompressed: true, ETag: true})
+	router.Static("/plain", dir)
+
+	w := PerformRequest(router, http.MethodGet, "/assets/app.js", header{Key: "Accept-Encoding", Value: "gzip, br"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "brotli", w.Body.String())
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+	brETag := w.Header().Get("ETag")
+
+	w = PerformRequest(router, http.MethodGet, "/assets/app.js", header{Key: "Accept-Encoding", Value: "gzip"})
+	assert.Equal(t, "gzipped", w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.NotEqual(t, brETag, w.Header().Get("ETag"))
+
+	w = PerformRequest(router, http.MethodGet, "/assets/style.css", header{Key: "Accept-Encoding", Value: "br"})
+	assert.Equal(t, "body{}", w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	w = PerformRequest(router, http.MethodGet, "/assets/app.js")
+	assert.Equal(t, "console.log(1)", w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w = PerformRequest(router, http.MethodGet, "/plain/app.js", header{Key: "Accept-Encoding", Value: "br"})
+	assert.Equal(t, "console.log(1)", w.Body.String())
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestRouteStaticIndexFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "docs"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "default.htm"), []byte("default"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "home.html"), []byte("home"), 0o600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0o700))
+
+	router := New()
+	router.StaticWithOptions("/site", dir, StaticOptions{
+		IndexFiles: []string{"home.html", "default.htm"},
+		MaxAge:     time.Minute,
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/site/docs/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "home", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+	// 没有'/'结尾时由http.FileServer重定向
+	w = PerformRequest(router, http.MethodGet, "/site/docs")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	// 没有index文件时由http.FileServer处理，Static不允许列出目录
+	w = PerformRequest(router, http.MethodGet, "/site/empty/")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouteStaticNotFound(t *testing.T) {
+	dir := t.TempDir()
+	router := New()
+	router.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "no route")
+	})
+	router.StaticWithOptions("/custom", dir, StaticOptions{
+		NotFound: func(c *Context) {
+			c.String(http.StatusNotFound, "missing %s", c.Param("filepath"))
+		},
+	})
+	router.Static("/default", dir)
+
+	w := PerformRequest(router, http.MethodGet, "/custom/a.js")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "missing /a.js", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/default/a.js")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "no route", w.Body.String())
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StreamOptions StreamWithOptions的配置
+type StreamOptions struct {
+	// 大于0时按照该间隔在后台刷新响应，step可以一直阻塞等待事件；为0时每次调用step之后刷新
+	FlushInterval time.Duration
+
+	// 大于0时每次写入和刷新时设置写入的deadline，完成之后清除，step阻塞等待事件的时间不计算在内；
+	// 写入超时后结束stream，底层的ResponseWriter不支持时忽略
+	WriteTimeout time.Duration
+
+	// 大于0时限制stream的总时长，到期后传给step的ctx被取消
+	MaxDuration time.Duration
+}
+
+// 与Stream相同，step接收的ctx在客户端断开连接、超过MaxDuration或者写入失败时被取消，
+// 可以在step中通过select同时等待事件、心跳定时器和ctx；
+// 返回true表示stream因为ctx取消或者写入失败而结束，返回false表示step返回了false
+func (c *Context) StreamWithOptions(step func(ctx context.Context, w io.Writer) bool, opts StreamOptions) bool {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	if opts.MaxDuration > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, opts.MaxDuration)
+		defer cancelTimeout()
+	}
+
+	w := &streamWriter{w: c.Writer, cancel: cancel, timeout: opts.WriteTimeout}
+	if opts.WriteTimeout > 0 {
+		w.rc = http.NewResponseController(c.Writer)
+	}
+
+	if opts.FlushInterval > 0 {
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		defer func() {
+			close(done)
+			<-stopped
+		}()
+		go func() {
+			defer close(stopped)
+			ticker := time.NewTicker(opts.FlushInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					w.flush()
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		if ctx.Err() != nil || w.failed() {
+			return true
+		}
+		keepOpen := step(ctx, w)
+		w.flush()
+		if w.failed() {
+			return true
+		}
+		if !keepOpen {
+			return false
+		}
+	}
+}
+
+// 串行化step的写入和后台的刷新，记录第一次写入失败的错误
+type streamWriter struct {
+	mu     sync.Mutex
+	w      ResponseWriter
+	err    error
+	cancel context.CancelFunc
+	// 写入和刷新的超时时间，为0时不设置deadline
+	timeout time.Duration
+	rc      *http.ResponseController
+}
+
+// 实现io.Writer Write函数接口
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+	if err := w.setDeadline(); err != nil {
+		return 0, err
+	}
+	defer w.clearDeadline()
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.err = err
+		w.cancel()
+	}
+	return n, err
+}
+
+// 刷新已经写入的数据
+func (w *streamWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil || w.setDeadline() != nil {
+		return
+	}
+	w.w.Flush()
+	w.clearDeadline()
+}
+
+// 设置本次写入的deadline，底层的ResponseWriter不支持时忽略，其他错误结束stream
+func (w *streamWriter) setDeadline() error {
+	if w.timeout <= 0 {
+		return nil
+	}
+	err := w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		w.err = err
+		w.cancel()
+		return err
+	}
+	return nil
+}
+
+// 写入完成之后清除deadline
+func (w *streamWriter) clearDeadline() {
+	if w.timeout > 0 {
+		_ = w.rc.SetWriteDeadline(time.Time{})
+	}
+}
+
+// 返回是否写入失败
+func (w *streamWriter) failed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err != nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextStreamWithOptions(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	n := 0
+	clientGone := c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+		assert.NoError(t, ctx.Err())
+		n++
+		_, err := io.WriteString(w, "tick ")
+		assert.NoError(t, err)
+		return n < 3
+	}, StreamOptions{WriteTimeout: time.Second})
+
+	assert.False(t, clientGone)
+	assert.Equal(t, "tick tick tick ", w.Body.String())
+	assert.True(t, w.Flushed)
+}
+
+func TestContextStreamWithOptionsMaxDuration(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	start := time.Now()
+	clientGone := c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+		<-ctx.Done()
+		return true
+	}, StreamOptions{MaxDuration: 10 * time.Millisecond})
+
+	assert.True(t, clientGone)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestContextStreamWithOptionsClientGone(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	clientGone := c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+		cancel()
+		return true
+	}, StreamOptions{})
+	assert.True(t, clientGone)
+}
+
+type failingStreamWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (failingStreamWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestContextStreamWithOptionsWriteError(t *testing.T) {
+	c, _ := CreateTestContext(failingStreamWriter{httptest.NewRecorder()})
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	calls := 0
+	clientGone := c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+		calls++
+		_, err := io.WriteString(w, "data")
+		assert.Error(t, err)
+		assert.Error(t, ctx.Err())
+		return true
+	}, StreamOptions{})
+	assert.True(t, clientGone)
+	assert.Equal(t, 1, calls)
+}
+
+func TestContextStreamWithOptionsFlushInterval(t *testing.T) {
+	release := make(chan struct{})
+	router := New()
+	router.GET("/events", func(c *Context) {
+		c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+			_, _ = io.WriteString(w, "data: hello\n\n")
+			// 阻塞时由后台定时刷新
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return false
+		}, StreamOptions{FlushInterval: 5 * time.Millisecond})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: hello\n", line)
+	close(release)
+}
+
+func TestContextStreamWithOptionsWriteTimeoutIdle(t *testing.T) {
+	router := New()
+	router.GET("/stream", func(c *Context) {
+		n := 0
+		c.StreamWithOptions(func(ctx context.Context, w io.Writer) bool {
+			// 等待事件的时间超过WriteTimeout
+			time.Sleep(150 * time.Millisecond)
+			n++
+			_, err := fmt.Fprintf(w, "event %d\n", n)
+			return err == nil && n < 2
+		}, StreamOptions{WriteTimeout: 50 * time.Millisecond})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "event 1\nevent 2\n", string(body))
+}
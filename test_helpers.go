@@ -4,7 +4,11 @@
 
 package gin
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 // 用于测试的新Engine和Context
 func CreateTestContext(w http.ResponseWriter) (c *Context, r *Engine) {
@@ -22,3 +26,126 @@ func CreateTestContextOnly(w http.ResponseWriter, r *Engine) (c *Context) {
 	c.writermem.reset(w)
 	return
 }
+
+// 通过Engine的路由处理req，执行匹配到的middleware和handler并返回处理后的Context
+// 返回的Context不会放回对象池，可以检查Params、Keys、Errors和FullPath等状态
+func CreateTestContextRouted(w http.ResponseWriter, r *Engine, req *http.Request) (c *Context) {
+	c = r.allocateContext(r.maxParams)
+	r.serveContext(c, w, req)
+	return
+}
+
+// 将routes注册到一个新的路由树中并检查路由树的不变式，用于路由树的fuzz和property测试：
+//   - addRoute只会因为路由冲突或者非法路径panic，冲突的路由被忽略，不影响已注册的路由
+//   - 每个已注册的路由替换参数后都能匹配到自身，并且得到正确的参数
+//   - 去掉或者添加尾部'/'后没有匹配到路由时，tsr为true
+//   - getValue对任意路径都不会panic
+//
+// 参数会被替换为以'\x00'开头的值，routes中的静态部分不应该包含'\x00'
+// 返回第一个被破坏的不变式，以及成功注册的路由
+func CheckRouteTree(routes []string) (accepted []string, err error) {
+	root := &node{}
+	var maxParams, maxSections uint16
+	for _, route := range routes {
+		if !addRouteSafe(root, route) {
+			// addRoute在panic之前可能已经修改了路由树，使用已注册的路由重建
+			root = &node{}
+			for _, r := range accepted {
+				root.addRoute(r, fakeRouteHandlers(r))
+			}
+			continue
+		}
+		accepted = append(accepted, route)
+		if n := countParams(route); n > maxParams {
+			maxParams = n
+		}
+		if n := countSections(route); n > maxSections {
+			maxSections = n
+		}
+	}
+
+	lookup := func(path string) (value nodeValue, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("getValue(%q) panicked: %v", path, r)
+			}
+		}()
+		params := make(Params, 0, maxParams)
+		skipped := make([]skippedNode, 0, maxSections)
+		value = root.getValue(path, &params, &skipped, false)
+		if value.params != nil {
+			value.params = &params
+		}
+		return value, nil
+	}
+
+	registered := make(map[string]bool, len(accepted))
+	for _, route := range accepted {
+		registered[route] = true
+	}
+
+	for _, route := range accepted {
+		path, want := concreteRoutePath(route)
+		value, err := lookup(path)
+		if err != nil {
+			return accepted, err
+		}
+		if value.handlers == nil {
+			return accepted, fmt.Errorf("route %q: path %q not found", route, path)
+		}
+		if value.fullPath != route {
+			return accepted, fmt.Errorf("route %q: path %q matched %q", route, path, value.fullPath)
+		}
+		var got Params
+		if value.params != nil {
+			got = *value.params
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return accepted, fmt.Errorf("route %q: path %q got params %v, want %v", route, path, got, want)
+		}
+
+		// 包含catch-all的路由总是匹配尾部'/'，不检查tsr
+		if route == "/" || strings.Contains(route, "*") {
+			continue
+		}
+		other := strings.TrimSuffix(path, "/")
+		if other == path {
+			other = path + "/"
+		}
+		value, err = lookup(other)
+		if err != nil {
+			return accepted, err
+		}
+		if value.handlers == nil && !value.tsr {
+			return accepted, fmt.Errorf("route %q: path %q not found without trailing slash recommendation", route, other)
+		}
+	}
+
+	// 任意路径都不会panic
+	for _, route := range routes {
+		for _, path := range []string{route, route + "/", strings.TrimSuffix(route, "/"), "/" + route, route + "x"} {
+			if _, err := lookup(path); err != nil {
+				return accepted, err
+			}
+		}
+	}
+	return accepted, nil
+}
+
+// 注册路由，发生panic时返回false
+func addRouteSafe(root *node, route string) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	if route == "" || route[0] != '/' {
+		return false
+	}
+	root.addRoute(route, fakeRouteHandlers(route))
+	return true
+}
+
+func fakeRouteHandlers(route string) HandlersChain {
+	return HandlersChain{func(c *Context) { c.String(http.StatusOK, route) }}
+}
@@ -126,6 +126,8 @@ type node struct {
 	wildChild bool
 	// node类型
 	nType nodeType
+	// 路径中间的catch-all node之后的路由包含的最多'/'数量，用于限制catch-all值的拆分位置
+	maxSections uint16
 	// 当前node的优先级
 	priority uint32
 	children []*node // child nodes, at most 1 :param style node at the end of the array
@@ -159,6 +161,12 @@ func (n *node) incrementChildPrio(pos int) int {
 // 添加一个所给handler的node到path中，非线程安全
 func (n *node) addRoute(path string, handlers HandlersChain) {
 	fullPath := path
+	// catch-all在路径中间时，之后不能再有catch-all，否则匹配时间随路径长度平方增长
+	if i := strings.Index(path, "/*"); i >= 0 {
+		if j := strings.IndexByte(path[i+2:], '/'); j >= 0 && strings.Contains(path[i+2+j:], "/*") {
+			panic("catch-all wildcard in the middle of path '" + fullPath + "' can not be followed by another catch-all")
+		}
+	}
 	// 每添加一个node，优先级++，node越多，优先级越高
 	n.priority++
 
@@ -228,7 +236,8 @@ walk:
 			}
 
 			// 添加新node
-			if c != ':' && c != '*' && n.nType != catchAll {
+			// catch-all的值node可以有以'/'开头的子node，例如/repos/*path/commits
+			if c != ':' && c != '*' && (n.nType != catchAll || n.path != "") {
 				// 将c添加到当前node的indices
 				n.indices += bytesconv.BytesToString([]byte{c})
 				child := &node{
@@ -253,6 +262,15 @@ walk:
 					continue walk
 				}
 
+				// 相同的catch-all，继续添加catch-all之后的路径或者catch-all本身的handler
+				if n.nType == catchAll && n.path == path[:min(len(n.path), len(path))] &&
+					(len(n.path) == len(path) || path[len(n.path)] == '/') {
+					if sections := countSections(path[len(n.path):]); sections > n.maxSections {
+						n.maxSections = sections
+					}
+					continue walk
+				}
+
 				// 通配符冲突
 				pathSeg := path
 				// 找到冲突的通配符位置，只取'/'分割的首个冲突位置
@@ -365,10 +383,8 @@ func (n *node) insertChild(path string, fullPath string, handlers HandlersChain)
 			return
 		}
 
-		// 处理wildcard为catchAll的情况
-		if i+len(wildcard) != len(path) { // '*'只能在path的末尾
-			panic("catch-all routes are only allowed at the end of the path in path '" + fullPath + "'")
-		}
+		// 处理wildcard为catchAll的情况，catch-all之后的路径保存在catch-all node的子node中
+		end := i + len(wildcard)
 
 		if len(n.path) > 0 && n.path[len(n.path)-1] == '/' { // TODO
 			pathSeg := strings.SplitN(n.children[0].path, "/", 2)[0]
@@ -403,16 +419,30 @@ func (n *node) insertChild(path string, fullPath string, handlers HandlersChain)
 
 		// catchAll的node存储变量值，nType为catchAll
 		child = &node{
-			path:     path[i:],
-			nType:    catchAll,
-			handlers: handlers,
-			priority: 1,
-			fullPath: fullPath,
+			path:        path[i:end],
+			nType:       catchAll,
+			maxSections: countSections(path[end:]),
+			priority:    1,
+			fullPath:    fullPath,
 		}
 		// 将存储变量信息的node添加到空catchAll node的children
 		n.children = []*node{child}
+		n = child
 
-		return
+		if end == len(path) {
+			n.handlers = handlers
+			return
+		}
+
+		// catch-all在路径中间，之后的路径以'/'开头，作为catch-all node的子node继续插入
+		n.indices = string('/')
+		child = &node{
+			priority: 1,
+			fullPath: fullPath,
+		}
+		n.addChild(child)
+		n = child
+		path = path[end:]
 	}
 
 	// 没有找到通配符，则只插入path和handle
@@ -439,7 +469,39 @@ type skippedNode struct {
 // 返回path注册的handle。通配符的值保存到map中。
 // 如果没有找到handle，如果path存在带有额外（不带）尾部'/'的handle，则tsr为trur
 func (n *node) getValue(path string, params *Params, skippedNodes *[]skippedNode, unescape bool) (value nodeValue) {
+	var opts lookupOptions
+	if unescape {
+		opts.unescape = url.QueryUnescape
+	}
+	return n.getValueWithOptions(path, params, skippedNodes, opts)
+}
+
+// 查找路由的配置
+type lookupOptions struct {
+	// 不为nil时使用该函数解码参数值，解码失败时保留原值
+	unescape func(string) (string, error)
+
+	// 为true时，如果静态子node存在与请求完全相同的路径段，
+	// 即使静态路由的后续部分没有匹配，也不会回滚到同级的参数或者catch-all node
+	strictStatic bool
+}
+
+// 与getValue相同，通过opts设置参数的解码方式和静态路径的优先级
+func (n *node) getValueWithOptions(path string, params *Params, skippedNodes *[]skippedNode, opts lookupOptions) nodeValue {
+	value, skippedTSR := n.lookup(path, params, skippedNodes, opts)
+	// 回滚之前的node有带尾部'/'的handler，回滚后没有找到handler时仍然推荐重定向
+	if skippedTSR && value.handlers == nil {
+		value.tsr = true
+	}
+	return value
+}
+
+// 查找path对应的handler，skippedTSR表示回滚之前的node是否有带尾部'/'的handler
+// 不使用defer设置value.tsr，避免value逃逸到堆上
+func (n *node) lookup(path string, params *Params, skippedNodes *[]skippedNode, opts lookupOptions) (value nodeValue, skippedTSR bool) {
 	var globalParamsCount int16
+	// 进入子node之前最近的path不为空的node，用于catch-all的尾部'/'推荐
+	var parent *node
 
 walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 	for {
@@ -473,6 +535,9 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 						}
 
 						// 继续遍历子node
+						if n.path != "" {
+							parent = n
+						}
 						n = n.children[i]
 						continue walk
 					}
@@ -486,6 +551,9 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 							skippedNode := (*skippedNodes)[length-1]
 							*skippedNodes = (*skippedNodes)[:length-1]
 							if strings.HasSuffix(skippedNode.path, path) {
+								if opts.strictStatic && skippedNode.staticWins() {
+									continue
+								}
 								path = skippedNode.path
 								n = skippedNode.node
 								if value.params != nil {
@@ -524,8 +592,8 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 						i := len(*value.params)
 						*value.params = (*value.params)[:i+1]
 						val := path[:end]
-						if unescape {
-							if v, err := url.QueryUnescape(val); err == nil {
+						if opts.unescape != nil {
+							if v, err := opts.unescape(val); err == nil {
 								val = v
 							}
 						}
@@ -539,6 +607,7 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 					if end < len(path) {
 						if len(n.children) > 0 {
 							path = path[end:]
+							parent = n
 							n = n.children[0]
 							continue walk
 						}
@@ -561,6 +630,18 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 					return
 
 				case catchAll:
+					// catch-all之后还有路由时，优先匹配之后的路由
+					if len(n.children) > 0 {
+						if v, ok := n.getCatchAllChildValue(path, params, skippedNodes, opts); ok {
+							return v, skippedTSR
+						}
+						if n.handlers == nil {
+							// 请求为catch-all之前的路径加上'/'时，推荐去掉尾部'/'
+							value.tsr = path == "/" && parent != nil && parent.handlers != nil
+							return
+						}
+					}
+
 					// 处理catchAll的节点
 					if params != nil {
 						if value.params == nil {
@@ -570,8 +651,8 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 						i := len(*value.params)
 						*value.params = (*value.params)[:i+1]
 						val := path
-						if unescape {
-							if v, err := url.QueryUnescape(path); err == nil {
+						if opts.unescape != nil {
+							if v, err := opts.unescape(path); err == nil {
 								val = v
 							}
 						}
@@ -596,10 +677,17 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 			// 如果当前path不等于'/'，且node没有handler，且最近匹配的node有children
 			// 当前node需要回滚到最后一个有效的skippedNode
 			if n.handlers == nil && path != "/" {
+				// 回滚之前记录当前node是否有带尾部'/'的handler，回滚后没有找到handler时仍然推荐重定向
+				if !skippedTSR && len(*skippedNodes) > 0 && n.hasTrailingSlashHandler() {
+					skippedTSR = true
+				}
 				for length := len(*skippedNodes); length > 0; length-- {
 					skippedNode := (*skippedNodes)[length-1]
 					*skippedNodes = (*skippedNodes)[:length-1]
 					if strings.HasSuffix(skippedNode.path, path) {
+						if opts.strictStatic && skippedNode.staticWins() {
+							continue
+						}
 						path = skippedNode.path
 						n = skippedNode.node
 						if value.params != nil {
@@ -629,15 +717,7 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 			}
 
 			// 没有找到handler，检查是否存在此路径的handler + 尾部'/'
-			for i, c := range []byte(n.indices) {
-				if c == '/' {
-					n = n.children[i]
-					value.tsr = (len(n.path) == 1 && n.handlers != nil) ||
-						(n.nType == catchAll && n.children[0].handlers != nil)
-					return
-				}
-			}
-
+			value.tsr = n.hasTrailingSlashHandler()
 			return
 		}
 
@@ -652,6 +732,9 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 				skippedNode := (*skippedNodes)[length-1]
 				*skippedNodes = (*skippedNodes)[:length-1]
 				if strings.HasSuffix(skippedNode.path, path) {
+					if opts.strictStatic && skippedNode.staticWins() {
+						continue
+					}
 					path = skippedNode.path
 					n = skippedNode.node
 					if value.params != nil {
@@ -667,6 +750,132 @@ walk: // 直到找到匹配的路径或没有更多节点可遍历为止
 	}
 }
 
+// 匹配catch-all之后的路由，从左向右尝试将path分为catch-all的值和剩余的路径，
+// catch-all的值包含尽可能少的路径段（至少一个），剩余的路径按照静态路径优先的规则匹配之后的路由。
+// catch-all之后的路由不包含catch-all，剩余的路径最多包含maxSections个'/'，因此只需要尝试最后几个拆分位置
+func (n *node) getCatchAllChildValue(path string, params *Params, skippedNodes *[]skippedNode, opts lookupOptions) (nodeValue, bool) {
+	count := 0
+	if params != nil {
+		count = len(*params)
+	}
+	// 子树使用skippedNodes中剩余的空间，回滚时不会回到catch-all之前的node
+	saved := *skippedNodes
+	defer func() { *skippedNodes = saved }()
+
+	// 剩余的路径中'/'的数量
+	remaining := int(countSections(path))
+	for k := 0; k < len(path); k++ {
+		if path[k] != '/' {
+			continue
+		}
+		sections := remaining
+		remaining--
+		if k == 0 || sections > int(n.maxSections) {
+			continue
+		}
+		for _, child := range n.children {
+			if params != nil {
+				*params = append((*params)[:count], Param{Key: n.path[2:], Value: path[:k]})
+			}
+			*skippedNodes = saved[len(saved):]
+			value := child.getValueWithOptions(path[k:], params, skippedNodes, opts)
+			if value.handlers != nil {
+				if params != nil {
+					// 匹配之后再解码catch-all的值，避免每个拆分位置都解码一次
+					if opts.unescape != nil {
+						if v, err := opts.unescape(path[:k]); err == nil {
+							(*params)[count].Value = v
+						}
+					}
+					value.params = params
+				}
+				return value, true
+			}
+		}
+	}
+	if params != nil {
+		*params = (*params)[:count]
+	}
+	return nodeValue{}, false
+}
+
+// 解码参数值，保留%2F和%25的编码，结果中的%2F只能来自请求中编码的'/'
+func unescapeKeepSlash(s string) (string, error) {
+	var b strings.Builder
+	start := 0
+	for i := 0; i+2 < len(s); {
+		if s[i] == '%' && (s[i+1:i+3] == "25" || strings.EqualFold(s[i+1:i+3], "2f")) {
+			v, err := url.QueryUnescape(s[start:i])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			b.WriteString(strings.ToUpper(s[i : i+3]))
+			i += 3
+			start = i
+			continue
+		}
+		i++
+	}
+	v, err := url.QueryUnescape(s[start:])
+	if err != nil {
+		return "", err
+	}
+	b.WriteString(v)
+	return b.String(), nil
+}
+
+// 跳过的node的静态子node中是否存在与请求完全相同的路径段
+func (s skippedNode) staticWins() bool {
+	segment := s.path[len(s.node.path):]
+	if i := strings.IndexByte(segment, '/'); i >= 0 {
+		segment = segment[:i]
+	}
+	return segment != "" && s.node.hasStaticSegment(segment)
+}
+
+// 从node的静态子node开始，是否存在以segment作为完整路径段的路由
+// skippedNode中保存的node没有indices，因此直接遍历children
+func (n *node) hasStaticSegment(segment string) bool {
+walk:
+	for {
+		children := n.children
+		if n.wildChild {
+			children = children[:len(children)-1]
+		}
+		for _, child := range children {
+			if child.path == "" || child.path[0] != segment[0] {
+				continue
+			}
+			l := longestCommonPrefix(segment, child.path)
+			switch {
+			case l == len(segment) && l < len(child.path):
+				return child.path[l] == '/'
+			case l == len(segment):
+				return child.handlers != nil || strings.IndexByte(child.indices, '/') >= 0
+			case l < len(child.path):
+				return false
+			}
+			segment = segment[l:]
+			n = child
+			continue walk
+		}
+		return false
+	}
+}
+
+// node的'/'子node是否有handler，即node的path加上尾部'/'后存在handler
+func (n *node) hasTrailingSlashHandler() bool {
+	for i, c := range []byte(n.indices) {
+		if c == '/' {
+			child := n.children[i]
+			return (len(child.path) == 1 && child.handlers != nil) ||
+				(child.nType == catchAll && child.children[0].handlers != nil)
+		}
+	}
+	return false
+}
+
 // 对给定的path进行不区分大小写的查找，可以选择性的修复尾部'/'
 // 返回大小写更正后的路径和一个布尔值
 func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) ([]byte, bool) {
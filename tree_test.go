@@ -6,6 +6,7 @@ package gin
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
 	"regexp"
 	"strings"
@@ -506,15 +507,52 @@ func TestEmptyWildcardName(t *testing.T) {
 
 func TestTreeCatchAllConflict(t *testing.T) {
 	routes := []testRoute{
-		{"/src/*filepath/x", true},
+		{"/src/*filepath/x", false},
 		{"/src2/", false},
 		{"/src2/*filepath/x", true},
 		{"/src3/*filepath", false},
-		{"/src3/*filepath/x", true},
+		{"/src3/*filepath/x", false},
+		{"/src3/*filepathx/y", true},
+		{"/src3/*other/y", true},
+		{"/src4/*filepath/:id", false},
+		{"/src4/*filepath/:name", true},
+		{"/src5/*a/*b", true},
+		{"/src6/*a/x/*b/y", true},
+		{"/src6/*a/x/*b", true},
+		{"/src7/*a/x", false},
+		{"/src7/*a/x/*b", true},
 	}
 	testRoutes(t, routes)
 }
 
+func TestTreeMidCatchAll(t *testing.T) {
+	tree := &node{}
+	routes := [...]string{
+		"/repos/*path/commits/:sha",
+		"/repos/*path/tags",
+		"/repos/*path",
+		"/files/*path/raw",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, fakeHandler(route))
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/repos/gin/commits/abc", false, "/repos/*path/commits/:sha", Params{{"path", "/gin"}, {"sha", "abc"}}},
+		{"/repos/a/b/c/commits/abc", false, "/repos/*path/commits/:sha", Params{{"path", "/a/b/c"}, {"sha", "abc"}}},
+		{"/repos/a/commits/b/commits/c", false, "/repos/*path/commits/:sha", Params{{"path", "/a/commits/b"}, {"sha", "c"}}},
+		{"/repos/a/b/tags", false, "/repos/*path/tags", Params{{"path", "/a/b"}}},
+		{"/repos/a/b/commits", false, "/repos/*path", Params{{"path", "/a/b/commits"}}},
+		{"/repos/commits/abc", false, "/repos/*path", Params{{"path", "/commits/abc"}}},
+		{"/repos/tags", false, "/repos/*path", Params{{"path", "/tags"}}},
+		{"/files/a/b/raw", false, "/files/*path/raw", Params{{"path", "/a/b"}}},
+		{"/files/a/b", true, "", nil},
+		{"/files/raw", true, "", nil},
+	})
+
+	checkPriorities(t, tree)
+}
+
 func TestTreeCatchAllConflictRoot(t *testing.T) {
 	routes := []testRoute{
 		{"/", false},
@@ -941,3 +979,96 @@ func TestTreeWildcardConflictEx(t *testing.T) {
 		}
 	}
 }
+
+// 随机生成由静态段、参数和catch-all组成的路由
+func randomRoutes(r *rand.Rand, n int) []string {
+	statics := []string{"a", "ab", "b", "users", "user", "u", "api", "v1"}
+	routes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		depth := 1 + r.Intn(4)
+		for d := 0; d < depth; d++ {
+			b.WriteByte('/')
+			switch k := r.Intn(10); {
+			case k < 6:
+				b.WriteString(statics[r.Intn(len(statics))])
+			case k < 9:
+				fmt.Fprintf(&b, ":p%d", r.Intn(3))
+			default:
+				fmt.Fprintf(&b, "*c%d", r.Intn(2))
+			}
+		}
+		if r.Intn(4) == 0 && !strings.Contains(b.String(), "*") {
+			b.WriteByte('/')
+		}
+		routes = append(routes, b.String())
+	}
+	return routes
+}
+
+func TestTreeRandomRoutes(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		routes := randomRoutes(rand.New(rand.NewSource(seed)), 12)
+		if _, err := CheckRouteTree(routes); err != nil {
+			t.Fatalf("seed %d, routes %q: %v", seed, routes, err)
+		}
+	}
+}
+
+func TestCheckRouteTree(t *testing.T) {
+	accepted, err := CheckRouteTree([]string{"/", "/users/:id", "/users/:name", "/src/*filepath", "invalid", "/users/:id/posts/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/", "/users/:id", "/src/*filepath", "/users/:id/posts/"}
+	if !reflect.DeepEqual(accepted, want) {
+		t.Errorf("accepted routes %q, want %q", accepted, want)
+	}
+}
+
+func TestTreeTrailingSlashRedirectAfterBacktrack(t *testing.T) {
+	tree := &node{}
+	for _, route := range []string{"/a/", "/:p/api/", "/ab/:q"} {
+		tree.addRoute(route, fakeHandler(route))
+	}
+
+	value := tree.getValue("/a", getParams(), getSkippedNodes(), false)
+	if value.handlers != nil || !value.tsr {
+		t.Errorf("expected trailing slash recommendation for /a, got handlers=%v tsr=%v", value.handlers != nil, value.tsr)
+	}
+}
+
+func TestUnescapeKeepSlash(t *testing.T) {
+	tests := []struct {
+		in, out string
+		err     bool
+	}{
+		{"a%20b", "a b", false},
+		{"a%2Fb%2fc", "a%2Fb%2Fc", false},
+		{"a%252F", "a%252F", false},
+		{"%25%41%2F", "%25A%2F", false},
+		{"a+b", "a b", false},
+		{"%zz%2F", "", true},
+		{"%2", "", true},
+	}
+	for _, tt := range tests {
+		out, err := unescapeKeepSlash(tt.in)
+		if (err != nil) != tt.err || out != tt.out {
+			t.Errorf("unescapeKeepSlash(%q) = %q, %v; want %q, err=%v", tt.in, out, err, tt.out, tt.err)
+		}
+	}
+}
+
+func FuzzTree(f *testing.F) {
+	f.Add("/users/:id", "/users/new", "/src/*filepath")
+	f.Add("/a/", "/a/:b", "/ab/*c")
+	f.Add("/:a/b", "/a/:b/", "/a/b/c")
+	f.Fuzz(func(t *testing.T, r1, r2, r3 string) {
+		if strings.Contains(r1+r2+r3, "\x00") {
+			t.Skip()
+		}
+		if _, err := CheckRouteTree([]string{r1, r2, r3}); err != nil {
+			t.Fatalf("routes %q: %v", []string{r1, r2, r3}, err)
+		}
+	})
+}
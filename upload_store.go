@@ -0,0 +1,241 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// UploadStore 保存上传文件的存储，例如本地目录、S3或者GCS，
+// Save从r中读取文件内容并返回文件的访问地址，r返回错误时需要放弃已经写入的内容
+type UploadStore interface {
+	Save(ctx context.Context, name string, r io.Reader) (*url.URL, error)
+}
+
+// UploadedFile 通过StreamUploads保存的文件
+type UploadedFile struct {
+	// form中的字段名
+	Field string
+	// 客户端提供的文件名
+	Filename string
+	// 保存的字节数
+	Size int64
+	// UploadStore返回的访问地址
+	URL *url.URL
+}
+
+// 上传文件名不合法时返回的错误
+var ErrInvalidUploadName = errors.New("gin: invalid upload file name")
+
+// 将上传的form file保存到store，使用去掉目录之后的文件名
+func (c *Context) SaveUploadedFileTo(store UploadStore, file *multipart.FileHeader) (*url.URL, error) {
+	name, err := uploadName(file.Filename)
+	if err != nil {
+		return nil, err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return store.Save(c.Request.Context(), name, src)
+}
+
+// 以流的方式读取multipart body，文件直接写入store而不经过内存或者临时文件，返回普通字段的值和保存的文件；
+// 遵守Engine.MultipartLimits，普通字段的总大小不超过Engine.MaxMultipartMemory。
+// 不能与FormFile、MultipartForm等解析整个form的方法同时使用
+func (c *Context) StreamUploads(store UploadStore) (url.Values, []UploadedFile, error) {
+	// 没有Engine时不限制文件，普通字段使用默认的内存大小
+	var limits MultipartLimits
+	valueBudget := int64(defaultMultipartMemory)
+	if c.engine != nil {
+		limits, valueBudget = c.engine.MultipartLimits, c.engine.MaxMultipartMemory
+	}
+	if limits.MaxTotalSize > 0 && c.Request.Body != nil {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxTotalSize)
+	}
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := url.Values{}
+	var files []UploadedFile
+	parts := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return values, files, nil
+		}
+		if err != nil {
+			return values, files, streamUploadError(err, limits)
+		}
+		parts++
+		if limits.MaxParts > 0 && parts > limits.MaxParts {
+			part.Close()
+			return values, files, fmt.Errorf("%w: limit is %d", ErrMultipartTooManyParts, limits.MaxParts)
+		}
+
+		field := part.FormName()
+		if field == "" {
+			part.Close()
+			continue
+		}
+		if part.FileName() == "" {
+			b, err := io.ReadAll(io.LimitReader(part, valueBudget+1))
+			part.Close()
+			if err != nil {
+				return values, files, streamUploadError(err, limits)
+			}
+			valueBudget -= int64(len(b))
+			if valueBudget < 0 {
+				return values, files, multipart.ErrMessageTooLarge
+			}
+			values.Add(field, string(b))
+			continue
+		}
+
+		name, err := uploadName(part.FileName())
+		if err != nil {
+			part.Close()
+			return values, files, err
+		}
+		r := &uploadCountingReader{r: part, limit: limits.MaxFileSize, filename: part.FileName()}
+		u, err := store.Save(c.Request.Context(), name, r)
+		part.Close()
+		if err != nil {
+			return values, files, streamUploadError(err, limits)
+		}
+		files = append(files, UploadedFile{Field: field, Filename: part.FileName(), Size: r.n, URL: u})
+	}
+}
+
+// 将body超过MaxTotalSize的错误转换为ErrMultipartTooLarge
+func streamUploadError(err error, limits MultipartLimits) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrMultipartTooLarge, limits.MaxTotalSize)
+	}
+	return err
+}
+
+// 返回去掉目录之后的文件名，不能为空或者为"."、".."
+func uploadName(filename string) (string, error) {
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if name == "." || name == ".." || name == "/" || strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUploadName, filename)
+	}
+	return name, nil
+}
+
+// 统计读取的字节数，超过limit时返回ErrMultipartFileTooLarge
+type uploadCountingReader struct {
+	r        io.Reader
+	n        int64
+	limit    int64
+	filename string
+}
+
+// 实现io.Reader Read函数接口
+func (r *uploadCountingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.limit > 0 && r.n > r.limit {
+		return n, fmt.Errorf("%w: %q is larger than %d bytes", ErrMultipartFileTooLarge, r.filename, r.limit)
+	}
+	return n, err
+}
+
+// DirUploadStore 将上传文件保存到本地目录的UploadStore
+type DirUploadStore struct {
+	// 保存文件的目录
+	Root string
+	// 不为nil时返回BaseURL与文件名拼接的地址，否则返回file://地址
+	BaseURL *url.URL
+}
+
+// 接口实现校验
+var _ UploadStore = DirUploadStore{}
+
+// 实现UploadStore Save函数接口，不覆盖已有的文件，同名时在文件名中添加序号，写入失败时删除已经写入的文件
+func (s DirUploadStore) Save(ctx context.Context, name string, r io.Reader) (*url.URL, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUploadName, name)
+	}
+	dst := filepath.Join(s.Root, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return nil, err
+	}
+	out, clean, err := createUploadFile(dst, clean)
+	if err != nil {
+		return nil, err
+	}
+	dst = out.Name()
+	_, err = io.Copy(out, readerWithContext{ctx: ctx, r: r})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst) //nolint: errcheck
+		return nil, err
+	}
+
+	if s.BaseURL != nil {
+		return s.BaseURL.JoinPath(clean), nil
+	}
+	abs, err := filepath.Abs(dst)
+	if err != nil {
+		return nil, err
+	}
+	return &url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}, nil
+}
+
+// 上传文件同名时尝试的最大序号
+const maxUploadNameAttempts = 1000
+
+// 创建不存在的文件，不覆盖已有的文件；dst已经存在时在扩展名之前添加-1、-2等序号，
+// 返回打开的文件和对应的URL路径
+func createUploadFile(dst, urlPath string) (*os.File, string, error) {
+	ext := path.Ext(urlPath)
+	dstBase, urlBase := strings.TrimSuffix(dst, ext), strings.TrimSuffix(urlPath, ext)
+	name, p := dst, urlPath
+	for i := 1; ; i++ {
+		out, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+		if err == nil {
+			return out, p, nil
+		}
+		if !os.IsExist(err) || i > maxUploadNameAttempts {
+			return nil, "", err
+		}
+		suffix := "-" + strconv.Itoa(i) + ext
+		name, p = dstBase+suffix, urlBase+suffix
+	}
+}
+
+// 在ctx结束之后停止读取
+type readerWithContext struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// 实现io.Reader Read函数接口
+func (r readerWithContext) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
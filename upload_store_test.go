@@ -0,0 +1,216 @@
+// Copyright 2026 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 保存在内存中的UploadStore
+type memoryUploadStore struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (s *memoryUploadStore) Save(_ context.Context, name string, r io.Reader) (*url.URL, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		s.files = make(map[string]string)
+	}
+	s.files[name] = string(b)
+	return &url.URL{Scheme: "mem", Path: "/" + name}, nil
+}
+
+// 创建包含字段和文件的multipart请求
+func newUploadRequest(t *testing.T, fields map[string]string, files map[string]string) *http.Request {
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	for k, v := range fields {
+		assert.NoError(t, mw.WriteField(k, v))
+	}
+	for name, content := range files {
+		w, err := mw.CreateFormFile("file", name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestSaveUploadedFileTo(t *testing.T) {
+	store := &memoryUploadStore{}
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request = newUploadRequest(t, nil, map[string]string{"../../etc/report.txt": "hello"})
+
+	fh, err := c.FormFile("file")
+	assert.NoError(t, err)
+	u, err := c.SaveUploadedFileTo(store, fh)
+	assert.NoError(t, err)
+	assert.Equal(t, "mem:///report.txt", u.String())
+	assert.Equal(t, map[string]string{"report.txt": "hello"}, store.files)
+
+	fh.Filename = ".."
+	_, err = c.SaveUploadedFileTo(store, fh)
+	assert.ErrorIs(t, err, ErrInvalidUploadName)
+}
+
+func TestStreamUploads(t *testing.T) {
+	store := &memoryUploadStore{}
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request = newUploadRequest(t, map[string]string{"title": "docs"}, map[string]string{"a.txt": "aaa"})
+
+	values, files, err := c.StreamUploads(store)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"title": {"docs"}}, values)
+	assert.Len(t, files, 1)
+	assert.Equal(t, UploadedFile{Field: "file", Filename: "a.txt", Size: 3, URL: &url.URL{Scheme: "mem", Path: "/a.txt"}}, files[0])
+	assert.Equal(t, "aaa", store.files["a.txt"])
+	// 文件没有经过ParseMultipartForm
+	assert.Empty(t, c.Request.MultipartForm.File)
+}
+
+func TestStreamUploadsWithoutEngine(t *testing.T) {
+	store := &memoryUploadStore{}
+	c := createContextWithoutEngine(httptest.NewRecorder())
+	c.Request = newUploadRequest(t, map[string]string{"title": "docs"}, map[string]string{"a.txt": "aaa"})
+
+	values, files, err := c.StreamUploads(store)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"title": {"docs"}}, values)
+	assert.Len(t, files, 1)
+	assert.Equal(t, "aaa", store.files["a.txt"])
+}
+
+func TestStreamUploadsLimits(t *testing.T) {
+	c, r := CreateTestContext(httptest.NewRecorder())
+	r.MultipartLimits = MultipartLimits{MaxFileSize: 2}
+	c.Request = newUploadRequest(t, nil, map[string]string{"a.txt": "aaa"})
+	_, _, err := c.StreamUploads(&memoryUploadStore{})
+	assert.ErrorIs(t, err, ErrMultipartFileTooLarge)
+
+	c, r = CreateTestContext(httptest.NewRecorder())
+	r.MultipartLimits = MultipartLimits{MaxParts: 1}
+	c.Request = newUploadRequest(t, map[string]string{"title": "docs"}, map[string]string{"a.txt": "aaa"})
+	_, _, err = c.StreamUploads(&memoryUploadStore{})
+	assert.ErrorIs(t, err, ErrMultipartTooManyParts)
+
+	c, r = CreateTestContext(httptest.NewRecorder())
+	r.MultipartLimits = MultipartLimits{MaxTotalSize: 10}
+	c.Request = newUploadRequest(t, nil, map[string]string{"a.txt": "aaa"})
+	_, _, err = c.StreamUploads(&memoryUploadStore{})
+	assert.ErrorIs(t, err, ErrMultipartTooLarge)
+
+	c, r = CreateTestContext(httptest.NewRecorder())
+	r.MaxMultipartMemory = 3
+	c.Request = newUploadRequest(t, map[string]string{"title": "docs"}, nil)
+	_, _, err = c.StreamUploads(&memoryUploadStore{})
+	assert.ErrorIs(t, err, multipart.ErrMessageTooLarge)
+
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	_, _, err = c.StreamUploads(&memoryUploadStore{})
+	assert.Error(t, err)
+}
+
+func TestDirUploadStore(t *testing.T) {
+	dir := t.TempDir()
+	store := DirUploadStore{Root: dir}
+
+	u, err := store.Save(context.Background(), "../nested/a.txt", bytes.NewBufferString("content"))
+	assert.NoError(t, err)
+	assert.Equal(t, "file", u.Scheme)
+	b, err := os.ReadFile(filepath.Join(dir, "nested", "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(b))
+
+	base, _ := url.Parse("https://cdn.example.com/uploads")
+	store.BaseURL = base
+	u, err = store.Save(context.Background(), "b.txt", bytes.NewBufferString("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/uploads/b.txt", u.String())
+
+	_, err = store.Save(context.Background(), "..", bytes.NewBufferString("x"))
+	assert.ErrorIs(t, err, ErrInvalidUploadName)
+
+	// 同名文件不会被覆盖
+	u, err = store.Save(context.Background(), "b.txt", bytes.NewBufferString("b2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/uploads/b-1.txt", u.String())
+	b, err = os.ReadFile(filepath.Join(dir, "b.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "b", string(b))
+	b, err = os.ReadFile(filepath.Join(dir, "b-1.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "b2", string(b))
+	store.BaseURL = nil
+
+	// 读取失败时删除已经写入的文件
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = store.Save(ctx, "c.txt", bytes.NewBufferString("c"))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = os.Stat(filepath.Join(dir, "c.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	// 并发保存同名文件时各自写入不同的文件
+	var wg sync.WaitGroup
+	urls := make([]*url.URL, 5)
+	for i := range urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			urls[i], _ = store.Save(context.Background(), "d.txt", bytes.NewBufferString("d"))
+		}(i)
+	}
+	wg.Wait()
+	seen := map[string]bool{}
+	for _, u := range urls {
+		assert.NotNil(t, u)
+		seen[u.String()] = true
+	}
+	assert.Len(t, seen, len(urls))
+}
+
+func TestStreamUploadsToDir(t *testing.T) {
+	dir := t.TempDir()
+	router := New()
+	router.POST("/upload", func(c *Context) {
+		_, files, err := c.StreamUploads(DirUploadStore{Root: dir})
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, "%s %d", files[0].Filename, files[0].Size)
+	})
+
+	req := newUploadRequest(t, nil, map[string]string{"photo.jpg": "jpegdata"})
+	req.URL.Path = "/upload"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "photo.jpg 8", w.Body.String())
+	b, err := os.ReadFile(filepath.Join(dir, "photo.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpegdata", string(b))
+}